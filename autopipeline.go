@@ -0,0 +1,190 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AutoPipelineOptions configures automatic pipelining, in which commands issued
+// concurrently by different goroutines are collected into batches and each batch
+// is sent as a pipeline on one connection.
+type AutoPipelineOptions struct {
+	// Maximum number of commands in a batch. The batch is sent as soon as it is full.
+	// Default is 100 commands.
+	MaxBatchSize int
+	// Maximum amount of time a command waits for other commands to join the batch.
+	// Default is 100 microseconds.
+	MaxDelay time.Duration
+}
+
+// AutoPipelineStats contains automatic pipelining stats.
+type AutoPipelineStats struct {
+	Batches     uint64 // number of sent batches
+	FullBatches uint64 // number of batches sent because MaxBatchSize was reached
+	Cmds        uint64 // number of commands sent in batches
+
+	WaitTime time.Duration // total time commands waited for their batches to be sent
+	ExecTime time.Duration // total time spent processing batches
+}
+
+type autoPipelineReq struct {
+	ctx      context.Context
+	cmd      Cmder
+	queuedAt time.Time
+	done     chan struct{}
+}
+
+type autoPipeliner struct {
+	batches     uint64 // atomic
+	fullBatches uint64 // atomic
+	cmds        uint64 // atomic
+	waitTime    int64  // atomic
+	execTime    int64  // atomic
+
+	opt  AutoPipelineOptions
+	exec func(ctx context.Context, cmds []Cmder) error
+
+	mu    sync.Mutex
+	queue []*autoPipelineReq
+	timer *time.Timer
+}
+
+func newAutoPipeliner(
+	opt *AutoPipelineOptions, exec func(ctx context.Context, cmds []Cmder) error,
+) *autoPipeliner {
+	p := &autoPipeliner{
+		opt:  *opt,
+		exec: exec,
+	}
+	if p.opt.MaxBatchSize <= 0 {
+		p.opt.MaxBatchSize = 100
+	}
+	if p.opt.MaxDelay <= 0 {
+		p.opt.MaxDelay = 100 * time.Microsecond
+	}
+	return p
+}
+
+// autoPipelineable reports whether the cmd can be sent in a batch.
+// Blocking commands would delay the other commands of the batch.
+func autoPipelineable(cmd Cmder) bool {
+	return cmd.readTimeout() == nil
+}
+
+func (p *autoPipeliner) process(ctx context.Context, cmd Cmder) error {
+	req := &autoPipelineReq{
+		ctx:      ctx,
+		cmd:      cmd,
+		queuedAt: time.Now(),
+		done:     make(chan struct{}),
+	}
+
+	var batch []*autoPipelineReq
+
+	p.mu.Lock()
+	p.queue = append(p.queue, req)
+	switch {
+	case len(p.queue) >= p.opt.MaxBatchSize:
+		batch = p.takeQueueLocked()
+	case len(p.queue) == 1:
+		if p.timer == nil {
+			p.timer = time.AfterFunc(p.opt.MaxDelay, p.flush)
+		} else {
+			p.timer.Reset(p.opt.MaxDelay)
+		}
+	}
+	p.mu.Unlock()
+
+	if batch != nil {
+		atomic.AddUint64(&p.fullBatches, 1)
+		p.execute(batch)
+	}
+
+	select {
+	case <-req.done:
+		return cmd.Err()
+	case <-ctx.Done():
+		if p.dequeue(req) {
+			return ctx.Err()
+		}
+		// The command is already being sent.
+		<-req.done
+		return cmd.Err()
+	}
+}
+
+func (p *autoPipeliner) takeQueueLocked() []*autoPipelineReq {
+	batch := p.queue
+	p.queue = nil
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	return batch
+}
+
+func (p *autoPipeliner) dequeue(req *autoPipelineReq) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, r := range p.queue {
+		if r == req {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			if len(p.queue) == 0 && p.timer != nil {
+				p.timer.Stop()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (p *autoPipeliner) flush() {
+	p.mu.Lock()
+	batch := p.takeQueueLocked()
+	p.mu.Unlock()
+
+	if len(batch) > 0 {
+		p.execute(batch)
+	}
+}
+
+func (p *autoPipeliner) execute(batch []*autoPipelineReq) {
+	start := time.Now()
+
+	cmds := make([]Cmder, 0, len(batch))
+	for _, req := range batch {
+		atomic.AddInt64(&p.waitTime, int64(start.Sub(req.queuedAt)))
+		if err := req.ctx.Err(); err != nil {
+			req.cmd.SetErr(err)
+			continue
+		}
+		cmds = append(cmds, req.cmd)
+	}
+
+	if len(cmds) > 0 {
+		// The batch is shared by the commands, so it is not bound to any command context.
+		if err := p.exec(context.Background(), cmds); err != nil {
+			setCmdsErr(cmds, err)
+		}
+
+		atomic.AddUint64(&p.batches, 1)
+		atomic.AddUint64(&p.cmds, uint64(len(cmds)))
+		atomic.AddInt64(&p.execTime, int64(time.Since(start)))
+	}
+
+	for _, req := range batch {
+		close(req.done)
+	}
+}
+
+func (p *autoPipeliner) stats() *AutoPipelineStats {
+	return &AutoPipelineStats{
+		Batches:     atomic.LoadUint64(&p.batches),
+		FullBatches: atomic.LoadUint64(&p.fullBatches),
+		Cmds:        atomic.LoadUint64(&p.cmds),
+		WaitTime:    time.Duration(atomic.LoadInt64(&p.waitTime)),
+		ExecTime:    time.Duration(atomic.LoadInt64(&p.execTime)),
+	}
+}
@@ -0,0 +1,94 @@
+package redis_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("AutoPipeline", func() {
+	ctx := context.Background()
+	var client *redis.Client
+
+	BeforeEach(func() {
+		opt := redisOptions()
+		opt.AutoPipeline = &redis.AutoPipelineOptions{
+			MaxBatchSize: 10,
+			MaxDelay:     10 * time.Millisecond,
+		}
+		client = redis.NewClient(opt)
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("batches concurrent commands", func() {
+		const n = 100
+
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				key := "key" + strconv.Itoa(i)
+				Expect(client.Set(ctx, key, i, 0).Err()).NotTo(HaveOccurred())
+				Expect(client.Get(ctx, key).Val()).To(Equal(strconv.Itoa(i)))
+			}(i)
+		}
+		wg.Wait()
+
+		stats := client.AutoPipelineStats()
+		Expect(stats.Cmds).To(Equal(uint64(2*n + 1)))
+		Expect(stats.Batches).To(BeNumerically("<", 2*n))
+		Expect(stats.FullBatches).To(BeNumerically(">", 0))
+	})
+
+	It("flushes incomplete batches after MaxDelay", func() {
+		start := time.Now()
+		Expect(client.Ping(ctx).Err()).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically(">=", 10*time.Millisecond))
+	})
+
+	It("returns errors of each command", func() {
+		Expect(client.Set(ctx, "key", "hello", 0).Err()).NotTo(HaveOccurred())
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			Expect(client.Incr(ctx, "key").Err()).To(MatchError("ERR value is not an integer or out of range"))
+		}()
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+		}()
+		wg.Wait()
+	})
+
+	It("does not batch blocking commands", func() {
+		before := client.AutoPipelineStats().Cmds
+		err := client.BLPop(ctx, 100*time.Millisecond, "list").Err()
+		Expect(err).To(Equal(redis.Nil))
+		Expect(client.AutoPipelineStats().Cmds).To(Equal(before))
+	})
+
+	It("respects context cancellation of queued commands", func() {
+		ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+		defer cancel()
+
+		err := client.Ping(ctx).Err()
+		Expect(err).To(Equal(context.DeadlineExceeded))
+	})
+})
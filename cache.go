@@ -0,0 +1,635 @@
+package redis
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/pool"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+var (
+	errClientCacheRESP2 = errors.New("redis: client-side caching requires RESP3 protocol")
+	errStaleTracking    = errors.New("redis: client-side cache tracker is lost")
+)
+
+// ClientCacheOptions configures server-assisted client-side caching.
+// The client enables CLIENT TRACKING on every connection and redirects the
+// invalidation messages to a dedicated connection, which drops the cached replies
+// as soon as the messages arrive. The dedicated connection is checked with PING.
+// If it is lost, the cache is flushed and the tracked connections are dropped
+// when they are returned to the pool, because their messages are lost.
+// The replies read by a connection are dropped when the connection is closed.
+// For more information - https://redis.io/docs/manual/client-side-caching/
+type ClientCacheOptions struct {
+	// Maximum number of cached replies. The least recently used replies
+	// are evicted when the limit is reached.
+	// Default is 10000 replies.
+	MaxEntries int
+	// Maximum amount of time a reply is served from the cache.
+	// Default is 0, replies are cached until invalidated or evicted.
+	TTL time.Duration
+
+	// Enables the broadcasting mode, in which the server sends invalidation
+	// messages for all keys matching Prefixes instead of remembering
+	// the keys read by each connection.
+	BCast bool
+	// Key prefixes the broadcasting mode is limited to.
+	Prefixes []string
+
+	// Interval of the PING commands sent on the connection that receives
+	// the invalidation messages. The connection is considered lost if it does not
+	// receive anything for two intervals.
+	// Default is 5 seconds.
+	PingInterval time.Duration
+
+	// Read-only commands whose replies are cached. All arguments of MGET and EXISTS
+	// are keys, other commands must take a single key as the first argument.
+	// Default is GET, GETRANGE, STRLEN, MGET, EXISTS, TYPE, HGET, HMGET, HGETALL,
+	// HKEYS, HVALS, HLEN, HEXISTS, SMEMBERS, SISMEMBER, SCARD, LRANGE, LINDEX, LLEN,
+	// ZRANGE, ZSCORE and ZCARD.
+	Commands []string
+}
+
+var defaultCacheCommands = []string{
+	"get", "getrange", "strlen", "mget", "exists", "type",
+	"hget", "hmget", "hgetall", "hkeys", "hvals", "hlen", "hexists",
+	"smembers", "sismember", "scard",
+	"lrange", "lindex", "llen",
+	"zrange", "zscore", "zcard",
+}
+
+// CacheStats contains client-side cache stats.
+type CacheStats struct {
+	Hits          uint32 // number of times a reply was found in the cache
+	Misses        uint32 // number of times a cacheable command was sent to the server
+	Evictions     uint32 // number of replies evicted because of MaxEntries
+	Invalidations uint32 // number of replies dropped because of invalidation messages
+
+	Entries uint32 // number of cached replies
+}
+
+type cacheEntry struct {
+	key       string
+	redisKeys []string
+	val       interface{}
+	err       error
+	expiresAt time.Time
+	// cn is the connection that read the reply and receives its invalidation.
+	cn *pool.Conn
+}
+
+type clientCache struct {
+	opt      *ClientCacheOptions
+	commands map[string]bool
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	keys    map[string]map[string]struct{} // redis key -> cache keys
+
+	// epoch is incremented by every invalidation. The replies of the reads started
+	// before the invalidation of their keys or before a flush are not cached.
+	epoch       uint64
+	flushedAt   uint64
+	reading     map[string]int    // redis key -> number of reads in flight
+	invalidated map[string]uint64 // redis key -> epoch, for the keys in reading
+
+	// gen is incremented when the tracker is lost. The replies are cached only
+	// if they are read by a connection tracked with the current tracker.
+	gen      uint32
+	tracked  map[*pool.Conn]uint32
+	connKeys map[*pool.Conn]map[string]struct{} // cn -> cache keys of the replies it read
+
+	trackerMu sync.Mutex
+	tracker   *pool.Conn
+	trackerID int64
+
+	hits          uint32 // atomic
+	misses        uint32 // atomic
+	evictions     uint32 // atomic
+	invalidations uint32 // atomic
+}
+
+func newClientCache(clOpt *ClientCacheOptions) *clientCache {
+	opt := *clOpt
+	if opt.MaxEntries == 0 {
+		opt.MaxEntries = 10000
+	}
+	if opt.Commands == nil {
+		opt.Commands = defaultCacheCommands
+	}
+	if opt.PingInterval == 0 {
+		opt.PingInterval = 5 * time.Second
+	}
+
+	c := &clientCache{
+		opt:      &opt,
+		commands: make(map[string]bool, len(opt.Commands)),
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		keys:     make(map[string]map[string]struct{}),
+
+		reading:     make(map[string]int),
+		invalidated: make(map[string]uint64),
+		tracked:     make(map[*pool.Conn]uint32),
+		connKeys:    make(map[*pool.Conn]map[string]struct{}),
+	}
+	for _, name := range opt.Commands {
+		c.commands[strings.ToLower(name)] = true
+	}
+	return c
+}
+
+func (c *clientCache) trackingArgs(redirect int64) []interface{} {
+	args := make([]interface{}, 0, 6+2*len(c.opt.Prefixes))
+	args = append(args, "client", "tracking", "on", "redirect", redirect)
+	if c.opt.BCast {
+		args = append(args, "bcast")
+		for _, prefix := range c.opt.Prefixes {
+			args = append(args, "prefix", prefix)
+		}
+	}
+	return args
+}
+
+func (c *clientCache) cmdKeys(cmd Cmder) []string {
+	args := cmd.Args()
+	if len(args) < 2 {
+		return nil
+	}
+	switch cmd.Name() {
+	case "mget", "exists":
+		keys := make([]string, len(args)-1)
+		for i := range keys {
+			keys[i] = cmd.stringArg(i + 1)
+		}
+		return keys
+	default:
+		return []string{cmd.stringArg(1)}
+	}
+}
+
+func (c *clientCache) cacheKey(cmd Cmder) string {
+	if !c.commands[cmd.Name()] || !isCacheableCmd(cmd) {
+		return ""
+	}
+
+	var b strings.Builder
+	for i := range cmd.Args() {
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		b.WriteString(cmd.stringArg(i))
+	}
+	return b.String()
+}
+
+// load sets the cached reply on the cmd and reports whether the reply was found.
+func (c *clientCache) load(cmd Cmder) bool {
+	key := c.cacheKey(cmd)
+	if key == "" {
+		return false
+	}
+
+	c.mu.Lock()
+	el, ok := c.entries[key]
+	if ok {
+		e := el.Value.(*cacheEntry)
+		if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+			c.remove(el)
+			ok = false
+		} else {
+			c.lru.MoveToFront(el)
+			setCachedVal(cmd, e.val)
+			cmd.SetErr(e.err)
+		}
+	}
+	c.mu.Unlock()
+
+	if ok {
+		atomic.AddUint32(&c.hits, 1)
+	} else {
+		atomic.AddUint32(&c.misses, 1)
+	}
+	return ok
+}
+
+// begin registers the reads of the cacheable cmds before they are sent
+// and returns the epoch that must be passed to store.
+func (c *clientCache) begin(cmds ...Cmder) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, cmd := range cmds {
+		if c.cacheKey(cmd) == "" {
+			continue
+		}
+		for _, k := range c.cmdKeys(cmd) {
+			c.reading[k]++
+		}
+	}
+	return c.epoch
+}
+
+// store caches the reply of the cmd read by the cn. It must be called once
+// for every cmd passed to begin, even if the cmd failed.
+func (c *clientCache) store(cn *pool.Conn, epoch uint64, cmd Cmder, err error) {
+	key := c.cacheKey(cmd)
+	if key == "" {
+		return
+	}
+	redisKeys := c.cmdKeys(cmd)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stale := epoch < c.flushedAt
+	for _, k := range redisKeys {
+		if c.invalidated[k] > epoch {
+			stale = true
+		}
+		if c.reading[k]--; c.reading[k] <= 0 {
+			delete(c.reading, k)
+			delete(c.invalidated, k)
+		}
+	}
+
+	if stale || (err != nil && err != Nil) {
+		return
+	}
+	if gen, ok := c.tracked[cn]; !ok || gen != c.gen {
+		return
+	}
+
+	e := &cacheEntry{
+		key:       key,
+		redisKeys: redisKeys,
+		val:       cachedVal(cmd),
+		err:       err,
+		cn:        cn,
+	}
+	if c.opt.TTL > 0 {
+		e.expiresAt = time.Now().Add(c.opt.TTL)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	for _, k := range e.redisKeys {
+		m, ok := c.keys[k]
+		if !ok {
+			m = make(map[string]struct{})
+			c.keys[k] = m
+		}
+		m[key] = struct{}{}
+	}
+	connKeys, ok := c.connKeys[cn]
+	if !ok {
+		connKeys = make(map[string]struct{})
+		c.connKeys[cn] = connKeys
+	}
+	connKeys[key] = struct{}{}
+
+	for c.lru.Len() > c.opt.MaxEntries {
+		c.remove(c.lru.Back())
+		atomic.AddUint32(&c.evictions, 1)
+	}
+}
+
+func (c *clientCache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*cacheEntry)
+	delete(c.entries, e.key)
+	if m, ok := c.connKeys[e.cn]; ok {
+		delete(m, e.key)
+		if len(m) == 0 {
+			delete(c.connKeys, e.cn)
+		}
+	}
+	for _, k := range e.redisKeys {
+		if m, ok := c.keys[k]; ok {
+			delete(m, e.key)
+			if len(m) == 0 {
+				delete(c.keys, k)
+			}
+		}
+	}
+}
+
+func (c *clientCache) invalidate(keys []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	for _, k := range keys {
+		if c.reading[k] > 0 {
+			c.invalidated[k] = c.epoch
+		}
+		for key := range c.keys[k] {
+			if el, ok := c.entries[key]; ok {
+				c.remove(el)
+				atomic.AddUint32(&c.invalidations, 1)
+			}
+		}
+	}
+}
+
+// flush drops all cached replies. It is called when the server flushes its databases
+// or the invalidation messages can be lost.
+func (c *clientCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.flushLocked()
+}
+
+func (c *clientCache) flushLocked() {
+	c.epoch++
+	c.flushedAt = c.epoch
+	atomic.AddUint32(&c.invalidations, uint32(c.lru.Len()))
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.keys = make(map[string]map[string]struct{})
+	c.connKeys = make(map[*pool.Conn]map[string]struct{})
+}
+
+// handlePush handles the invalidate push notifications.
+func (c *clientCache) handlePush(push []interface{}) error {
+	if len(push) < 2 || push[1] == nil {
+		c.flush()
+		return nil
+	}
+
+	items, _ := push[1].([]interface{})
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		if key, ok := item.(string); ok {
+			keys = append(keys, key)
+		}
+	}
+	c.invalidate(keys)
+	return nil
+}
+
+func (c *clientCache) stats() *CacheStats {
+	c.mu.Lock()
+	entries := uint32(c.lru.Len())
+	c.mu.Unlock()
+
+	return &CacheStats{
+		Hits:          atomic.LoadUint32(&c.hits),
+		Misses:        atomic.LoadUint32(&c.misses),
+		Evictions:     atomic.LoadUint32(&c.evictions),
+		Invalidations: atomic.LoadUint32(&c.invalidations),
+		Entries:       entries,
+	}
+}
+
+func isCacheableCmd(cmd Cmder) bool {
+	switch cmd.(type) {
+	case *StringCmd, *StatusCmd, *IntCmd, *BoolCmd, *FloatCmd,
+		*SliceCmd, *StringSliceCmd, *MapStringStringCmd, *StringStructMapCmd, *ZSliceCmd:
+		return true
+	}
+	return false
+}
+
+// cachedVal returns a copy of the cmd reply that is safe to share between cmds.
+func cachedVal(cmd Cmder) interface{} {
+	switch cmd := cmd.(type) {
+	case *StringCmd:
+		return cmd.val
+	case *StatusCmd:
+		return cmd.val
+	case *IntCmd:
+		return cmd.val
+	case *BoolCmd:
+		return cmd.val
+	case *FloatCmd:
+		return cmd.val
+	case *SliceCmd:
+		return append([]interface{}(nil), cmd.val...)
+	case *StringSliceCmd:
+		return append([]string(nil), cmd.val...)
+	case *ZSliceCmd:
+		return append([]Z(nil), cmd.val...)
+	case *MapStringStringCmd:
+		m := make(map[string]string, len(cmd.val))
+		for k, v := range cmd.val {
+			m[k] = v
+		}
+		return m
+	case *StringStructMapCmd:
+		m := make(map[string]struct{}, len(cmd.val))
+		for k := range cmd.val {
+			m[k] = struct{}{}
+		}
+		return m
+	}
+	return nil
+}
+
+func setCachedVal(cmd Cmder, val interface{}) {
+	switch cmd := cmd.(type) {
+	case *StringCmd:
+		cmd.val, _ = val.(string)
+	case *StatusCmd:
+		cmd.val, _ = val.(string)
+	case *IntCmd:
+		cmd.val, _ = val.(int64)
+	case *BoolCmd:
+		cmd.val, _ = val.(bool)
+	case *FloatCmd:
+		cmd.val, _ = val.(float64)
+	case *SliceCmd:
+		v, _ := val.([]interface{})
+		cmd.val = append([]interface{}(nil), v...)
+	case *StringSliceCmd:
+		v, _ := val.([]string)
+		cmd.val = append([]string(nil), v...)
+	case *ZSliceCmd:
+		v, _ := val.([]Z)
+		cmd.val = append([]Z(nil), v...)
+	case *MapStringStringCmd:
+		v, _ := val.(map[string]string)
+		cmd.val = make(map[string]string, len(v))
+		for k, s := range v {
+			cmd.val[k] = s
+		}
+	case *StringStructMapCmd:
+		v, _ := val.(map[string]struct{})
+		cmd.val = make(map[string]struct{}, len(v))
+		for k := range v {
+			cmd.val[k] = struct{}{}
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// track records the tracker generation the cn is tracked with.
+func (c *clientCache) track(cn *pool.Conn, gen uint32) {
+	c.mu.Lock()
+	c.tracked[cn] = gen
+	c.mu.Unlock()
+}
+
+// untrack drops the replies read by the closed connection, because the server
+// stops sending the invalidation messages for the keys it read.
+func (c *clientCache) untrack(cn *pool.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.tracked, cn)
+	for key := range c.connKeys[cn] {
+		if el, ok := c.entries[key]; ok {
+			c.remove(el)
+			atomic.AddUint32(&c.invalidations, 1)
+		}
+	}
+	delete(c.connKeys, cn)
+}
+
+// stale reports whether the cn was tracked with a lost tracker,
+// so the invalidation messages of its reads are lost.
+func (c *clientCache) stale(cn *pool.Conn) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	gen, ok := c.tracked[cn]
+	return ok && gen != c.gen
+}
+
+// trackerLost flushes the cache and starts a new tracker generation.
+// The connections tracked with the lost tracker become stale.
+func (c *clientCache) trackerLost(cn *pool.Conn) {
+	c.trackerMu.Lock()
+	if c.tracker == cn {
+		c.tracker = nil
+	}
+	c.trackerMu.Unlock()
+
+	c.mu.Lock()
+	c.gen++
+	c.flushLocked()
+	c.mu.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// initTracking enables CLIENT TRACKING on the connection with the invalidation
+// messages redirected to the tracker connection.
+func (c *baseClient) initTracking(ctx context.Context, cn *pool.Conn) error {
+	if c.opt.Protocol == 2 {
+		return errClientCacheRESP2
+	}
+
+	id, gen, err := c.cacheTracker(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn := newConn(c.opt, pool.NewSingleConnPool(c.connPool, cn))
+	if err := conn.Process(ctx, NewStatusCmd(ctx, c.cache.trackingArgs(id)...)); err != nil {
+		return err
+	}
+
+	c.cache.track(cn, gen)
+	cn.SetOnClose(func() {
+		c.cache.untrack(cn)
+	})
+	return nil
+}
+
+// cacheTracker returns the client ID of the tracker connection, starting it if needed,
+// and the tracker generation.
+func (c *baseClient) cacheTracker(ctx context.Context) (int64, uint32, error) {
+	cache := c.cache
+
+	cache.trackerMu.Lock()
+	defer cache.trackerMu.Unlock()
+
+	if cache.tracker == nil {
+		cn, err := c.newConn(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		cn.SetPushHandler(c.pushHandlers.handle)
+
+		conn := newConn(c.opt, pool.NewSingleConnPool(c.connPool, cn))
+		id, err := conn.ClientID(ctx).Result()
+		if err != nil {
+			_ = c.connPool.CloseConn(cn)
+			return 0, 0, err
+		}
+
+		cache.tracker = cn
+		cache.trackerID = id
+		go c.readInvalidations(cn)
+	}
+
+	cache.mu.Lock()
+	gen := cache.gen
+	cache.mu.Unlock()
+
+	return cache.trackerID, gen, nil
+}
+
+// readInvalidations handles the invalidation messages received by the tracker connection
+// until it fails or does not answer PING. Then the tracked connections become stale,
+// because their messages are lost, and the next connection starts a new tracker.
+func (c *baseClient) readInvalidations(cn *pool.Conn) {
+	ctx := context.Background()
+	interval := c.cache.opt.PingInterval
+
+	stop := make(chan struct{})
+	go c.pingTracker(cn, interval, stop)
+
+	for {
+		// The push replies are handled while the reply to PING is read.
+		err := cn.WithReader(ctx, 2*interval, func(rd *proto.Reader) error {
+			_, err := rd.ReadReply()
+			return err
+		})
+		if err != nil && !isRedisError(err) {
+			if !errors.Is(err, net.ErrClosed) && !errors.Is(err, pool.ErrClosed) {
+				internal.Logger.Printf(ctx, "redis: client-side cache tracker failed: %s", err)
+			}
+			break
+		}
+	}
+
+	close(stop)
+	_ = c.connPool.CloseConn(cn)
+	c.cache.trackerLost(cn)
+}
+
+func (c *baseClient) pingTracker(cn *pool.Conn, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ctx := context.Background()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		if err := cn.WithWriter(ctx, c.opt.WriteTimeout, func(wr *proto.Writer) error {
+			return writeCmds(wr, []Cmder{NewStatusCmd(ctx, "ping")})
+		}); err != nil {
+			// Unblock the reader.
+			_ = cn.Close()
+			return
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package redis_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("Client-side caching", func() {
+	ctx := context.Background()
+	var client, writer *redis.Client
+
+	newClient := func(cacheOpt *redis.ClientCacheOptions) *redis.Client {
+		opt := redisOptions()
+		opt.PoolSize = 1
+		opt.ClientCache = cacheOpt
+		return redis.NewClient(opt)
+	}
+
+	BeforeEach(func() {
+		writer = redis.NewClient(redisOptions())
+		Expect(writer.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		if client != nil {
+			Expect(client.Close()).NotTo(HaveOccurred())
+		}
+		Expect(writer.Close()).NotTo(HaveOccurred())
+	})
+
+	It("serves replies from the cache", func() {
+		client = newClient(&redis.ClientCacheOptions{})
+		Expect(writer.Set(ctx, "key", "hello", 0).Err()).NotTo(HaveOccurred())
+
+		for i := 0; i < 3; i++ {
+			val, err := client.Get(ctx, "key").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(val).To(Equal("hello"))
+		}
+
+		stats := client.CacheStats()
+		Expect(stats.Misses).To(Equal(uint32(1)))
+		Expect(stats.Hits).To(Equal(uint32(2)))
+		Expect(stats.Entries).To(Equal(uint32(1)))
+	})
+
+	It("caches nil replies", func() {
+		client = newClient(&redis.ClientCacheOptions{})
+
+		for i := 0; i < 2; i++ {
+			err := client.Get(ctx, "missing").Err()
+			Expect(err).To(Equal(redis.Nil))
+		}
+		Expect(client.CacheStats().Hits).To(Equal(uint32(1)))
+	})
+
+	It("invalidates modified keys", func() {
+		client = newClient(&redis.ClientCacheOptions{})
+		Expect(writer.HSet(ctx, "hash", "f1", "v1").Err()).NotTo(HaveOccurred())
+
+		m, err := client.HGetAll(ctx, "hash").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m).To(Equal(map[string]string{"f1": "v1"}))
+
+		Expect(writer.HSet(ctx, "hash", "f2", "v2").Err()).NotTo(HaveOccurred())
+
+		Eventually(func() map[string]string {
+			return client.HGetAll(ctx, "hash").Val()
+		}).Should(Equal(map[string]string{"f1": "v1", "f2": "v2"}))
+		Expect(client.CacheStats().Invalidations).To(BeNumerically(">=", 1))
+	})
+
+	It("invalidates replies while the connections are idle", func() {
+		client = newClient(&redis.ClientCacheOptions{})
+		Expect(writer.Set(ctx, "key", "a", 0).Err()).NotTo(HaveOccurred())
+		Expect(client.Get(ctx, "key").Val()).To(Equal("a"))
+
+		Expect(writer.Set(ctx, "key", "b", 0).Err()).NotTo(HaveOccurred())
+		Eventually(func() uint32 {
+			return client.CacheStats().Entries
+		}).Should(Equal(uint32(0)))
+		Expect(client.Get(ctx, "key").Val()).To(Equal("b"))
+	})
+
+	It("invalidates MGET replies by any key", func() {
+		client = newClient(&redis.ClientCacheOptions{})
+		Expect(writer.MSet(ctx, "k1", "v1", "k2", "v2").Err()).NotTo(HaveOccurred())
+
+		vals, err := client.MGet(ctx, "k1", "k2").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vals).To(Equal([]interface{}{"v1", "v2"}))
+
+		Expect(writer.Set(ctx, "k2", "v3", 0).Err()).NotTo(HaveOccurred())
+
+		Eventually(func() []interface{} {
+			return client.MGet(ctx, "k1", "k2").Val()
+		}).Should(Equal([]interface{}{"v1", "v3"}))
+	})
+
+	It("supports broadcasting mode", func() {
+		client = newClient(&redis.ClientCacheOptions{
+			BCast:    true,
+			Prefixes: []string{"user:"},
+		})
+		Expect(writer.Set(ctx, "user:1", "a", 0).Err()).NotTo(HaveOccurred())
+
+		Expect(client.Get(ctx, "user:1").Val()).To(Equal("a"))
+		Expect(writer.Set(ctx, "user:1", "b", 0).Err()).NotTo(HaveOccurred())
+
+		Eventually(func() string {
+			return client.Get(ctx, "user:1").Val()
+		}).Should(Equal("b"))
+	})
+
+	It("expires replies after TTL", func() {
+		client = newClient(&redis.ClientCacheOptions{TTL: 10 * time.Millisecond})
+		Expect(writer.Set(ctx, "key", "hello", 0).Err()).NotTo(HaveOccurred())
+
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+		time.Sleep(20 * time.Millisecond)
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+		Expect(client.CacheStats().Misses).To(Equal(uint32(2)))
+	})
+
+	It("caches the replies of AutoPipeline", func() {
+		opt := redisOptions()
+		opt.ClientCache = &redis.ClientCacheOptions{}
+		opt.AutoPipeline = &redis.AutoPipelineOptions{}
+		client = redis.NewClient(opt)
+		Expect(writer.Set(ctx, "key", "hello", 0).Err()).NotTo(HaveOccurred())
+
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+
+		stats := client.CacheStats()
+		Expect(stats.Misses).To(Equal(uint32(1)))
+		Expect(stats.Hits).To(Equal(uint32(1)))
+	})
+
+	It("flushes the cache when the tracker does not answer PING", func() {
+		client = newClient(&redis.ClientCacheOptions{PingInterval: 50 * time.Millisecond})
+		Expect(writer.Set(ctx, "key", "hello", 0).Err()).NotTo(HaveOccurred())
+
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+		Expect(client.CacheStats().Entries).To(Equal(uint32(1)))
+
+		Expect(writer.Do(ctx, "client", "pause", 500, "all").Err()).NotTo(HaveOccurred())
+		Eventually(func() uint32 {
+			return client.CacheStats().Entries
+		}, "1s", "10ms").Should(BeZero())
+
+		Eventually(func() string {
+			return client.Get(ctx, "key").Val()
+		}, "2s").Should(Equal("hello"))
+	})
+
+	It("does not cache other commands", func() {
+		client = newClient(&redis.ClientCacheOptions{Commands: []string{"HGET"}})
+		Expect(writer.Set(ctx, "key", "hello", 0).Err()).NotTo(HaveOccurred())
+
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+		Expect(client.CacheStats()).To(Equal(&redis.CacheStats{}))
+	})
+
+	It("requires RESP3", func() {
+		opt := redisOptions()
+		opt.Protocol = 2
+		opt.ClientCache = &redis.ClientCacheOptions{}
+		client = redis.NewClient(opt)
+
+		err := client.Get(ctx, "key").Err()
+		Expect(err).To(MatchError("redis: client-side caching requires RESP3 protocol"))
+	})
+})
@@ -0,0 +1,306 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by the commands when the circuit breaker is open.
+var ErrCircuitOpen = errors.New("redis: circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int32
+
+const (
+	// CircuitClosed allows the commands and counts their failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects the commands with ErrCircuitOpen until OpenTimeout elapses.
+	CircuitOpen
+	// CircuitHalfOpen allows HalfOpenRequests probe commands. The circuit is closed
+	// if they succeed and opened again if any fails.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOptions are the options of CircuitBreaker.
+type CircuitBreakerOptions struct {
+	// Name identifies the breaker in OnStateChange.
+	// The per-node breakers of ClusterClient and Ring are named by the node address.
+	Name string
+
+	// ConsecutiveFailures opens the circuit after that many consecutive failures.
+	// Default is 5 unless FailureRatio is set.
+	ConsecutiveFailures int
+	// FailureRatio opens the circuit when the ratio of failures in the Interval
+	// reaches the ratio, e.g. 0.5, after at least MinRequests commands.
+	FailureRatio float64
+	// MinRequests is the minimum number of commands in the Interval for FailureRatio.
+	// Default is 10.
+	MinRequests int
+	// Interval is the period after which the counts of the closed circuit are reset.
+	// Default is 1 minute. -1 disables resetting the counts.
+	Interval time.Duration
+
+	// OpenTimeout is the time the circuit stays open before it becomes half-open.
+	// Default is 30 seconds.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of probe commands allowed in the half-open state.
+	// Default is 1.
+	HalfOpenRequests int
+
+	// IsFailure reports whether the result of a command is a failure.
+	// By default, the network errors and timeouts are failures, but not the
+	// Redis error replies, e.g. Nil, because the server is reachable.
+	// The cancelled commands are never counted.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called when the state of the circuit changes.
+	OnStateChange func(name string, from, to CircuitState)
+}
+
+func (opt *CircuitBreakerOptions) init() {
+	if opt.ConsecutiveFailures == 0 && opt.FailureRatio == 0 {
+		opt.ConsecutiveFailures = 5
+	}
+	if opt.MinRequests == 0 {
+		opt.MinRequests = 10
+	}
+	switch opt.Interval {
+	case -1:
+		opt.Interval = 0
+	case 0:
+		opt.Interval = time.Minute
+	}
+	if opt.OpenTimeout == 0 {
+		opt.OpenTimeout = 30 * time.Second
+	}
+	if opt.HalfOpenRequests == 0 {
+		opt.HalfOpenRequests = 1
+	}
+	if opt.IsFailure == nil {
+		opt.IsFailure = isCircuitFailure
+	}
+}
+
+func isCircuitFailure(err error) bool {
+	return err != nil && !isRedisError(err)
+}
+
+// CircuitBreaker is a Limiter that stops sending the commands to a node that keeps failing,
+// e.g. because it is down, so the commands fail fast with ErrCircuitOpen instead of
+// waiting for the dial and read timeouts:
+//
+//	rdb := redis.NewClient(&redis.Options{
+//		Limiter: redis.NewCircuitBreaker(&redis.CircuitBreakerOptions{
+//			OnStateChange: func(name string, from, to redis.CircuitState) { ... },
+//		}),
+//	})
+//
+// ClusterOptions.CircuitBreaker and RingOptions.CircuitBreaker create a breaker per node.
+type CircuitBreaker struct {
+	opt CircuitBreakerOptions
+
+	mu          sync.Mutex
+	state       CircuitState
+	expiry      time.Time // the end of the Interval or of the OpenTimeout
+	requests    int
+	failures    int
+	consecutive int
+
+	// transitions are the state changes to pass to OnStateChange outside of the lock.
+	transitions []circuitTransition
+}
+
+type circuitTransition struct {
+	from, to CircuitState
+}
+
+var _ Limiter = (*CircuitBreaker)(nil)
+
+// NewCircuitBreaker returns a closed CircuitBreaker.
+func NewCircuitBreaker(opt *CircuitBreakerOptions) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		opt: *opt,
+	}
+	cb.opt.init()
+	cb.setState(CircuitClosed, time.Now())
+	return cb
+}
+
+// Name returns CircuitBreakerOptions.Name.
+func (cb *CircuitBreaker) Name() string {
+	return cb.opt.Name
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	state := cb.currentState(time.Now())
+	transitions := cb.takeTransitions()
+	cb.mu.Unlock()
+
+	cb.notify(transitions)
+	return state
+}
+
+// Allow returns ErrCircuitOpen if the circuit is open or the half-open circuit
+// has allowed HalfOpenRequests probes.
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	state := cb.currentState(time.Now())
+
+	var err error
+	switch state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if cb.requests >= cb.opt.HalfOpenRequests {
+			err = ErrCircuitOpen
+		} else {
+			cb.requests++
+		}
+	}
+	transitions := cb.takeTransitions()
+	cb.mu.Unlock()
+
+	cb.notify(transitions)
+	return err
+}
+
+// ReportResult counts the result of the command allowed by Allow.
+func (cb *CircuitBreaker) ReportResult(result error) {
+	now := time.Now()
+
+	cb.mu.Lock()
+	state := cb.currentState(now)
+
+	switch {
+	case errors.Is(result, context.Canceled):
+		if state == CircuitHalfOpen && cb.requests > 0 {
+			// Let another command probe the node.
+			cb.requests--
+		}
+	case cb.opt.IsFailure(result):
+		cb.onFailure(state, now)
+	default:
+		cb.onSuccess(state, now)
+	}
+	transitions := cb.takeTransitions()
+	cb.mu.Unlock()
+
+	cb.notify(transitions)
+}
+
+func (cb *CircuitBreaker) onSuccess(state CircuitState, now time.Time) {
+	switch state {
+	case CircuitClosed:
+		cb.requests++
+		cb.consecutive = 0
+	case CircuitHalfOpen:
+		cb.consecutive++
+		if cb.consecutive >= cb.opt.HalfOpenRequests {
+			cb.setState(CircuitClosed, now)
+		}
+	}
+}
+
+func (cb *CircuitBreaker) onFailure(state CircuitState, now time.Time) {
+	switch state {
+	case CircuitClosed:
+		cb.requests++
+		cb.failures++
+		cb.consecutive++
+		if cb.shouldTrip() {
+			cb.setState(CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		cb.setState(CircuitOpen, now)
+	}
+}
+
+func (cb *CircuitBreaker) shouldTrip() bool {
+	if cb.opt.ConsecutiveFailures > 0 && cb.consecutive >= cb.opt.ConsecutiveFailures {
+		return true
+	}
+	return cb.opt.FailureRatio > 0 && cb.requests >= cb.opt.MinRequests &&
+		float64(cb.failures)/float64(cb.requests) >= cb.opt.FailureRatio
+}
+
+// currentState moves the circuit to the half-open state after the OpenTimeout
+// and resets the counts of the closed circuit after the Interval.
+func (cb *CircuitBreaker) currentState(now time.Time) CircuitState {
+	switch cb.state {
+	case CircuitClosed:
+		if !cb.expiry.IsZero() && !now.Before(cb.expiry) {
+			cb.setState(CircuitClosed, now)
+		}
+	case CircuitOpen:
+		if !now.Before(cb.expiry) {
+			cb.setState(CircuitHalfOpen, now)
+		}
+	}
+	return cb.state
+}
+
+func (cb *CircuitBreaker) setState(state CircuitState, now time.Time) {
+	if state != cb.state {
+		cb.transitions = append(cb.transitions, circuitTransition{from: cb.state, to: state})
+	}
+	cb.state = state
+	cb.requests = 0
+	cb.failures = 0
+	cb.consecutive = 0
+
+	switch state {
+	case CircuitClosed:
+		if cb.opt.Interval > 0 {
+			cb.expiry = now.Add(cb.opt.Interval)
+		} else {
+			cb.expiry = time.Time{}
+		}
+	case CircuitOpen:
+		cb.expiry = now.Add(cb.opt.OpenTimeout)
+	default:
+		cb.expiry = time.Time{}
+	}
+}
+
+func (cb *CircuitBreaker) takeTransitions() []circuitTransition {
+	transitions := cb.transitions
+	cb.transitions = nil
+	return transitions
+}
+
+// notify calls OnStateChange outside of the lock.
+func (cb *CircuitBreaker) notify(transitions []circuitTransition) {
+	if cb.opt.OnStateChange == nil {
+		return
+	}
+	for _, t := range transitions {
+		cb.opt.OnStateChange(cb.opt.Name, t.from, t.to)
+	}
+}
+
+// nodeCircuitBreaker returns the breaker of the node if the options are set.
+func nodeCircuitBreaker(opt *CircuitBreakerOptions, addr string) Limiter {
+	if opt == nil {
+		return nil
+	}
+	o := *opt
+	o.Name = addr
+	return NewCircuitBreaker(&o)
+}
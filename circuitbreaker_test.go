@@ -0,0 +1,163 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	var (
+		mu          sync.Mutex
+		transitions []string
+	)
+
+	onStateChange := func(name string, from, to redis.CircuitState) {
+		mu.Lock()
+		defer mu.Unlock()
+		transitions = append(transitions, name+": "+from.String()+" -> "+to.String())
+	}
+
+	getTransitions := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return transitions
+	}
+
+	BeforeEach(func() {
+		transitions = nil
+	})
+
+	fail := func(cb *redis.CircuitBreaker, n int) {
+		for i := 0; i < n; i++ {
+			Expect(cb.Allow()).NotTo(HaveOccurred())
+			cb.ReportResult(io.EOF)
+		}
+	}
+
+	It("should open after consecutive failures", func() {
+		cb := redis.NewCircuitBreaker(&redis.CircuitBreakerOptions{
+			Name:                "node",
+			ConsecutiveFailures: 3,
+			OpenTimeout:         50 * time.Millisecond,
+			OnStateChange:       onStateChange,
+		})
+		Expect(cb.Name()).To(Equal("node"))
+
+		fail(cb, 2)
+		Expect(cb.Allow()).NotTo(HaveOccurred())
+		cb.ReportResult(nil)
+		fail(cb, 2)
+		Expect(cb.State()).To(Equal(redis.CircuitClosed))
+
+		fail(cb, 1)
+		Expect(cb.State()).To(Equal(redis.CircuitOpen))
+		Expect(cb.Allow()).To(Equal(redis.ErrCircuitOpen))
+
+		time.Sleep(60 * time.Millisecond)
+		Expect(cb.Allow()).NotTo(HaveOccurred())
+		Expect(cb.State()).To(Equal(redis.CircuitHalfOpen))
+		Expect(cb.Allow()).To(Equal(redis.ErrCircuitOpen))
+		cb.ReportResult(nil)
+		Expect(cb.State()).To(Equal(redis.CircuitClosed))
+
+		Expect(getTransitions()).To(Equal([]string{
+			"node: closed -> open",
+			"node: open -> half-open",
+			"node: half-open -> closed",
+		}))
+	})
+
+	It("should open again when the probe fails", func() {
+		cb := redis.NewCircuitBreaker(&redis.CircuitBreakerOptions{
+			ConsecutiveFailures: 1,
+			OpenTimeout:         50 * time.Millisecond,
+			HalfOpenRequests:    2,
+		})
+
+		fail(cb, 1)
+		time.Sleep(60 * time.Millisecond)
+		Expect(cb.Allow()).NotTo(HaveOccurred())
+		Expect(cb.Allow()).NotTo(HaveOccurred())
+		cb.ReportResult(nil)
+		Expect(cb.State()).To(Equal(redis.CircuitHalfOpen))
+		cb.ReportResult(io.EOF)
+		Expect(cb.State()).To(Equal(redis.CircuitOpen))
+	})
+
+	It("should open when the failure ratio is reached", func() {
+		cb := redis.NewCircuitBreaker(&redis.CircuitBreakerOptions{
+			FailureRatio: 0.5,
+			MinRequests:  4,
+		})
+
+		for i := 0; i < 3; i++ {
+			Expect(cb.Allow()).NotTo(HaveOccurred())
+			cb.ReportResult(nil)
+		}
+		fail(cb, 2)
+		Expect(cb.State()).To(Equal(redis.CircuitClosed))
+		fail(cb, 1)
+		Expect(cb.State()).To(Equal(redis.CircuitOpen))
+	})
+
+	It("should reset the counts after the interval", func() {
+		cb := redis.NewCircuitBreaker(&redis.CircuitBreakerOptions{
+			ConsecutiveFailures: 2,
+			Interval:            50 * time.Millisecond,
+		})
+
+		fail(cb, 1)
+		time.Sleep(60 * time.Millisecond)
+		fail(cb, 1)
+		Expect(cb.State()).To(Equal(redis.CircuitClosed))
+	})
+
+	It("should ignore Redis errors and cancelled commands", func() {
+		cb := redis.NewCircuitBreaker(&redis.CircuitBreakerOptions{
+			ConsecutiveFailures: 1,
+		})
+
+		for _, err := range []error{redis.Nil, context.Canceled} {
+			Expect(cb.Allow()).NotTo(HaveOccurred())
+			cb.ReportResult(err)
+		}
+		Expect(cb.State()).To(Equal(redis.CircuitClosed))
+
+		cb = redis.NewCircuitBreaker(&redis.CircuitBreakerOptions{
+			ConsecutiveFailures: 1,
+			IsFailure: func(err error) bool {
+				return errors.Is(err, redis.Nil)
+			},
+		})
+		Expect(cb.Allow()).NotTo(HaveOccurred())
+		cb.ReportResult(redis.Nil)
+		Expect(cb.State()).To(Equal(redis.CircuitOpen))
+	})
+
+	It("should create a breaker per ring shard", func() {
+		ring := redis.NewRing(&redis.RingOptions{
+			Addrs:       map[string]string{"down": "127.0.0.1:1"},
+			DialTimeout: 100 * time.Millisecond,
+			MaxRetries:  -1,
+			CircuitBreaker: &redis.CircuitBreakerOptions{
+				ConsecutiveFailures: 2,
+				OnStateChange:       onStateChange,
+			},
+		})
+		defer ring.Close()
+
+		ctx := context.Background()
+		Eventually(func() error {
+			return ring.Get(ctx, "key").Err()
+		}).Should(Equal(redis.ErrCircuitOpen))
+		Expect(getTransitions()).To(ContainElement("127.0.0.1:1: closed -> open"))
+	})
+})
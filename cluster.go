@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -48,6 +49,13 @@ type ClusterOptions struct {
 	// Allows routing read-only commands to the random master or slave node.
 	// It automatically enables ReadOnly.
 	RouteRandomly bool
+	// ReadRouter selects the master or slave node for read-only commands instead of
+	// RouteByLatency and RouteRandomly, e.g. ZoneReadRouter. The node latencies are measured
+	// for the router. It automatically enables ReadOnly.
+	ReadRouter ReadRouter
+	// NodeMetadata returns the metadata of the node, e.g. MetadataAvailabilityZone,
+	// for ReadRouter. It overrides the metadata reported by CLUSTER SHARDS.
+	NodeMetadata func(addr string) map[string]string
 
 	// Optional function that returns cluster slots information.
 	// It is useful to manually create cluster of standalone Redis servers
@@ -74,6 +82,8 @@ type ClusterOptions struct {
 	ReadTimeout           time.Duration
 	WriteTimeout          time.Duration
 	ContextTimeoutEnabled bool
+	AttributesEnabled     bool
+	Codec                 Codec
 
 	PoolFIFO        bool
 	PoolSize        int // applies per cluster node and not for the whole cluster
@@ -84,6 +94,18 @@ type ClusterOptions struct {
 	ConnMaxLifetime time.Duration
 
 	TLSConfig *tls.Config
+
+	OnPush         map[string]PushNotificationHandler
+	AutoPipeline   *AutoPipelineOptions // applies per cluster node
+	MultiplexConns int                  // applies per cluster node
+	ClientCache    *ClientCacheOptions  // applies per cluster node
+
+	// CircuitBreaker creates a CircuitBreaker per cluster node named by the node address.
+	CircuitBreaker *CircuitBreakerOptions
+}
+
+func (opt *ClusterOptions) measureLatency() bool {
+	return opt.RouteByLatency || opt.ReadRouter != nil
 }
 
 func (opt *ClusterOptions) init() {
@@ -93,7 +115,7 @@ func (opt *ClusterOptions) init() {
 		opt.MaxRedirects = 3
 	}
 
-	if opt.RouteByLatency || opt.RouteRandomly {
+	if opt.RouteByLatency || opt.RouteRandomly || opt.ReadRouter != nil {
 		opt.ReadOnly = true
 	}
 
@@ -277,6 +299,9 @@ func (opt *ClusterOptions) clientOptions() *Options {
 		ReadTimeout:  opt.ReadTimeout,
 		WriteTimeout: opt.WriteTimeout,
 
+		AttributesEnabled: opt.AttributesEnabled,
+		Codec:             opt.Codec,
+
 		PoolFIFO:        opt.PoolFIFO,
 		PoolSize:        opt.PoolSize,
 		PoolTimeout:     opt.PoolTimeout,
@@ -285,7 +310,11 @@ func (opt *ClusterOptions) clientOptions() *Options {
 		ConnMaxIdleTime: opt.ConnMaxIdleTime,
 		ConnMaxLifetime: opt.ConnMaxLifetime,
 
-		TLSConfig: opt.TLSConfig,
+		TLSConfig:      opt.TLSConfig,
+		OnPush:         opt.OnPush,
+		AutoPipeline:   opt.AutoPipeline,
+		MultiplexConns: opt.MultiplexConns,
+		ClientCache:    opt.ClientCache,
 		// If ClusterSlots is populated, then we probably have an artificial
 		// cluster whose nodes are not in clustering mode (otherwise there isn't
 		// much use for ClusterSlots config).  This means we cannot execute the
@@ -303,17 +332,30 @@ type clusterNode struct {
 	latency    uint32 // atomic
 	generation uint32 // atomic
 	failing    uint32 // atomic
+
+	// metadata is the networking metadata of the node reported by
+	// CLUSTER SHARDS or CLUSTER SLOTS, e.g. the hostname and the availability zone,
+	// merged with the labels returned by ClusterOptions.NodeMetadata.
+	metadata atomic.Value // map[string]string
+	labels   map[string]string
 }
 
 func newClusterNode(clOpt *ClusterOptions, addr string) *clusterNode {
 	opt := clOpt.clientOptions()
 	opt.Addr = addr
+	if cb := nodeCircuitBreaker(clOpt.CircuitBreaker, addr); cb != nil {
+		opt.Limiter = cb
+	}
 	node := clusterNode{
 		Client: clOpt.NewClient(opt),
 	}
+	if clOpt.NodeMetadata != nil {
+		node.labels = clOpt.NodeMetadata(addr)
+		node.SetMetadata(nil)
+	}
 
 	node.latency = math.MaxUint32
-	if clOpt.RouteByLatency {
+	if clOpt.measureLatency() {
 		go node.updateLatency()
 	}
 
@@ -360,18 +402,21 @@ func (n *clusterNode) Latency() time.Duration {
 	return time.Duration(latency) * time.Microsecond
 }
 
-func (n *clusterNode) MarkAsFailing() {
-	atomic.StoreUint32(&n.failing, uint32(time.Now().Unix()))
+const clusterNodeFailingTimeout = 15 // 15 seconds
+
+// MarkAsFailing returns true if the node was not failing.
+func (n *clusterNode) MarkAsFailing() bool {
+	now := time.Now().Unix()
+	failing := atomic.SwapUint32(&n.failing, uint32(now))
+	return failing == 0 || now-int64(failing) >= clusterNodeFailingTimeout
 }
 
 func (n *clusterNode) Failing() bool {
-	const timeout = 15 // 15 seconds
-
 	failing := atomic.LoadUint32(&n.failing)
 	if failing == 0 {
 		return false
 	}
-	if time.Now().Unix()-int64(failing) < timeout {
+	if time.Now().Unix()-int64(failing) < clusterNodeFailingTimeout {
 		return true
 	}
 	atomic.StoreUint32(&n.failing, 0)
@@ -391,6 +436,27 @@ func (n *clusterNode) SetGeneration(gen uint32) {
 	}
 }
 
+func (n *clusterNode) Metadata() map[string]string {
+	metadata, _ := n.metadata.Load().(map[string]string)
+	return metadata
+}
+
+func (n *clusterNode) SetMetadata(metadata map[string]string) {
+	if len(n.labels) > 0 {
+		merged := make(map[string]string, len(metadata)+len(n.labels))
+		for k, v := range metadata {
+			merged[k] = v
+		}
+		for k, v := range n.labels {
+			merged[k] = v
+		}
+		metadata = merged
+	}
+	if metadata != nil {
+		n.metadata.Store(metadata)
+	}
+}
+
 //------------------------------------------------------------------------------
 
 type clusterNodes struct {
@@ -402,6 +468,8 @@ type clusterNodes struct {
 	activeAddrs []string
 	closed      bool
 	onNewNode   []func(rdb *Client)
+	// onEvent is called when a node is added or removed.
+	onEvent func(event ClusterEvent)
 
 	_generation uint32 // atomic
 }
@@ -481,7 +549,7 @@ func (c *clusterNodes) GC(generation uint32) {
 	for addr, node := range c.nodes {
 		if node.Generation() >= generation {
 			c.activeAddrs = append(c.activeAddrs, addr)
-			if c.opt.RouteByLatency {
+			if c.opt.measureLatency() {
 				go node.updateLatency()
 			}
 			continue
@@ -495,6 +563,9 @@ func (c *clusterNodes) GC(generation uint32) {
 
 	for _, node := range collected {
 		_ = node.Client.Close()
+		if c.onEvent != nil {
+			c.onEvent(ClusterEvent{Type: ClusterEventNodeRemoved, Addr: node.Client.opt.Addr, Slot: -1})
+		}
 	}
 }
 
@@ -508,14 +579,15 @@ func (c *clusterNodes) GetOrCreate(addr string) (*clusterNode, error) {
 	}
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
 
 	if c.closed {
+		c.mu.Unlock()
 		return nil, pool.ErrClosed
 	}
 
 	node, ok := c.nodes[addr]
 	if ok {
+		c.mu.Unlock()
 		return node, nil
 	}
 
@@ -527,6 +599,11 @@ func (c *clusterNodes) GetOrCreate(addr string) (*clusterNode, error) {
 	c.addrs = appendIfNotExists(c.addrs, addr)
 	c.nodes[addr] = node
 
+	c.mu.Unlock()
+
+	if c.onEvent != nil {
+		c.onEvent(ClusterEvent{Type: ClusterEventNodeAdded, Addr: addr, Slot: -1})
+	}
 	return node, nil
 }
 
@@ -629,6 +706,7 @@ func newClusterState(
 			}
 
 			node.SetGeneration(c.generation)
+			node.SetMetadata(slotNode.NetworkingMetadata)
 			nodes = append(nodes, node)
 
 			if i == 0 {
@@ -756,6 +834,29 @@ func (c *clusterState) slotRandomNode(slot int) (*clusterNode, error) {
 	return nodes[randomNodes[0]], nil
 }
 
+func (c *clusterState) slotRoutedNode(router ReadRouter, slot int) (*clusterNode, error) {
+	nodes := c.slotNodes(slot)
+	if len(nodes) == 0 {
+		return c.nodes.Random()
+	}
+
+	readNodes := make([]ReadNode, len(nodes))
+	for i, node := range nodes {
+		readNodes[i] = ReadNode{
+			Addr:     node.Client.opt.Addr,
+			Master:   i == 0,
+			Failing:  node.Failing(),
+			Latency:  node.Latency(),
+			Metadata: node.Metadata(),
+		}
+	}
+
+	if i := router.Route(slot, readNodes); i >= 0 && i < len(nodes) {
+		return nodes[i], nil
+	}
+	return nodes[0], nil
+}
+
 func (c *clusterState) slotNodes(slot int) []*clusterNode {
 	i := sort.Search(len(c.slots), func(i int) bool {
 		return c.slots[i].end >= slot
@@ -773,7 +874,8 @@ func (c *clusterState) slotNodes(slot int) []*clusterNode {
 //------------------------------------------------------------------------------
 
 type clusterStateHolder struct {
-	load func(ctx context.Context) (*clusterState, error)
+	load     func(ctx context.Context) (*clusterState, error)
+	onChange func(old, new *clusterState)
 
 	state     atomic.Value
 	reloading uint32 // atomic
@@ -790,7 +892,10 @@ func (c *clusterStateHolder) Reload(ctx context.Context) (*clusterState, error)
 	if err != nil {
 		return nil, err
 	}
-	c.state.Store(state)
+	old, _ := c.state.Swap(state).(*clusterState)
+	if c.onChange != nil {
+		c.onChange(old, state)
+	}
 	return state, nil
 }
 
@@ -835,13 +940,21 @@ func (c *clusterStateHolder) ReloadOrGet(ctx context.Context) (*clusterState, er
 // ClusterClient is a Redis Cluster client representing a pool of zero
 // or more underlying connections. It's safe for concurrent use by
 // multiple goroutines.
+//
+// The MGET, MSET, DEL, EXISTS, UNLINK and TOUCH commands whose keys belong to
+// different slots are split by slot, see ClusterPartialError. A split MSET is
+// not atomic: the keys of some slots can be set while the others are not yet
+// or fail to be set. Use hash tags to keep the keys in one slot if it matters.
 type ClusterClient struct {
 	opt           *ClusterOptions
 	nodes         *clusterNodes
 	state         *clusterStateHolder
 	cmdsInfoCache *cmdsInfoCache
+	events        clusterEvents
 	cmdable
 	hooksMixin
+
+	noClusterShards uint32 // atomic; CLUSTER SHARDS is not supported by the server
 }
 
 // NewClusterClient returns a Redis Cluster client as described in
@@ -855,6 +968,8 @@ func NewClusterClient(opt *ClusterOptions) *ClusterClient {
 	}
 
 	c.state = newClusterStateHolder(c.loadState)
+	c.state.onChange = c.events.stateChanged
+	c.nodes.onEvent = c.events.emit
 	c.cmdsInfoCache = newCmdsInfoCache(c.cmdsInfo)
 	c.cmdable = c.Process
 
@@ -901,6 +1016,13 @@ func (c *ClusterClient) Process(ctx context.Context, cmd Cmder) error {
 }
 
 func (c *ClusterClient) process(ctx context.Context, cmd Cmder) error {
+	if split := splitClusterCmd(ctx, cmd); split != nil {
+		// Run the pieces with the keys of different slots in parallel.
+		_ = c._processPipeline(ctx, split.pieces)
+		split.merge()
+		return cmd.Err()
+	}
+
 	cmdInfo := c.cmdInfo(ctx, cmd.Name())
 	slot := c.cmdSlot(ctx, cmd)
 	var node *clusterNode
@@ -946,7 +1068,7 @@ func (c *ClusterClient) process(ctx context.Context, cmd Cmder) error {
 
 		// If slave is loading - pick another node.
 		if c.opt.ReadOnly && isLoadingError(lastErr) {
-			node.MarkAsFailing()
+			c.markAsFailing(node, lastErr)
 			node = nil
 			continue
 		}
@@ -962,6 +1084,7 @@ func (c *ClusterClient) process(ctx context.Context, cmd Cmder) error {
 			if err != nil {
 				return err
 			}
+			c.events.redirected(lastErr, moved, ask, addr)
 			continue
 		}
 
@@ -972,7 +1095,7 @@ func (c *ClusterClient) process(ctx context.Context, cmd Cmder) error {
 			}
 
 			// Second try another node.
-			node.MarkAsFailing()
+			c.markAsFailing(node, lastErr)
 			node = nil
 			continue
 		}
@@ -986,6 +1109,40 @@ func (c *ClusterClient) OnNewNode(fn func(rdb *Client)) {
 	c.nodes.OnNewNode(fn)
 }
 
+// OnTopologyChange calls the fn when the slot ranges or their nodes in the reloaded
+// cluster state differ from the previous state, e.g. after a resharding or a failover.
+// The fn is called synchronously by the reloading goroutine and must not block.
+func (c *ClusterClient) OnTopologyChange(fn func(old, new ClusterTopology)) {
+	c.events.OnTopologyChange(fn)
+}
+
+// OnClusterEvent calls the fn on the MOVED and ASK redirects, when a node is added
+// or removed and when a node is marked as failing.
+// The fn is called synchronously by the goroutine that caught the event and must not block.
+func (c *ClusterClient) OnClusterEvent(fn func(event ClusterEvent)) {
+	c.events.OnClusterEvent(fn)
+}
+
+// Topology returns a snapshot of the current cluster state.
+func (c *ClusterClient) Topology(ctx context.Context) (ClusterTopology, error) {
+	state, err := c.state.Get(ctx)
+	if err != nil {
+		return ClusterTopology{}, err
+	}
+	return state.topology(), nil
+}
+
+func (c *ClusterClient) markAsFailing(node *clusterNode, err error) {
+	if node.MarkAsFailing() {
+		c.events.emit(ClusterEvent{
+			Type: ClusterEventNodeFailing,
+			Addr: node.Client.opt.Addr,
+			Slot: -1,
+			Err:  err,
+		})
+	}
+}
+
 // ForEachMaster concurrently calls the fn on each master node in the cluster.
 // It returns the first error if any.
 func (c *ClusterClient) ForEachMaster(
@@ -1024,6 +1181,50 @@ func (c *ClusterClient) ForEachMaster(
 	}
 }
 
+// ScanAll returns an iterator over the keys of all the masters that match the pattern
+// and, unless keyType is empty, have the type. The count is passed to SCAN as the COUNT hint.
+// Use ScanAllIterator.ForEach to scan the masters concurrently.
+//
+// The iterator reloads the cluster state when a master fails and when the walk ends,
+// and then scans the new masters and the masters whose slots changed, so the keys
+// moved by a resharding or a failover during the walk are not missed.
+// The keys are deduplicated, so the iterator keeps all the returned keys in memory.
+func (c *ClusterClient) ScanAll(ctx context.Context, match string, count int64, keyType string) *ScanAllIterator {
+	return newScanAllIterator(ctx, match, count, keyType, c.scanAllNodes)
+}
+
+func (c *ClusterClient) scanAllNodes(ctx context.Context, reload bool) ([]scanAllNode, error) {
+	var state *clusterState
+	var err error
+	if reload {
+		state, err = c.state.Reload(ctx)
+	} else {
+		state, err = c.state.ReloadOrGet(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make(map[*clusterNode][]string, len(state.Masters))
+	for _, slot := range state.slots {
+		if len(slot.nodes) == 0 {
+			continue
+		}
+		master := slot.nodes[0]
+		slots[master] = append(slots[master], fmt.Sprintf("%d-%d", slot.start, slot.end))
+	}
+
+	nodes := make([]scanAllNode, 0, len(state.Masters))
+	for _, master := range state.Masters {
+		nodes = append(nodes, scanAllNode{
+			addr:   master.Client.opt.Addr,
+			owns:   strings.Join(slots[master], ","),
+			client: master.Client,
+		})
+	}
+	return nodes, nil
+}
+
 // ForEachSlave concurrently calls the fn on each slave node in the cluster.
 // It returns the first error if any.
 func (c *ClusterClient) ForEachSlave(
@@ -1140,6 +1341,52 @@ func (c *ClusterClient) PoolStats() *PoolStats {
 	return &acc
 }
 
+// CacheStats accumulates client-side cache stats of all cluster nodes.
+func (c *ClusterClient) CacheStats() *CacheStats {
+	var acc CacheStats
+
+	state, _ := c.state.Get(context.TODO())
+	if state == nil {
+		return &acc
+	}
+
+	for _, nodes := range [][]*clusterNode{state.Masters, state.Slaves} {
+		for _, node := range nodes {
+			s := node.Client.CacheStats()
+			acc.Hits += s.Hits
+			acc.Misses += s.Misses
+			acc.Evictions += s.Evictions
+			acc.Invalidations += s.Invalidations
+			acc.Entries += s.Entries
+		}
+	}
+
+	return &acc
+}
+
+// AutoPipelineStats accumulates automatic pipelining stats of all cluster nodes.
+func (c *ClusterClient) AutoPipelineStats() *AutoPipelineStats {
+	var acc AutoPipelineStats
+
+	state, _ := c.state.Get(context.TODO())
+	if state == nil {
+		return &acc
+	}
+
+	for _, nodes := range [][]*clusterNode{state.Masters, state.Slaves} {
+		for _, node := range nodes {
+			s := node.Client.AutoPipelineStats()
+			acc.Batches += s.Batches
+			acc.FullBatches += s.FullBatches
+			acc.Cmds += s.Cmds
+			acc.WaitTime += s.WaitTime
+			acc.ExecTime += s.ExecTime
+		}
+	}
+
+	return &acc
+}
+
 func (c *ClusterClient) loadState(ctx context.Context) (*clusterState, error) {
 	if c.opt.ClusterSlots != nil {
 		slots, err := c.opt.ClusterSlots(ctx)
@@ -1167,7 +1414,7 @@ func (c *ClusterClient) loadState(ctx context.Context) (*clusterState, error) {
 			continue
 		}
 
-		slots, err := node.Client.ClusterSlots(ctx).Result()
+		slots, err := c.clusterSlots(ctx, node)
 		if err != nil {
 			if firstErr == nil {
 				firstErr = err
@@ -1191,9 +1438,111 @@ func (c *ClusterClient) loadState(ctx context.Context) (*clusterState, error) {
 	return nil, firstErr
 }
 
+// clusterSlots prefers CLUSTER SHARDS, which reports the health and the announced
+// hostnames of the nodes, and falls back to CLUSTER SLOTS on servers older than Redis 7.
+func (c *ClusterClient) clusterSlots(ctx context.Context, node *clusterNode) ([]ClusterSlot, error) {
+	if atomic.LoadUint32(&c.noClusterShards) == 0 {
+		shards, err := node.Client.ClusterShards(ctx).Result()
+		if err == nil {
+			return clusterShardsSlots(shards, c.opt.TLSConfig != nil), nil
+		}
+		if !isRedisError(err) {
+			return nil, err
+		}
+		if isUnknownCommandError(err) {
+			atomic.StoreUint32(&c.noClusterShards, 1)
+		}
+	}
+	return node.Client.ClusterSlots(ctx).Result()
+}
+
+// isUnknownCommandError reports whether the server does not support the command or subcommand.
+func isUnknownCommandError(err error) bool {
+	s := strings.ToLower(err.Error())
+	return strings.HasPrefix(s, "err unknown command") || strings.HasPrefix(s, "err unknown subcommand")
+}
+
+// clusterShardsSlots converts the CLUSTER SHARDS reply to the CLUSTER SLOTS one.
+// The online master is preferred to the failed one, e.g. during a failover,
+// and the replicas that are loading or failed are skipped.
+func clusterShardsSlots(shards []ClusterShard, tls bool) []ClusterSlot {
+	var slots []ClusterSlot
+	for _, shard := range shards {
+		if len(shard.Slots) == 0 {
+			continue
+		}
+
+		var master *ClusterNode
+		var masterOnline bool
+		var replicas []ClusterNode
+		for i := range shard.Nodes {
+			node := &shard.Nodes[i]
+			online := node.Health == "" || node.Health == "online"
+			switch {
+			case node.Role == "master":
+				if master == nil || (online && !masterOnline) {
+					master, masterOnline = clusterShardNode(node, tls), online
+				}
+			case online:
+				replicas = append(replicas, *clusterShardNode(node, tls))
+			}
+		}
+		if master == nil {
+			continue
+		}
+
+		nodes := append([]ClusterNode{*master}, replicas...)
+		for _, r := range shard.Slots {
+			slots = append(slots, ClusterSlot{
+				Start: int(r.Start),
+				End:   int(r.End),
+				Nodes: nodes,
+			})
+		}
+	}
+	return slots
+}
+
+// clusterShardNode uses the preferred endpoint of the node, like CLUSTER SLOTS,
+// and falls back to the hostname and the IP when it is unknown.
+// The TLS port is used when TLS is enabled.
+func clusterShardNode(node *Node, tls bool) *ClusterNode {
+	host := node.Endpoint
+	if host == "" || host == "?" {
+		host = node.Hostname
+	}
+	if host == "" || host == "?" {
+		host = node.IP
+	}
+
+	port := node.Port
+	if (tls && node.TLSPort != 0) || port == 0 {
+		port = node.TLSPort
+	}
+
+	metadata := make(map[string]string)
+	for key, value := range map[string]string{
+		"ip":                node.IP,
+		"hostname":          node.Hostname,
+		"health":            node.Health,
+		"availability-zone": node.AvailabilityZone,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+
+	return &ClusterNode{
+		ID:                 node.ID,
+		Addr:               net.JoinHostPort(host, strconv.FormatInt(port, 10)),
+		NetworkingMetadata: metadata,
+	}
+}
+
 func (c *ClusterClient) Pipeline() Pipeliner {
 	pipe := Pipeline{
-		exec: pipelineExecer(c.processPipelineHook),
+		exec:  pipelineExecer(c.processPipelineHook),
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
@@ -1204,6 +1553,19 @@ func (c *ClusterClient) Pipelined(ctx context.Context, fn func(Pipeliner) error)
 }
 
 func (c *ClusterClient) processPipeline(ctx context.Context, cmds []Cmder) error {
+	pieces, splits := splitClusterCmds(ctx, cmds)
+	if len(splits) == 0 {
+		return c._processPipeline(ctx, cmds)
+	}
+
+	_ = c._processPipeline(ctx, pieces)
+	for _, split := range splits {
+		split.merge()
+	}
+	return cmdsFirstErr(cmds)
+}
+
+func (c *ClusterClient) _processPipeline(ctx context.Context, cmds []Cmder) error {
 	cmdsMap := newCmdsMap()
 
 	if err := c.mapCmdsByNode(ctx, cmdsMap, cmds); err != nil {
@@ -1326,7 +1688,7 @@ func (c *ClusterClient) pipelineReadCmds(
 	failedCmds *cmdsMap,
 ) error {
 	for i, cmd := range cmds {
-		err := cmd.readReply(rd)
+		err := readCmdReply(rd, cmd)
 		cmd.SetErr(err)
 
 		if err == nil {
@@ -1338,7 +1700,7 @@ func (c *ClusterClient) pipelineReadCmds(
 		}
 
 		if c.opt.ReadOnly {
-			node.MarkAsFailing()
+			c.markAsFailing(node, err)
 		}
 
 		if !isRedisError(err) {
@@ -1366,10 +1728,11 @@ func (c *ClusterClient) checkMovedErr(
 		return false
 	}
 
-	node, err := c.nodes.GetOrCreate(addr)
-	if err != nil {
+	node, nodeErr := c.nodes.GetOrCreate(addr)
+	if nodeErr != nil {
 		return false
 	}
+	c.events.redirected(err, moved, ask, addr)
 
 	if moved {
 		c.state.LazyReload()
@@ -1392,6 +1755,7 @@ func (c *ClusterClient) TxPipeline() Pipeliner {
 			cmds = wrapMultiExec(ctx, cmds)
 			return c.processTxPipelineHook(ctx, cmds)
 		},
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
@@ -1506,6 +1870,7 @@ func (c *ClusterClient) processTxPipelineNodeConn(
 
 			moved, ask, addr := isMovedError(err)
 			if moved || ask {
+				c.events.redirected(err, moved, ask, addr)
 				return c.cmdsMoved(ctx, trimmedCmds, moved, ask, addr, failedCmds)
 			}
 
@@ -1613,6 +1978,7 @@ func (c *ClusterClient) Watch(ctx context.Context, fn func(*Tx) error, keys ...s
 
 		moved, ask, addr := isMovedError(err)
 		if moved || ask {
+			c.events.redirected(err, moved, ask, addr)
 			node, err = c.nodes.GetOrCreate(addr)
 			if err != nil {
 				return err
@@ -1806,6 +2172,9 @@ func (c *ClusterClient) cmdNode(
 }
 
 func (c *ClusterClient) slotReadOnlyNode(state *clusterState, slot int) (*clusterNode, error) {
+	if c.opt.ReadRouter != nil {
+		return state.slotRoutedNode(c.opt.ReadRouter, slot)
+	}
 	if c.opt.RouteByLatency {
 		return state.slotClosestNode(slot)
 	}
@@ -0,0 +1,229 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/redis/go-redis/v9/internal/hashtag"
+)
+
+// ClusterPartialError is returned by a multi-key command, e.g. MGET or DEL, that
+// ClusterClient split by slot when some of the pieces failed. The results of the keys
+// of the other pieces are set, e.g. MGET returns their values and DEL counts them.
+// The keys of the MSET pieces that succeeded stay set.
+type ClusterPartialError struct {
+	// Errs maps the keys of the failed pieces to the errors.
+	Errs map[string]error
+	// Keys is the number of the keys of the command.
+	Keys int
+}
+
+func (e *ClusterPartialError) Error() string {
+	keys := make([]string, 0, len(e.Errs))
+	for key := range e.Errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return fmt.Sprintf("redis: %d of %d keys failed, key %q: %s",
+		len(e.Errs), e.Keys, keys[0], e.Errs[keys[0]])
+}
+
+// clusterSplitCmd is a multi-key command split into the pieces with the keys of one slot.
+type clusterSplitCmd struct {
+	cmd    Cmder
+	pieces []Cmder
+	// indexes are the positions of the keys of the pieces in the cmd.
+	indexes [][]int
+	// step is the number of the args per key, e.g. 2 for MSET.
+	step int
+}
+
+// splitClusterCmd splits the MGET, MSET, DEL, EXISTS, UNLINK and TOUCH commands
+// whose keys belong to different slots, including the commands sent with Do.
+// It returns nil for other commands.
+//
+// The keys are split by slot rather than by node, because Redis Cluster rejects
+// the keys of different slots with CROSSSLOT even if the slots are served by the
+// same node. The pieces are processed as a pipeline, so the pieces of a node are
+// sent in a single round trip.
+func splitClusterCmd(ctx context.Context, cmd Cmder) *clusterSplitCmd {
+	step := 1
+	switch cmd.Name() {
+	case "mget":
+		switch cmd.(type) {
+		case *SliceCmd, *Cmd:
+		default:
+			return nil
+		}
+	case "mset":
+		switch cmd.(type) {
+		case *StatusCmd, *Cmd:
+		default:
+			return nil
+		}
+		step = 2
+	case "del", "exists", "unlink", "touch":
+		switch cmd.(type) {
+		case *IntCmd, *Cmd:
+		default:
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	args := cmd.Args()
+	if len(args) < 1+2*step || (len(args)-1)%step != 0 {
+		return nil
+	}
+
+	var slots []int
+	keysBySlot := make(map[int][]int)
+	for i := 1; i < len(args); i += step {
+		slot := hashtag.Slot(cmd.stringArg(i))
+		if _, ok := keysBySlot[slot]; !ok {
+			slots = append(slots, slot)
+		}
+		keysBySlot[slot] = append(keysBySlot[slot], (i-1)/step)
+	}
+	if len(slots) == 1 {
+		return nil
+	}
+
+	split := &clusterSplitCmd{
+		cmd:     cmd,
+		pieces:  make([]Cmder, len(slots)),
+		indexes: make([][]int, len(slots)),
+		step:    step,
+	}
+	for i, slot := range slots {
+		indexes := keysBySlot[slot]
+		pieceArgs := make([]interface{}, 1, 1+len(indexes)*step)
+		pieceArgs[0] = args[0]
+		for _, idx := range indexes {
+			pieceArgs = append(pieceArgs, args[1+idx*step:1+(idx+1)*step]...)
+		}
+
+		var piece Cmder
+		switch cmd.(type) {
+		case *SliceCmd:
+			piece = NewSliceCmd(ctx, pieceArgs...)
+		case *StatusCmd:
+			piece = NewStatusCmd(ctx, pieceArgs...)
+		case *IntCmd:
+			piece = NewIntCmd(ctx, pieceArgs...)
+		default:
+			piece = NewCmd(ctx, pieceArgs...)
+		}
+		split.pieces[i] = piece
+		split.indexes[i] = indexes
+	}
+	return split
+}
+
+// merge sets the result of the cmd in the original key order.
+func (s *clusterSplitCmd) merge() {
+	var (
+		errs     map[string]error
+		failed   int
+		firstErr error
+		vals     []interface{}
+		sum      int64
+	)
+	keys := (len(s.cmd.Args()) - 1) / s.step
+	name := s.cmd.Name()
+	if name == "mget" {
+		vals = make([]interface{}, keys)
+	}
+
+	for i, piece := range s.pieces {
+		if err := piece.Err(); err != nil {
+			if firstErr == nil {
+				firstErr = err
+				errs = make(map[string]error)
+			}
+			for _, idx := range s.indexes[i] {
+				errs[s.cmd.stringArg(1+idx*s.step)] = err
+			}
+			failed += len(s.indexes[i])
+			continue
+		}
+
+		switch piece := piece.(type) {
+		case *SliceCmd:
+			for j, val := range piece.Val() {
+				vals[s.indexes[i][j]] = val
+			}
+		case *IntCmd:
+			sum += piece.Val()
+		case *Cmd:
+			switch name {
+			case "mget":
+				pieceVals, _ := piece.Slice()
+				for j, val := range pieceVals {
+					vals[s.indexes[i][j]] = val
+				}
+			case "mset":
+			default:
+				n, _ := piece.Int64()
+				sum += n
+			}
+		}
+	}
+
+	switch cmd := s.cmd.(type) {
+	case *SliceCmd:
+		cmd.SetVal(vals)
+	case *StatusCmd:
+		if firstErr == nil {
+			cmd.SetVal("OK")
+		}
+	case *IntCmd:
+		cmd.SetVal(sum)
+	case *Cmd:
+		switch name {
+		case "mget":
+			cmd.SetVal(vals)
+		case "mset":
+			if firstErr == nil {
+				cmd.SetVal("OK")
+			}
+		default:
+			cmd.SetVal(sum)
+		}
+	}
+
+	switch {
+	case firstErr == nil:
+		s.cmd.SetErr(nil)
+	case failed == keys:
+		s.cmd.SetErr(firstErr)
+	default:
+		s.cmd.SetErr(&ClusterPartialError{Errs: errs, Keys: keys})
+	}
+}
+
+// splitClusterCmds replaces the cross-slot multi-key cmds with their pieces.
+func splitClusterCmds(ctx context.Context, cmds []Cmder) ([]Cmder, []*clusterSplitCmd) {
+	var splits []*clusterSplitCmd
+	var out []Cmder
+	for i, cmd := range cmds {
+		split := splitClusterCmd(ctx, cmd)
+		if split == nil {
+			if out != nil {
+				out = append(out, cmd)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]Cmder, 0, len(cmds)+len(split.pieces)), cmds[:i]...)
+		}
+		splits = append(splits, split)
+		out = append(out, split.pieces...)
+	}
+	if out == nil {
+		return cmds, nil
+	}
+	return out, splits
+}
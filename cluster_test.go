@@ -667,6 +667,62 @@ var _ = Describe("ClusterClient", func() {
 			Expect(size).To(Equal(int64(0)))
 		})
 
+		It("scans the keys of all the masters", func() {
+			for i := 0; i < 100; i++ {
+				Expect(client.Set(ctx, "key"+strconv.Itoa(i), "", 0).Err()).NotTo(HaveOccurred())
+			}
+			Expect(client.HSet(ctx, "hash", "field", "value").Err()).NotTo(HaveOccurred())
+
+			var keys []string
+			iter := client.ScanAll(ctx, "key*", 10, "")
+			for iter.Next(ctx) {
+				keys = append(keys, iter.Val())
+			}
+			Expect(iter.Err()).NotTo(HaveOccurred())
+			Expect(keys).To(HaveLen(100))
+			Expect(keys).To(ContainElements("key0", "key99"))
+
+			var mu sync.Mutex
+			keys = nil
+			err := client.ScanAll(ctx, "*", 10, "hash").ForEach(ctx, func(ctx context.Context, key string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				keys = append(keys, key)
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(Equal([]string{"hash"}))
+		})
+
+		It("emits MOVED events", func() {
+			var mu sync.Mutex
+			var events []redis.ClusterEvent
+			client.OnClusterEvent(func(event redis.ClusterEvent) {
+				mu.Lock()
+				defer mu.Unlock()
+				events = append(events, event)
+			})
+
+			Eventually(func() error {
+				return client.SwapNodes(ctx, "A")
+			}, 30*time.Second).ShouldNot(HaveOccurred())
+
+			err := client.Get(ctx, "A").Err()
+			Expect(err).To(Equal(redis.Nil))
+
+			mu.Lock()
+			defer mu.Unlock()
+			var moved []redis.ClusterEvent
+			for _, event := range events {
+				if event.Type == redis.ClusterEventMoved {
+					moved = append(moved, event)
+				}
+			}
+			Expect(moved).NotTo(BeEmpty())
+			Expect(moved[0].Slot).To(Equal(hashtag.Slot("A")))
+			Expect(moved[0].Err).To(HaveOccurred())
+		})
+
 		It("should CLUSTER SLOTS", func() {
 			res, err := client.ClusterSlots(ctx).Result()
 			Expect(err).NotTo(HaveOccurred())
@@ -1366,6 +1422,156 @@ var _ = Describe("ClusterClient with unavailable Cluster", func() {
 	})
 })
 
+var _ = Describe("ClusterClient with cross-slot keys", func() {
+	// The keys "A" and "D" belong to the slots 0-8191, "B" and "C" to 8192-16383.
+	newClusterClient := func(addr string) *redis.ClusterClient {
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			ClusterSlots: func(ctx context.Context) ([]redis.ClusterSlot, error) {
+				return []redis.ClusterSlot{
+					{Start: 0, End: 8191, Nodes: []redis.ClusterNode{{Addr: redisAddr}}},
+					{Start: 8192, End: 16383, Nodes: []redis.ClusterNode{{Addr: addr}}},
+				}, nil
+			},
+			DialTimeout:  100 * time.Millisecond,
+			MaxRedirects: -1,
+		})
+	}
+
+	var client *redis.ClusterClient
+
+	BeforeEach(func() {
+		rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+		defer rdb.Close()
+		Expect(rdb.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should split multi-key commands by slot", func() {
+		client = newClusterClient(redisAddr)
+
+		err := client.MSet(ctx, "A", "a", "B", "b", "C", "c", "D", "d").Err()
+		Expect(err).NotTo(HaveOccurred())
+
+		vals, err := client.MGet(ctx, "B", "A", "missing", "C").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vals).To(Equal([]interface{}{"b", "a", nil, "c"}))
+
+		Expect(client.Exists(ctx, "A", "B", "C", "missing").Val()).To(Equal(int64(3)))
+		Expect(client.Touch(ctx, "A", "B").Val()).To(Equal(int64(2)))
+		Expect(client.Del(ctx, "A", "B").Val()).To(Equal(int64(2)))
+		Expect(client.Unlink(ctx, "C", "D").Val()).To(Equal(int64(2)))
+
+		pipe := client.Pipeline()
+		mset := pipe.MSet(ctx, "A", "a", "B", "b")
+		get := pipe.Get(ctx, "A")
+		mget := pipe.MGet(ctx, "A", "B")
+		_, err = pipe.Exec(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mset.Val()).To(Equal("OK"))
+		Expect(get.Val()).To(Equal("a"))
+		Expect(mget.Val()).To(Equal([]interface{}{"a", "b"}))
+
+		Expect(client.Do(ctx, "MSET", "C", "c", "D", "d").Val()).To(Equal("OK"))
+		Expect(client.Do(ctx, "MGET", "D", "missing", "A").Val()).To(Equal([]interface{}{"d", nil, "a"}))
+		Expect(client.Do(ctx, "DEL", "A", "B", "C").Val()).To(Equal(int64(3)))
+	})
+
+	It("should report partial failures", func() {
+		client = newClusterClient("127.0.0.1:1")
+
+		Expect(client.Set(ctx, "A", "a", 0).Err()).NotTo(HaveOccurred())
+
+		vals, err := client.MGet(ctx, "A", "B").Result()
+		var partialErr *redis.ClusterPartialError
+		Expect(errors.As(err, &partialErr)).To(BeTrue())
+		Expect(partialErr.Keys).To(Equal(2))
+		Expect(partialErr.Errs).To(HaveLen(1))
+		Expect(partialErr.Errs).To(HaveKey("B"))
+		Expect(vals).To(Equal([]interface{}{"a", nil}))
+
+		n, err := client.Del(ctx, "A", "B").Result()
+		Expect(errors.As(err, &partialErr)).To(BeTrue())
+		Expect(n).To(Equal(int64(1)))
+
+		err = client.Del(ctx, "B", "C").Err()
+		Expect(err).To(HaveOccurred())
+		Expect(errors.As(err, &partialErr)).To(BeFalse())
+	})
+})
+
+var _ = Describe("ClusterClient topology events", func() {
+	It("should notify about topology changes and nodes", func() {
+		var mu sync.Mutex
+		split := false
+		var changes [][2]redis.ClusterTopology
+		var events []redis.ClusterEvent
+
+		client := redis.NewClusterClient(&redis.ClusterOptions{
+			ClusterSlots: func(ctx context.Context) ([]redis.ClusterSlot, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if !split {
+					return []redis.ClusterSlot{
+						{Start: 0, End: 16383, Nodes: []redis.ClusterNode{{Addr: redisAddr}}},
+					}, nil
+				}
+				return []redis.ClusterSlot{
+					{Start: 0, End: 8191, Nodes: []redis.ClusterNode{{Addr: redisAddr}}},
+					{Start: 8192, End: 16383, Nodes: []redis.ClusterNode{{Addr: "127.0.0.1" + redisAddr}}},
+				}, nil
+			},
+		})
+		defer client.Close()
+
+		client.OnTopologyChange(func(old, new redis.ClusterTopology) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, [2]redis.ClusterTopology{old, new})
+		})
+		client.OnClusterEvent(func(event redis.ClusterEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+		})
+
+		Expect(client.Ping(ctx).Err()).NotTo(HaveOccurred())
+
+		mu.Lock()
+		Expect(changes).To(BeEmpty())
+		split = true
+		mu.Unlock()
+
+		Eventually(func() int {
+			client.ReloadState(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			return len(changes)
+		}).Should(Equal(1))
+
+		mu.Lock()
+		defer mu.Unlock()
+		old, new := changes[0][0], changes[0][1]
+		Expect(old.Slots).To(Equal([]redis.ClusterTopologySlot{
+			{Start: 0, End: 16383, Master: redisAddr, Replicas: []string{}},
+		}))
+		Expect(new.Slots).To(Equal([]redis.ClusterTopologySlot{
+			{Start: 0, End: 8191, Master: redisAddr, Replicas: []string{}},
+			{Start: 8192, End: 16383, Master: "127.0.0.1" + redisAddr, Replicas: []string{}},
+		}))
+		Expect(new.Masters).To(Equal([]string{redisAddr, "127.0.0.1" + redisAddr}))
+		Expect(new.Generation).To(BeNumerically(">", old.Generation))
+
+		Expect(events).To(ContainElement(redis.ClusterEvent{
+			Type: redis.ClusterEventNodeAdded,
+			Addr: "127.0.0.1" + redisAddr,
+			Slot: -1,
+		}))
+	})
+})
+
 var _ = Describe("ClusterClient timeout", func() {
 	var client *redis.ClusterClient
 
@@ -0,0 +1,180 @@
+package redis
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClusterTopology is a read-only snapshot of the cluster state of ClusterClient.
+type ClusterTopology struct {
+	Slots    []ClusterTopologySlot
+	Masters  []string
+	Replicas []string
+
+	// Generation is incremented every time the cluster state is loaded.
+	Generation uint32
+	CreatedAt  time.Time
+}
+
+// ClusterTopologySlot is a range of slots served by the master and the replicas.
+type ClusterTopologySlot struct {
+	Start    int
+	End      int
+	Master   string
+	Replicas []string
+}
+
+func (c *clusterState) topology() ClusterTopology {
+	t := ClusterTopology{
+		Slots:      make([]ClusterTopologySlot, 0, len(c.slots)),
+		Masters:    clusterNodeAddrs(c.Masters),
+		Replicas:   clusterNodeAddrs(c.Slaves),
+		Generation: c.generation,
+		CreatedAt:  c.createdAt,
+	}
+	for _, slot := range c.slots {
+		s := ClusterTopologySlot{
+			Start: slot.start,
+			End:   slot.end,
+		}
+		if len(slot.nodes) > 0 {
+			s.Master = slot.nodes[0].Client.opt.Addr
+			s.Replicas = clusterNodeAddrs(slot.nodes[1:])
+		}
+		t.Slots = append(t.Slots, s)
+	}
+	return t
+}
+
+func clusterNodeAddrs(nodes []*clusterNode) []string {
+	addrs := make([]string, len(nodes))
+	for i, node := range nodes {
+		addrs[i] = node.Client.opt.Addr
+	}
+	return addrs
+}
+
+//------------------------------------------------------------------------------
+
+// ClusterEventType is the type of ClusterEvent.
+type ClusterEventType int
+
+const (
+	// ClusterEventMoved is emitted when a node redirects a command with a MOVED error.
+	ClusterEventMoved ClusterEventType = iota + 1
+	// ClusterEventAsk is emitted when a node redirects a command with an ASK error
+	// during a slot migration.
+	ClusterEventAsk
+	// ClusterEventNodeAdded is emitted when the client connects to a new node.
+	ClusterEventNodeAdded
+	// ClusterEventNodeRemoved is emitted when the client closes a node that
+	// is no longer in the cluster.
+	ClusterEventNodeRemoved
+	// ClusterEventNodeFailing is emitted when a node is marked as failing, so the
+	// read-only commands avoid it for a while.
+	ClusterEventNodeFailing
+)
+
+func (t ClusterEventType) String() string {
+	switch t {
+	case ClusterEventMoved:
+		return "moved"
+	case ClusterEventAsk:
+		return "ask"
+	case ClusterEventNodeAdded:
+		return "node added"
+	case ClusterEventNodeRemoved:
+		return "node removed"
+	case ClusterEventNodeFailing:
+		return "node failing"
+	default:
+		return "unknown"
+	}
+}
+
+// ClusterEvent is an event of ClusterClient, e.g. a redirect.
+type ClusterEvent struct {
+	Type ClusterEventType
+	// Addr is the address of the node or, for the redirects, of the target node.
+	Addr string
+	// Slot is the redirected slot or -1.
+	Slot int
+	// Err is the redirect or the error that marked the node as failing.
+	Err error
+}
+
+// clusterEvents holds the OnTopologyChange and OnClusterEvent callbacks.
+type clusterEvents struct {
+	mu       sync.RWMutex
+	onChange []func(old, new ClusterTopology)
+	onEvent  []func(event ClusterEvent)
+}
+
+func (e *clusterEvents) OnTopologyChange(fn func(old, new ClusterTopology)) {
+	e.mu.Lock()
+	e.onChange = append(e.onChange, fn)
+	e.mu.Unlock()
+}
+
+func (e *clusterEvents) OnClusterEvent(fn func(event ClusterEvent)) {
+	e.mu.Lock()
+	e.onEvent = append(e.onEvent, fn)
+	e.mu.Unlock()
+}
+
+// stateChanged calls the OnTopologyChange callbacks if the slots moved
+// between the old and the new state.
+func (e *clusterEvents) stateChanged(old, new *clusterState) {
+	e.mu.RLock()
+	onChange := e.onChange
+	e.mu.RUnlock()
+
+	if len(onChange) == 0 || old == nil || old == new {
+		return
+	}
+
+	oldTopology, newTopology := old.topology(), new.topology()
+	if reflect.DeepEqual(oldTopology.Slots, newTopology.Slots) {
+		return
+	}
+	for _, fn := range onChange {
+		fn(oldTopology, newTopology)
+	}
+}
+
+func (e *clusterEvents) emit(event ClusterEvent) {
+	e.mu.RLock()
+	onEvent := e.onEvent
+	e.mu.RUnlock()
+
+	for _, fn := range onEvent {
+		fn(event)
+	}
+}
+
+// redirected emits ClusterEventMoved or ClusterEventAsk for the MOVED or ASK error.
+func (e *clusterEvents) redirected(err error, moved, ask bool, addr string) {
+	if !moved && !ask {
+		return
+	}
+
+	event := ClusterEvent{
+		Type: ClusterEventMoved,
+		Addr: addr,
+		Slot: -1,
+		Err:  err,
+	}
+	if ask {
+		event.Type = ClusterEventAsk
+	}
+	// The error is "MOVED 3999 127.0.0.1:6381".
+	if fields := strings.Fields(err.Error()); len(fields) == 3 {
+		if slot, err := strconv.Atoi(fields[1]); err == nil {
+			event.Slot = slot
+		}
+	}
+	e.emit(event)
+}
@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec marshals the values that have no built-in Redis encoding,
+// e.g. structs, maps and slices, and unmarshals them from the replies.
+// Strings, numbers, booleans, time.Time, time.Duration and the types implementing
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler use the built-in encoding.
+// The codec of a client is set with Options.Codec; the typed commands also accept
+// a codec set with WithCodec.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec marshals the values as JSON.
+type JSONCodec struct{}
+
+var _ Codec = JSONCodec{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// GobCodec marshals the values with encoding/gob.
+type GobCodec struct{}
+
+var _ Codec = GobCodec{}
+
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"strconv"
 	"strings"
@@ -26,10 +27,24 @@ type Cmder interface {
 	readTimeout() *time.Duration
 	readReply(rd *proto.Reader) error
 
+	Attributes() map[string]interface{}
+	setAttributes(map[string]interface{})
+
+	setCodec(Codec)
+
 	SetErr(error)
 	Err() error
 }
 
+// readCmdReply reads the cmd reply and sets the attributes of the reply, if any.
+func readCmdReply(rd *proto.Reader, cmd Cmder) error {
+	err := cmd.readReply(rd)
+	if attrs := rd.Attributes(); attrs != nil {
+		cmd.setAttributes(attrs)
+	}
+	return err
+}
+
 func setCmdsErr(cmds []Cmder, e error) {
 	for _, cmd := range cmds {
 		if cmd.Err() == nil {
@@ -57,6 +72,9 @@ func writeCmds(wr *proto.Writer, cmds []Cmder) error {
 }
 
 func writeCmd(wr *proto.Writer, cmd Cmder) error {
+	if codec := wr.Codec(); codec != nil {
+		cmd.setCodec(codec)
+	}
 	return wr.WriteArgs(cmd.Args())
 }
 
@@ -115,6 +133,8 @@ type baseCmd struct {
 	args   []interface{}
 	err    error
 	keyPos int8
+	attrs  map[string]interface{}
+	codec  Codec
 
 	_readTimeout *time.Duration
 }
@@ -178,6 +198,29 @@ func (cmd *baseCmd) Err() error {
 	return cmd.err
 }
 
+// Attributes returns the RESP3 attributes sent by the server along with the reply.
+// Attributes are collected only if Options.AttributesEnabled is set.
+func (cmd *baseCmd) Attributes() map[string]interface{} {
+	return cmd.attrs
+}
+
+func (cmd *baseCmd) setAttributes(attrs map[string]interface{}) {
+	cmd.attrs = attrs
+}
+
+// setCodec sets the codec of the client that processes the cmd, see Options.Codec.
+func (cmd *baseCmd) setCodec(codec Codec) {
+	cmd.codec = codec
+}
+
+// scan scans b into dst using the codec for the types that proto.Scan doesn't support.
+func (cmd *baseCmd) scan(b []byte, dst interface{}) error {
+	if cmd.codec != nil && !proto.Scannable(dst) {
+		return cmd.codec.Unmarshal(b, dst)
+	}
+	return proto.Scan(b, dst)
+}
+
 func (cmd *baseCmd) readTimeout() *time.Duration {
 	return cmd._readTimeout
 }
@@ -226,6 +269,19 @@ func (cmd *Cmd) Text() (string, error) {
 	return toString(cmd.val)
 }
 
+// Scan scans the string reply into dst. The types that have no built-in decoding,
+// e.g. structs, are unmarshaled by Options.Codec.
+func (cmd *Cmd) Scan(dst interface{}) error {
+	if cmd.err != nil {
+		return cmd.err
+	}
+	s, err := toString(cmd.val)
+	if err != nil {
+		return err
+	}
+	return cmd.scan([]byte(s), dst)
+}
+
 func toString(val interface{}) (string, error) {
 	switch val := val.(type) {
 	case string:
@@ -323,6 +379,8 @@ func toFloat64(val interface{}) (float64, error) {
 	switch val := val.(type) {
 	case int64:
 		return float64(val), nil
+	case float64:
+		return val, nil
 	case string:
 		return strconv.ParseFloat(val, 64)
 	default:
@@ -352,6 +410,27 @@ func toBool(val interface{}) (bool, error) {
 	}
 }
 
+// replyMap converts a RESP3 map or a RESP2 array of key-value pairs,
+// as read by ReadReply, to a map. It returns nil for other values.
+func replyMap(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, v := range v {
+			m[fmt.Sprint(k)] = v
+		}
+		return m
+	case []interface{}:
+		m := make(map[string]interface{}, len(v)/2)
+		for i := 0; i+1 < len(v); i += 2 {
+			m[fmt.Sprint(v[i])] = v[i+1]
+		}
+		return m
+	default:
+		return nil
+	}
+}
+
 func (cmd *Cmd) Slice() ([]interface{}, error) {
 	if cmd.err != nil {
 		return nil, cmd.err
@@ -617,6 +696,55 @@ func (cmd *IntCmd) readReply(rd *proto.Reader) (err error) {
 
 //------------------------------------------------------------------------------
 
+// WriterCmd copies a string reply to an io.Writer without buffering the whole value.
+// The connection is kept checked out until the value is copied.
+type WriterCmd struct {
+	baseCmd
+
+	w   io.Writer
+	val int64
+}
+
+var _ Cmder = (*WriterCmd)(nil)
+
+func NewWriterCmd(ctx context.Context, w io.Writer, args ...interface{}) *WriterCmd {
+	return &WriterCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+		w: w,
+	}
+}
+
+func (cmd *WriterCmd) SetVal(val int64) {
+	cmd.val = val
+}
+
+// Val returns the number of bytes written.
+func (cmd *WriterCmd) Val() int64 {
+	return cmd.val
+}
+
+func (cmd *WriterCmd) Result() (int64, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *WriterCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *WriterCmd) readReply(rd *proto.Reader) (err error) {
+	cmd.val, err = rd.ReadStringTo(cmd.w)
+	if err != nil && cmd.val > 0 {
+		// The value is partially written, so the command must not be retried.
+		return fmt.Errorf("redis: value is partially written (%d bytes): %w", cmd.val, err)
+	}
+	return err
+}
+
+//------------------------------------------------------------------------------
+
 type IntSliceCmd struct {
 	baseCmd
 
@@ -666,6 +794,90 @@ func (cmd *IntSliceCmd) readReply(rd *proto.Reader) error {
 
 //------------------------------------------------------------------------------
 
+// HFieldStatus is the per-field result of HEXPIRE, HPEXPIRE, HEXPIREAT, HPEXPIREAT and HPERSIST.
+type HFieldStatus int64
+
+const (
+	// HFieldMissing means the field or the hash does not exist.
+	HFieldMissing HFieldStatus = -2
+	// HFieldNoExpiration means HPERSIST found no expiration to remove.
+	HFieldNoExpiration HFieldStatus = -1
+	// HFieldConditionNotMet means the NX, XX, GT or LT condition was not met.
+	HFieldConditionNotMet HFieldStatus = 0
+	// HFieldUpdated means the expiration was set or, for HPERSIST, removed.
+	HFieldUpdated HFieldStatus = 1
+	// HFieldDeleted means the field was deleted because the expiration was in the past.
+	HFieldDeleted HFieldStatus = 2
+)
+
+func (s HFieldStatus) String() string {
+	switch s {
+	case HFieldMissing:
+		return "missing"
+	case HFieldNoExpiration:
+		return "no expiration"
+	case HFieldConditionNotMet:
+		return "condition not met"
+	case HFieldUpdated:
+		return "updated"
+	case HFieldDeleted:
+		return "deleted"
+	default:
+		return "HFieldStatus(" + strconv.FormatInt(int64(s), 10) + ")"
+	}
+}
+
+type HFieldStatusSliceCmd struct {
+	baseCmd
+
+	val []HFieldStatus
+}
+
+var _ Cmder = (*HFieldStatusSliceCmd)(nil)
+
+func NewHFieldStatusSliceCmd(ctx context.Context, args ...interface{}) *HFieldStatusSliceCmd {
+	return &HFieldStatusSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *HFieldStatusSliceCmd) SetVal(val []HFieldStatus) {
+	cmd.val = val
+}
+
+func (cmd *HFieldStatusSliceCmd) Val() []HFieldStatus {
+	return cmd.val
+}
+
+func (cmd *HFieldStatusSliceCmd) Result() ([]HFieldStatus, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *HFieldStatusSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *HFieldStatusSliceCmd) readReply(rd *proto.Reader) error {
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	cmd.val = make([]HFieldStatus, n)
+	for i := 0; i < len(cmd.val); i++ {
+		status, err := rd.ReadInt()
+		if err != nil {
+			return err
+		}
+		cmd.val[i] = HFieldStatus(status)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
 type DurationCmd struct {
 	baseCmd
 
@@ -903,11 +1115,13 @@ func (cmd *StringCmd) Time() (time.Time, error) {
 	return time.Parse(time.RFC3339Nano, cmd.Val())
 }
 
+// Scan scans the reply into val. The types that have no built-in decoding,
+// e.g. structs, are unmarshaled by Options.Codec.
 func (cmd *StringCmd) Scan(val interface{}) error {
 	if cmd.err != nil {
 		return cmd.err
 	}
-	return proto.Scan([]byte(cmd.val), val)
+	return cmd.scan([]byte(cmd.val), val)
 }
 
 func (cmd *StringCmd) String() string {
@@ -4660,6 +4874,7 @@ type Node struct {
 	Role              string
 	ReplicationOffset int64
 	Health            string
+	AvailabilityZone  string
 }
 
 type ClusterShard struct {
@@ -4775,8 +4990,11 @@ func (cmd *ClusterShardsCmd) readReply(rd *proto.Reader) error {
 							cmd.val[i].Nodes[k].ReplicationOffset, err = rd.ReadInt()
 						case "health":
 							cmd.val[i].Nodes[k].Health, err = rd.ReadString()
+						case "availability-zone":
+							cmd.val[i].Nodes[k].AvailabilityZone, err = rd.ReadString()
 						default:
-							return fmt.Errorf("redis: unexpected key %q in CLUSTER SHARDS node reply", nodeKey)
+							// Skip the fields added by newer servers.
+							err = rd.DiscardNext()
 						}
 
 						if err != nil {
@@ -5233,3 +5451,482 @@ func (cmd *ACLLogCmd) readReply(rd *proto.Reader) error {
 
 	return nil
 }
+
+// -------------------------------------------
+
+// ACLSelector is a set of the key, channel and command rules of the user.
+// The rules use the ACL SETUSER syntax, e.g. "~cache:* %R~data:*".
+type ACLSelector struct {
+	Commands string
+	Keys     string
+	Channels string
+}
+
+type ACLUser struct {
+	Flags     []string
+	Passwords []string
+	ACLSelector
+	Selectors []ACLSelector
+}
+
+type ACLUserCmd struct {
+	baseCmd
+
+	val *ACLUser
+}
+
+var _ Cmder = (*ACLUserCmd)(nil)
+
+func NewACLUserCmd(ctx context.Context, args ...interface{}) *ACLUserCmd {
+	return &ACLUserCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *ACLUserCmd) SetVal(val *ACLUser) {
+	cmd.val = val
+}
+
+func (cmd *ACLUserCmd) Val() *ACLUser {
+	return cmd.val
+}
+
+func (cmd *ACLUserCmd) Result() (*ACLUser, error) {
+	return cmd.Val(), cmd.Err()
+}
+
+func (cmd *ACLUserCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *ACLUserCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	m := replyMap(v)
+	if m == nil {
+		return fmt.Errorf("redis: unexpected ACL GETUSER reply type %T", v)
+	}
+
+	user := &ACLUser{
+		Flags:       aclStrings(m["flags"]),
+		Passwords:   aclStrings(m["passwords"]),
+		ACLSelector: parseACLSelector(m),
+	}
+	if selectors, ok := m["selectors"].([]interface{}); ok {
+		user.Selectors = make([]ACLSelector, 0, len(selectors))
+		for _, selector := range selectors {
+			user.Selectors = append(user.Selectors, parseACLSelector(replyMap(selector)))
+		}
+	}
+	cmd.val = user
+	return nil
+}
+
+func parseACLSelector(m map[string]interface{}) ACLSelector {
+	return ACLSelector{
+		Commands: aclRules(m["commands"]),
+		Keys:     aclRules(m["keys"]),
+		Channels: aclRules(m["channels"]),
+	}
+}
+
+// aclRules returns the rules that are a string in Redis 7 and a list of the patterns in Redis 6.
+func aclRules(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []interface{}:
+		return strings.Join(aclStrings(v), " ")
+	default:
+		return ""
+	}
+}
+
+func aclStrings(v interface{}) []string {
+	vals, _ := v.([]interface{})
+	ss := make([]string, 0, len(vals))
+	for _, val := range vals {
+		ss = append(ss, fmt.Sprint(val))
+	}
+	return ss
+}
+
+// -------------------------------------------
+
+// MemoryDBStats is the memory overhead of the hash tables of a database.
+type MemoryDBStats struct {
+	OverheadHashtableMain    int64
+	OverheadHashtableExpires int64
+}
+
+// MemoryStats is the reply of MEMORY STATS. The sizes are in bytes.
+type MemoryStats struct {
+	PeakAllocated               int64
+	TotalAllocated              int64
+	StartupAllocated            int64
+	ReplicationBacklog          int64
+	ClientsReplicas             int64
+	ClientsNormal               int64
+	ClusterLinks                int64
+	AOFBuffer                   int64
+	LuaCaches                   int64
+	FunctionsCaches             int64
+	OverheadTotal               int64
+	KeysCount                   int64
+	KeysBytesPerKey             int64
+	DatasetBytes                int64
+	DatasetPercentage           float64
+	PeakPercentage              float64
+	AllocatorAllocated          int64
+	AllocatorActive             int64
+	AllocatorResident           int64
+	AllocatorFragmentationRatio float64
+	AllocatorFragmentationBytes int64
+	AllocatorRSSRatio           float64
+	AllocatorRSSBytes           int64
+	RSSOverheadRatio            float64
+	RSSOverheadBytes            int64
+	Fragmentation               float64
+	FragmentationBytes          int64
+	// DBs is the overhead of each non-empty database by the database index.
+	DBs map[int]MemoryDBStats
+}
+
+type MemoryStatsCmd struct {
+	baseCmd
+
+	val *MemoryStats
+}
+
+var _ Cmder = (*MemoryStatsCmd)(nil)
+
+func NewMemoryStatsCmd(ctx context.Context, args ...interface{}) *MemoryStatsCmd {
+	return &MemoryStatsCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *MemoryStatsCmd) SetVal(val *MemoryStats) {
+	cmd.val = val
+}
+
+func (cmd *MemoryStatsCmd) Val() *MemoryStats {
+	return cmd.val
+}
+
+func (cmd *MemoryStatsCmd) Result() (*MemoryStats, error) {
+	return cmd.Val(), cmd.Err()
+}
+
+func (cmd *MemoryStatsCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *MemoryStatsCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	m := replyMap(v)
+	if m == nil {
+		return fmt.Errorf("redis: unexpected MEMORY STATS reply type %T", v)
+	}
+
+	stats := &MemoryStats{DBs: make(map[int]MemoryDBStats)}
+	ints := map[string]*int64{
+		"peak.allocated":                &stats.PeakAllocated,
+		"total.allocated":               &stats.TotalAllocated,
+		"startup.allocated":             &stats.StartupAllocated,
+		"replication.backlog":           &stats.ReplicationBacklog,
+		"clients.slaves":                &stats.ClientsReplicas,
+		"clients.normal":                &stats.ClientsNormal,
+		"cluster.links":                 &stats.ClusterLinks,
+		"aof.buffer":                    &stats.AOFBuffer,
+		"lua.caches":                    &stats.LuaCaches,
+		"functions.caches":              &stats.FunctionsCaches,
+		"overhead.total":                &stats.OverheadTotal,
+		"keys.count":                    &stats.KeysCount,
+		"keys.bytes-per-key":            &stats.KeysBytesPerKey,
+		"dataset.bytes":                 &stats.DatasetBytes,
+		"allocator.allocated":           &stats.AllocatorAllocated,
+		"allocator.active":              &stats.AllocatorActive,
+		"allocator.resident":            &stats.AllocatorResident,
+		"allocator-fragmentation.bytes": &stats.AllocatorFragmentationBytes,
+		"allocator-rss.bytes":           &stats.AllocatorRSSBytes,
+		"rss-overhead.bytes":            &stats.RSSOverheadBytes,
+		"fragmentation.bytes":           &stats.FragmentationBytes,
+	}
+	floats := map[string]*float64{
+		"dataset.percentage":            &stats.DatasetPercentage,
+		"peak.percentage":               &stats.PeakPercentage,
+		"allocator-fragmentation.ratio": &stats.AllocatorFragmentationRatio,
+		"allocator-rss.ratio":           &stats.AllocatorRSSRatio,
+		"rss-overhead.ratio":            &stats.RSSOverheadRatio,
+		"fragmentation":                 &stats.Fragmentation,
+	}
+
+	for key, val := range m {
+		if p, ok := ints[key]; ok {
+			if *p, err = toInt64(val); err != nil {
+				return fmt.Errorf("redis: MEMORY STATS %s: %w", key, err)
+			}
+			continue
+		}
+		if p, ok := floats[key]; ok {
+			if *p, err = toFloat64(val); err != nil {
+				return fmt.Errorf("redis: MEMORY STATS %s: %w", key, err)
+			}
+			continue
+		}
+		if strings.HasPrefix(key, "db.") {
+			db, err := strconv.Atoi(key[len("db."):])
+			if err != nil {
+				continue
+			}
+			overhead := replyMap(val)
+			var dbStats MemoryDBStats
+			dbStats.OverheadHashtableMain, _ = toInt64(overhead["overhead.hashtable.main"])
+			dbStats.OverheadHashtableExpires, _ = toInt64(overhead["overhead.hashtable.expires"])
+			stats.DBs[db] = dbStats
+		}
+	}
+
+	cmd.val = stats
+	return nil
+}
+
+// -------------------------------------------
+
+// LatencyEvent is the latest latency spike of an event.
+type LatencyEvent struct {
+	Name   string
+	Time   time.Time
+	Latest time.Duration
+	Max    time.Duration
+}
+
+type LatencyEventsCmd struct {
+	baseCmd
+
+	val []LatencyEvent
+}
+
+var _ Cmder = (*LatencyEventsCmd)(nil)
+
+func NewLatencyEventsCmd(ctx context.Context, args ...interface{}) *LatencyEventsCmd {
+	return &LatencyEventsCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *LatencyEventsCmd) SetVal(val []LatencyEvent) {
+	cmd.val = val
+}
+
+func (cmd *LatencyEventsCmd) Val() []LatencyEvent {
+	return cmd.val
+}
+
+func (cmd *LatencyEventsCmd) Result() ([]LatencyEvent, error) {
+	return cmd.Val(), cmd.Err()
+}
+
+func (cmd *LatencyEventsCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *LatencyEventsCmd) readReply(rd *proto.Reader) error {
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	cmd.val = make([]LatencyEvent, n)
+	for i := 0; i < n; i++ {
+		nn, err := rd.ReadArrayLen()
+		if err != nil {
+			return err
+		}
+		if nn < 4 {
+			return fmt.Errorf("redis: got %d elements in LATENCY LATEST reply, wanted at least 4", nn)
+		}
+
+		event := &cmd.val[i]
+		if event.Name, err = rd.ReadString(); err != nil {
+			return err
+		}
+		ts, err := rd.ReadInt()
+		if err != nil {
+			return err
+		}
+		event.Time = time.Unix(ts, 0)
+		latest, err := rd.ReadInt()
+		if err != nil {
+			return err
+		}
+		event.Latest = time.Duration(latest) * time.Millisecond
+		max, err := rd.ReadInt()
+		if err != nil {
+			return err
+		}
+		event.Max = time.Duration(max) * time.Millisecond
+
+		// Skip the fields added by newer servers.
+		for j := 4; j < nn; j++ {
+			if err := rd.DiscardNext(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// -------------------------------------------
+
+// LatencySample is a latency spike of an event.
+type LatencySample struct {
+	Time    time.Time
+	Latency time.Duration
+}
+
+type LatencySamplesCmd struct {
+	baseCmd
+
+	val []LatencySample
+}
+
+var _ Cmder = (*LatencySamplesCmd)(nil)
+
+func NewLatencySamplesCmd(ctx context.Context, args ...interface{}) *LatencySamplesCmd {
+	return &LatencySamplesCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *LatencySamplesCmd) SetVal(val []LatencySample) {
+	cmd.val = val
+}
+
+func (cmd *LatencySamplesCmd) Val() []LatencySample {
+	return cmd.val
+}
+
+func (cmd *LatencySamplesCmd) Result() ([]LatencySample, error) {
+	return cmd.Val(), cmd.Err()
+}
+
+func (cmd *LatencySamplesCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *LatencySamplesCmd) readReply(rd *proto.Reader) error {
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	cmd.val = make([]LatencySample, n)
+	for i := 0; i < n; i++ {
+		if err = rd.ReadFixedArrayLen(2); err != nil {
+			return err
+		}
+		ts, err := rd.ReadInt()
+		if err != nil {
+			return err
+		}
+		latency, err := rd.ReadInt()
+		if err != nil {
+			return err
+		}
+		cmd.val[i] = LatencySample{
+			Time:    time.Unix(ts, 0),
+			Latency: time.Duration(latency) * time.Millisecond,
+		}
+	}
+	return nil
+}
+
+// -------------------------------------------
+
+// LatencyHistogram is the latency histogram of a command. Buckets maps the upper bound
+// of each bucket, a power of two of microseconds, to the cumulative number of calls.
+type LatencyHistogram struct {
+	Calls   int64
+	Buckets map[time.Duration]int64
+}
+
+type LatencyHistogramCmd struct {
+	baseCmd
+
+	val map[string]LatencyHistogram
+}
+
+var _ Cmder = (*LatencyHistogramCmd)(nil)
+
+func NewLatencyHistogramCmd(ctx context.Context, args ...interface{}) *LatencyHistogramCmd {
+	return &LatencyHistogramCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *LatencyHistogramCmd) SetVal(val map[string]LatencyHistogram) {
+	cmd.val = val
+}
+
+func (cmd *LatencyHistogramCmd) Val() map[string]LatencyHistogram {
+	return cmd.val
+}
+
+func (cmd *LatencyHistogramCmd) Result() (map[string]LatencyHistogram, error) {
+	return cmd.Val(), cmd.Err()
+}
+
+func (cmd *LatencyHistogramCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *LatencyHistogramCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	m := replyMap(v)
+	if m == nil {
+		return fmt.Errorf("redis: unexpected LATENCY HISTOGRAM reply type %T", v)
+	}
+
+	cmd.val = make(map[string]LatencyHistogram, len(m))
+	for name, val := range m {
+		fields := replyMap(val)
+		h := LatencyHistogram{Buckets: make(map[time.Duration]int64)}
+		if h.Calls, err = toInt64(fields["calls"]); err != nil {
+			return err
+		}
+		for usec, calls := range replyMap(fields["histogram_usec"]) {
+			bound, err := strconv.ParseInt(usec, 10, 64)
+			if err != nil {
+				return err
+			}
+			if h.Buckets[time.Duration(bound)*time.Microsecond], err = toInt64(calls); err != nil {
+				return err
+			}
+		}
+		cmd.val[name] = h
+	}
+	return nil
+}
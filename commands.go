@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/hscan"
+	"github.com/redis/go-redis/v9/internal/proto"
 )
 
 // KeepTTL is a Redis KEEPTTL option to keep existing TTL, it requires your redis-server version >= 6.0,
@@ -99,60 +103,69 @@ func appendArg(dst []interface{}, arg interface{}) []interface{} {
 }
 
 // appendStructField appends the field and value held by the structure v to dst, and returns the appended dst.
+// See hscan.EncodeStruct for the supported field types and tag options.
 func appendStructField(dst []interface{}, v reflect.Value) []interface{} {
-	typ := v.Type()
-	for i := 0; i < typ.NumField(); i++ {
-		tag := typ.Field(i).Tag.Get("redis")
-		if tag == "" || tag == "-" {
-			continue
-		}
-		name, opt, _ := strings.Cut(tag, ",")
-		if name == "" {
-			continue
-		}
+	fields, err := hscan.EncodeStruct(v)
+	if err != nil {
+		// The error is returned when the command is written.
+		return append(dst, errArg{err: err})
+	}
+	for _, field := range fields {
+		dst = append(dst, field.Name, field.Value)
+	}
+	return dst
+}
 
-		field := v.Field(i)
+// errArg is an argument that fails to be written with the error.
+type errArg struct {
+	err error
+}
 
-		// miss field
-		if omitEmpty(opt) && isEmptyValue(field) {
-			continue
-		}
+func (a errArg) MarshalBinary() ([]byte, error) {
+	return nil, a.err
+}
 
-		if field.CanInterface() {
-			dst = append(dst, name, field.Interface())
+// hashFieldTTLs returns the fields of the structure in values that are tagged
+// with the ttl option, e.g. `redis:"token,ttl=30s"`, grouped by the TTL.
+func hashFieldTTLs(values []interface{}) (map[time.Duration][]string, error) {
+	if len(values) != 1 {
+		return nil, nil
+	}
+	v := reflect.ValueOf(values[0])
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
 		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	switch values[0].(type) {
+	case time.Time, encoding.BinaryMarshaler:
+		return nil, nil
 	}
 
-	return dst
-}
-
-func omitEmpty(opt string) bool {
-	for opt != "" {
-		var name string
-		name, opt, _ = strings.Cut(opt, ",")
-		if name == "omitempty" {
-			return true
-		}
+	fields, err := hscan.EncodeStruct(v)
+	if err != nil {
+		return nil, err
 	}
-	return false
-}
 
-func isEmptyValue(v reflect.Value) bool {
-	switch v.Kind() {
-	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
-		return v.Len() == 0
-	case reflect.Bool:
-		return !v.Bool()
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return v.Int() == 0
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		return v.Uint() == 0
-	case reflect.Float32, reflect.Float64:
-		return v.Float() == 0
-	case reflect.Interface, reflect.Pointer:
-		return v.IsNil()
+	var ttls map[time.Duration][]string
+	for _, field := range fields {
+		if field.TTL == "" {
+			continue
+		}
+		ttl, err := time.ParseDuration(field.TTL)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("redis: invalid ttl %q of field %q", field.TTL, field.Name)
+		}
+		if ttls == nil {
+			ttls = make(map[time.Duration][]string)
+		}
+		ttls[ttl] = append(ttls[ttl], field.Name)
 	}
-	return false
+	return ttls, nil
 }
 
 type Cmdable interface {
@@ -208,6 +221,7 @@ type Cmdable interface {
 	Decr(ctx context.Context, key string) *IntCmd
 	DecrBy(ctx context.Context, key string, decrement int64) *IntCmd
 	Get(ctx context.Context, key string) *StringCmd
+	GetStream(ctx context.Context, key string, w io.Writer) *WriterCmd
 	GetRange(ctx context.Context, key string, start, end int64) *StringCmd
 	GetSet(ctx context.Context, key string, value interface{}) *StringCmd
 	GetEx(ctx context.Context, key string, expiration time.Duration) *StringCmd
@@ -220,6 +234,7 @@ type Cmdable interface {
 	MSetNX(ctx context.Context, values ...interface{}) *BoolCmd
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *StatusCmd
 	SetArgs(ctx context.Context, key string, value interface{}, a SetArgs) *StatusCmd
+	SetReader(ctx context.Context, key string, r io.Reader, size int64) *StatusCmd
 	SetEx(ctx context.Context, key string, value interface{}, expiration time.Duration) *StatusCmd
 	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *BoolCmd
 	SetXX(ctx context.Context, key string, value interface{}, expiration time.Duration) *BoolCmd
@@ -254,11 +269,25 @@ type Cmdable interface {
 	HLen(ctx context.Context, key string) *IntCmd
 	HMGet(ctx context.Context, key string, fields ...string) *SliceCmd
 	HSet(ctx context.Context, key string, values ...interface{}) *IntCmd
+	HSetWithTTL(ctx context.Context, key string, values ...interface{}) (*IntCmd, []*HFieldStatusSliceCmd)
 	HMSet(ctx context.Context, key string, values ...interface{}) *BoolCmd
 	HSetNX(ctx context.Context, key, field string, value interface{}) *BoolCmd
 	HVals(ctx context.Context, key string) *StringSliceCmd
 	HRandField(ctx context.Context, key string, count int) *StringSliceCmd
 	HRandFieldWithValues(ctx context.Context, key string, count int) *KeyValueSliceCmd
+	HExpire(ctx context.Context, key string, expiration time.Duration, fields ...string) *HFieldStatusSliceCmd
+	HExpireWithArgs(ctx context.Context, key string, expiration time.Duration, expirationArgs HExpireArgs, fields ...string) *HFieldStatusSliceCmd
+	HPExpire(ctx context.Context, key string, expiration time.Duration, fields ...string) *HFieldStatusSliceCmd
+	HPExpireWithArgs(ctx context.Context, key string, expiration time.Duration, expirationArgs HExpireArgs, fields ...string) *HFieldStatusSliceCmd
+	HExpireAt(ctx context.Context, key string, tm time.Time, fields ...string) *HFieldStatusSliceCmd
+	HExpireAtWithArgs(ctx context.Context, key string, tm time.Time, expirationArgs HExpireArgs, fields ...string) *HFieldStatusSliceCmd
+	HPExpireAt(ctx context.Context, key string, tm time.Time, fields ...string) *HFieldStatusSliceCmd
+	HPExpireAtWithArgs(ctx context.Context, key string, tm time.Time, expirationArgs HExpireArgs, fields ...string) *HFieldStatusSliceCmd
+	HPersist(ctx context.Context, key string, fields ...string) *HFieldStatusSliceCmd
+	HExpireTime(ctx context.Context, key string, fields ...string) *IntSliceCmd
+	HPExpireTime(ctx context.Context, key string, fields ...string) *IntSliceCmd
+	HTTL(ctx context.Context, key string, fields ...string) *IntSliceCmd
+	HPTTL(ctx context.Context, key string, fields ...string) *IntSliceCmd
 
 	BLPop(ctx context.Context, timeout time.Duration, keys ...string) *StringSliceCmd
 	BLMPop(ctx context.Context, timeout time.Duration, direction string, count int64, keys ...string) *KeyValuesCmd
@@ -432,6 +461,16 @@ type Cmdable interface {
 	ReadOnly(ctx context.Context) *StatusCmd
 	ReadWrite(ctx context.Context) *StatusCmd
 	MemoryUsage(ctx context.Context, key string, samples ...int) *IntCmd
+	MemoryStats(ctx context.Context) *MemoryStatsCmd
+	MemoryDoctor(ctx context.Context) *StringCmd
+	MemoryMallocStats(ctx context.Context) *StringCmd
+	MemoryPurge(ctx context.Context) *StatusCmd
+	LatencyLatest(ctx context.Context) *LatencyEventsCmd
+	LatencyHistory(ctx context.Context, event string) *LatencySamplesCmd
+	LatencyReset(ctx context.Context, events ...string) *IntCmd
+	LatencyGraph(ctx context.Context, event string) *StringCmd
+	LatencyDoctor(ctx context.Context) *StringCmd
+	LatencyHistogram(ctx context.Context, commands ...string) *LatencyHistogramCmd
 
 	Eval(ctx context.Context, script string, keys []string, args ...interface{}) *Cmd
 	EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *Cmd
@@ -502,11 +541,26 @@ type Cmdable interface {
 	ACLDryRun(ctx context.Context, username string, command ...interface{}) *StringCmd
 	ACLLog(ctx context.Context, count int64) *ACLLogCmd
 	ACLLogReset(ctx context.Context) *StatusCmd
+	ACLSetUser(ctx context.Context, username string, rules ...string) *StatusCmd
+	ACLGetUser(ctx context.Context, username string) *ACLUserCmd
+	ACLDelUser(ctx context.Context, usernames ...string) *IntCmd
+	ACLList(ctx context.Context) *StringSliceCmd
+	ACLUsers(ctx context.Context) *StringSliceCmd
+	ACLWhoAmI(ctx context.Context) *StringCmd
+	ACLGenPass(ctx context.Context, bits int) *StringCmd
+	ACLCat(ctx context.Context) *StringSliceCmd
+	ACLCatArgs(ctx context.Context, category string) *StringSliceCmd
+	ACLSave(ctx context.Context) *StatusCmd
+	ACLLoad(ctx context.Context) *StatusCmd
 
 	ModuleLoadex(ctx context.Context, conf *ModuleLoadexConfig) *StringCmd
 
 	gearsCmdable
 	probabilisticCmdable
+	searchCmdable
+	vectorCmdable
+	timeseriesCmdable
+	JSONCmdable
 }
 
 type StatefulCmdable interface {
@@ -983,6 +1037,14 @@ func (c cmdable) Get(ctx context.Context, key string) *StringCmd {
 	return cmd
 }
 
+// GetStream copies the value of the key to w without buffering the whole value
+// and returns the number of bytes written.
+func (c cmdable) GetStream(ctx context.Context, key string, w io.Writer) *WriterCmd {
+	cmd := NewWriterCmd(ctx, w, "get", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
 func (c cmdable) GetRange(ctx context.Context, key string, start, end int64) *StringCmd {
 	cmd := NewStringCmd(ctx, "getrange", key, start, end)
 	_ = c(ctx, cmd)
@@ -1105,6 +1167,14 @@ func (c cmdable) Set(ctx context.Context, key string, value interface{}, expirat
 	return cmd
 }
 
+// SetReader sets the value of the key to the size bytes read from r
+// without buffering the whole value. The command is retried only if r implements io.Seeker.
+func (c cmdable) SetReader(ctx context.Context, key string, r io.Reader, size int64) *StatusCmd {
+	cmd := NewStatusCmd(ctx, "set", key, proto.NewBulkReader(r, size))
+	_ = c(ctx, cmd)
+	return cmd
+}
+
 // SetArgs provides arguments for the SetArgs function.
 type SetArgs struct {
 	// Mode can be `NX` or `XX` or empty.
@@ -1501,9 +1571,14 @@ func (c cmdable) HMGet(ctx context.Context, key string, fields ...string) *Slice
 //     For struct, can be a structure pointer type, we only parse the field whose tag is redis.
 //     if you don't want the field to be read, you can use the `redis:"-"` flag to ignore it,
 //     or you don't need to set the redis tag.
-//     For the type of structure field, we only support simple data types:
+//     For the type of structure field, we support simple data types:
 //     string, int/uint(8,16,32,64), float(32,64), time.Time(to RFC3339Nano), time.Duration(to Nanoseconds ),
-//     if you are other more complex or custom data types, please implement the encoding.BinaryMarshaler interface.
+//     the types implementing encoding.BinaryMarshaler or encoding.TextMarshaler and the types registered
+//     with RegisterStructCodec. The fields of nested structs are written as "<field>.<nested field>"
+//     and the entries of maps as "<field>.<key>". The formats of time.Time and time.Duration are set with
+//     the format option, e.g. `redis:"created,format=unix"`.
+//
+//     The ttl option of the fields, e.g. `redis:"token,ttl=30s"`, is used only by HSetWithTTL.
 //
 // Note that in older versions of Redis server(redis-server < 4.0), HSet only supports a single key-value pair.
 // redis-docs: https://redis.io/commands/hset (Starting with Redis version 4.0.0: Accepts multiple field and value arguments.)
@@ -1519,6 +1594,48 @@ func (c cmdable) HSet(ctx context.Context, key string, values ...interface{}) *I
 	return cmd
 }
 
+// HSetWithTTL is like HSet, but it also sets the TTLs of the struct fields tagged
+// with the ttl option, e.g. `redis:"token,ttl=30s"`. It sends HSET followed by
+// an HPEXPIRE per TTL and returns the HPEXPIRE commands sorted by the TTL,
+// so their errors are reported separately. With non-pipelined clients, the HPEXPIRE
+// commands are not sent if HSET fails; in pipelines they are queued anyway.
+// Use it with TxPipelined to set the fields and the TTLs atomically.
+// redis-server version >= 7.4.0.
+func (c cmdable) HSetWithTTL(
+	ctx context.Context, key string, values ...interface{},
+) (*IntCmd, []*HFieldStatusSliceCmd) {
+	args := make([]interface{}, 2, 2+len(values))
+	args[0] = "hset"
+	args[1] = key
+	args = appendArgs(args, values)
+	cmd := NewIntCmd(ctx, args...)
+
+	ttls, err := hashFieldTTLs(values)
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd, nil
+	}
+
+	_ = c(ctx, cmd)
+	if len(ttls) == 0 || cmd.Err() != nil {
+		return cmd, nil
+	}
+
+	durations := make([]time.Duration, 0, len(ttls))
+	for ttl := range ttls {
+		durations = append(durations, ttl)
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	expireCmds := make([]*HFieldStatusSliceCmd, len(durations))
+	for i, ttl := range durations {
+		expireCmds[i] = c.HPExpire(ctx, key, ttl, ttls[ttl]...)
+	}
+	return cmd, expireCmds
+}
+
 // HMSet is a deprecated version of HSet left for compatibility with Redis 3.
 func (c cmdable) HMSet(ctx context.Context, key string, values ...interface{}) *BoolCmd {
 	args := make([]interface{}, 2, 2+len(values))
@@ -1556,6 +1673,160 @@ func (c cmdable) HRandFieldWithValues(ctx context.Context, key string, count int
 	return cmd
 }
 
+// HExpireArgs is the condition of setting the expiration of the hash fields.
+// At most one of the options can be set.
+type HExpireArgs struct {
+	NX bool
+	XX bool
+	GT bool
+	LT bool
+}
+
+func (a HExpireArgs) mode() string {
+	switch {
+	case a.NX:
+		return "NX"
+	case a.XX:
+		return "XX"
+	case a.GT:
+		return "GT"
+	case a.LT:
+		return "LT"
+	default:
+		return ""
+	}
+}
+
+// HExpire sets the expiration of the hash fields. It returns the status of each field.
+// redis-server version >= 7.4.0.
+func (c cmdable) HExpire(ctx context.Context, key string, expiration time.Duration, fields ...string) *HFieldStatusSliceCmd {
+	return c.hexpire(ctx, "hexpire", key, formatSec(ctx, expiration), HExpireArgs{}, fields)
+}
+
+// HExpireWithArgs sets the expiration of the hash fields if the condition is met.
+// redis-server version >= 7.4.0.
+func (c cmdable) HExpireWithArgs(
+	ctx context.Context, key string, expiration time.Duration, expirationArgs HExpireArgs, fields ...string,
+) *HFieldStatusSliceCmd {
+	return c.hexpire(ctx, "hexpire", key, formatSec(ctx, expiration), expirationArgs, fields)
+}
+
+// HPExpire sets the expiration of the hash fields in milliseconds.
+// redis-server version >= 7.4.0.
+func (c cmdable) HPExpire(ctx context.Context, key string, expiration time.Duration, fields ...string) *HFieldStatusSliceCmd {
+	return c.hexpire(ctx, "hpexpire", key, formatMs(ctx, expiration), HExpireArgs{}, fields)
+}
+
+// HPExpireWithArgs sets the expiration of the hash fields in milliseconds if the condition is met.
+// redis-server version >= 7.4.0.
+func (c cmdable) HPExpireWithArgs(
+	ctx context.Context, key string, expiration time.Duration, expirationArgs HExpireArgs, fields ...string,
+) *HFieldStatusSliceCmd {
+	return c.hexpire(ctx, "hpexpire", key, formatMs(ctx, expiration), expirationArgs, fields)
+}
+
+// HExpireAt sets the expiration time of the hash fields.
+// redis-server version >= 7.4.0.
+func (c cmdable) HExpireAt(ctx context.Context, key string, tm time.Time, fields ...string) *HFieldStatusSliceCmd {
+	return c.hexpire(ctx, "hexpireat", key, tm.Unix(), HExpireArgs{}, fields)
+}
+
+// HExpireAtWithArgs sets the expiration time of the hash fields if the condition is met.
+// redis-server version >= 7.4.0.
+func (c cmdable) HExpireAtWithArgs(
+	ctx context.Context, key string, tm time.Time, expirationArgs HExpireArgs, fields ...string,
+) *HFieldStatusSliceCmd {
+	return c.hexpire(ctx, "hexpireat", key, tm.Unix(), expirationArgs, fields)
+}
+
+// HPExpireAt sets the expiration time of the hash fields with millisecond precision.
+// redis-server version >= 7.4.0.
+func (c cmdable) HPExpireAt(ctx context.Context, key string, tm time.Time, fields ...string) *HFieldStatusSliceCmd {
+	return c.hexpire(ctx, "hpexpireat", key, tm.UnixMilli(), HExpireArgs{}, fields)
+}
+
+// HPExpireAtWithArgs sets the expiration time of the hash fields with millisecond precision
+// if the condition is met.
+// redis-server version >= 7.4.0.
+func (c cmdable) HPExpireAtWithArgs(
+	ctx context.Context, key string, tm time.Time, expirationArgs HExpireArgs, fields ...string,
+) *HFieldStatusSliceCmd {
+	return c.hexpire(ctx, "hpexpireat", key, tm.UnixMilli(), expirationArgs, fields)
+}
+
+func (c cmdable) hexpire(
+	ctx context.Context, name, key string, expiration int64, expirationArgs HExpireArgs, fields []string,
+) *HFieldStatusSliceCmd {
+	args := make([]interface{}, 3, 6+len(fields))
+	args[0] = name
+	args[1] = key
+	args[2] = expiration
+	if mode := expirationArgs.mode(); mode != "" {
+		args = append(args, mode)
+	}
+	args = appendHashFields(args, fields)
+	cmd := NewHFieldStatusSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HPersist removes the expiration of the hash fields. It returns the status of each field.
+// redis-server version >= 7.4.0.
+func (c cmdable) HPersist(ctx context.Context, key string, fields ...string) *HFieldStatusSliceCmd {
+	args := make([]interface{}, 2, 4+len(fields))
+	args[0] = "hpersist"
+	args[1] = key
+	args = appendHashFields(args, fields)
+	cmd := NewHFieldStatusSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HExpireTime returns the Unix time in seconds at which the hash fields expire,
+// -1 for the fields without the expiration and -2 for the missing fields.
+// redis-server version >= 7.4.0.
+func (c cmdable) HExpireTime(ctx context.Context, key string, fields ...string) *IntSliceCmd {
+	return c.hashFieldsIntSlice(ctx, "hexpiretime", key, fields)
+}
+
+// HPExpireTime is like HExpireTime, but returns the Unix time in milliseconds.
+// redis-server version >= 7.4.0.
+func (c cmdable) HPExpireTime(ctx context.Context, key string, fields ...string) *IntSliceCmd {
+	return c.hashFieldsIntSlice(ctx, "hpexpiretime", key, fields)
+}
+
+// HTTL returns the remaining time to live in seconds of the hash fields,
+// -1 for the fields without the expiration and -2 for the missing fields.
+// redis-server version >= 7.4.0.
+func (c cmdable) HTTL(ctx context.Context, key string, fields ...string) *IntSliceCmd {
+	return c.hashFieldsIntSlice(ctx, "httl", key, fields)
+}
+
+// HPTTL is like HTTL, but returns the time to live in milliseconds.
+// redis-server version >= 7.4.0.
+func (c cmdable) HPTTL(ctx context.Context, key string, fields ...string) *IntSliceCmd {
+	return c.hashFieldsIntSlice(ctx, "hpttl", key, fields)
+}
+
+func (c cmdable) hashFieldsIntSlice(ctx context.Context, name, key string, fields []string) *IntSliceCmd {
+	args := make([]interface{}, 2, 4+len(fields))
+	args[0] = name
+	args[1] = key
+	args = appendHashFields(args, fields)
+	cmd := NewIntSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// appendHashFields appends the FIELDS numfields field [field ...] arguments.
+func appendHashFields(args []interface{}, fields []string) []interface{} {
+	args = append(args, "fields", len(fields))
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	return args
+}
+
 //------------------------------------------------------------------------------
 
 func (c cmdable) BLPop(ctx context.Context, timeout time.Duration, keys ...string) *StringSliceCmd {
@@ -3371,6 +3642,87 @@ func (c cmdable) MemoryUsage(ctx context.Context, key string, samples ...int) *I
 	return cmd
 }
 
+func (c cmdable) MemoryStats(ctx context.Context) *MemoryStatsCmd {
+	cmd := NewMemoryStatsCmd(ctx, "memory", "stats")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) MemoryDoctor(ctx context.Context) *StringCmd {
+	cmd := NewStringCmd(ctx, "memory", "doctor")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) MemoryMallocStats(ctx context.Context) *StringCmd {
+	cmd := NewStringCmd(ctx, "memory", "malloc-stats")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// MemoryPurge asks the allocator to release memory. It is a no-op with allocators other than jemalloc.
+func (c cmdable) MemoryPurge(ctx context.Context) *StatusCmd {
+	cmd := NewStatusCmd(ctx, "memory", "purge")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LatencyLatest returns the latest latency spike of each event.
+// Spikes are recorded only if latency-monitor-threshold is set.
+func (c cmdable) LatencyLatest(ctx context.Context) *LatencyEventsCmd {
+	cmd := NewLatencyEventsCmd(ctx, "latency", "latest")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LatencyHistory returns up to 160 latest latency spikes of the event.
+func (c cmdable) LatencyHistory(ctx context.Context, event string) *LatencySamplesCmd {
+	cmd := NewLatencySamplesCmd(ctx, "latency", "history", event)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LatencyReset resets the latency spikes of the events or all the events if none are given.
+// It returns the number of the reset events.
+func (c cmdable) LatencyReset(ctx context.Context, events ...string) *IntCmd {
+	args := make([]interface{}, 2, 2+len(events))
+	args[0] = "latency"
+	args[1] = "reset"
+	for _, event := range events {
+		args = append(args, event)
+	}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LatencyGraph returns an ASCII-art graph of the latency spikes of the event.
+func (c cmdable) LatencyGraph(ctx context.Context, event string) *StringCmd {
+	cmd := NewStringCmd(ctx, "latency", "graph", event)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) LatencyDoctor(ctx context.Context) *StringCmd {
+	cmd := NewStringCmd(ctx, "latency", "doctor")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// LatencyHistogram returns the latency histograms of the commands or of all
+// the called commands if none are given.
+func (c cmdable) LatencyHistogram(ctx context.Context, commands ...string) *LatencyHistogramCmd {
+	args := make([]interface{}, 2, 2+len(commands))
+	args[0] = "latency"
+	args[1] = "histogram"
+	for _, command := range commands {
+		args = append(args, command)
+	}
+	cmd := NewLatencyHistogramCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
 //------------------------------------------------------------------------------
 
 func (c cmdable) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *Cmd {
@@ -3968,3 +4320,202 @@ func (c cmdable) ACLLogReset(ctx context.Context) *StatusCmd {
 	_ = c(ctx, cmd)
 	return cmd
 }
+
+// ACLSetUser creates the user or modifies the rules of the existing user.
+// The rules can be built with ACLRules.
+func (c cmdable) ACLSetUser(ctx context.Context, username string, rules ...string) *StatusCmd {
+	args := make([]interface{}, 3, 3+len(rules))
+	args[0] = "acl"
+	args[1] = "setuser"
+	args[2] = username
+	for _, rule := range rules {
+		args = append(args, rule)
+	}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ACLGetUser returns the rules of the user or redis.Nil if the user does not exist.
+func (c cmdable) ACLGetUser(ctx context.Context, username string) *ACLUserCmd {
+	cmd := NewACLUserCmd(ctx, "acl", "getuser", username)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ACLDelUser(ctx context.Context, usernames ...string) *IntCmd {
+	args := make([]interface{}, 2, 2+len(usernames))
+	args[0] = "acl"
+	args[1] = "deluser"
+	for _, username := range usernames {
+		args = append(args, username)
+	}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ACLList returns the users in the format of the ACL file.
+func (c cmdable) ACLList(ctx context.Context) *StringSliceCmd {
+	cmd := NewStringSliceCmd(ctx, "acl", "list")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ACLUsers(ctx context.Context) *StringSliceCmd {
+	cmd := NewStringSliceCmd(ctx, "acl", "users")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ACLWhoAmI(ctx context.Context) *StringCmd {
+	cmd := NewStringCmd(ctx, "acl", "whoami")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ACLGenPass returns a random password of the given number of bits.
+// Zero bits uses the server default of 256 bits.
+func (c cmdable) ACLGenPass(ctx context.Context, bits int) *StringCmd {
+	args := make([]interface{}, 0, 3)
+	args = append(args, "acl", "genpass")
+	if bits > 0 {
+		args = append(args, bits)
+	}
+	cmd := NewStringCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ACLCat returns the command categories.
+func (c cmdable) ACLCat(ctx context.Context) *StringSliceCmd {
+	cmd := NewStringSliceCmd(ctx, "acl", "cat")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ACLCatArgs returns the commands of the category.
+func (c cmdable) ACLCatArgs(ctx context.Context, category string) *StringSliceCmd {
+	cmd := NewStringSliceCmd(ctx, "acl", "cat", category)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ACLSave saves the users to the ACL file.
+func (c cmdable) ACLSave(ctx context.Context) *StatusCmd {
+	cmd := NewStatusCmd(ctx, "acl", "save")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ACLLoad reloads the users from the ACL file.
+func (c cmdable) ACLLoad(ctx context.Context) *StatusCmd {
+	cmd := NewStatusCmd(ctx, "acl", "load")
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// ACLRules builds the rules of ACL SETUSER, e.g.
+//
+//	rules := redis.NewACLRules().Reset().On().AddPasswords("secret").
+//		Keys("svc:*").AllowCategories("read", "write")
+//	err := rdb.ACLSetUser(ctx, "svc", rules.Rules()...).Err()
+type ACLRules struct {
+	rules []string
+}
+
+func NewACLRules() *ACLRules {
+	return &ACLRules{}
+}
+
+// Rules returns the rules in the order they were added.
+func (r *ACLRules) Rules() []string {
+	return r.rules
+}
+
+func (r *ACLRules) String() string {
+	return strings.Join(r.rules, " ")
+}
+
+// Rule adds the raw rule.
+func (r *ACLRules) Rule(rules ...string) *ACLRules {
+	r.rules = append(r.rules, rules...)
+	return r
+}
+
+func (r *ACLRules) prefixed(prefix string, values []string) *ACLRules {
+	for _, v := range values {
+		r.rules = append(r.rules, prefix+v)
+	}
+	return r
+}
+
+// Reset removes all the rules of the user.
+func (r *ACLRules) Reset() *ACLRules { return r.Rule("reset") }
+
+// On enables the user.
+func (r *ACLRules) On() *ACLRules { return r.Rule("on") }
+
+// Off disables the user.
+func (r *ACLRules) Off() *ACLRules { return r.Rule("off") }
+
+// NoPass allows any password.
+func (r *ACLRules) NoPass() *ACLRules { return r.Rule("nopass") }
+
+// ResetPass removes all the passwords.
+func (r *ACLRules) ResetPass() *ACLRules { return r.Rule("resetpass") }
+
+func (r *ACLRules) AddPasswords(passwords ...string) *ACLRules { return r.prefixed(">", passwords) }
+
+func (r *ACLRules) RemovePasswords(passwords ...string) *ACLRules { return r.prefixed("<", passwords) }
+
+// AddHashedPasswords adds the hex-encoded SHA-256 hashes of the passwords.
+func (r *ACLRules) AddHashedPasswords(hashes ...string) *ACLRules { return r.prefixed("#", hashes) }
+
+func (r *ACLRules) RemoveHashedPasswords(hashes ...string) *ACLRules { return r.prefixed("!", hashes) }
+
+// Keys allows reading and writing the keys matching the patterns.
+func (r *ACLRules) Keys(patterns ...string) *ACLRules { return r.prefixed("~", patterns) }
+
+// ReadKeys allows reading the keys matching the patterns.
+func (r *ACLRules) ReadKeys(patterns ...string) *ACLRules { return r.prefixed("%R~", patterns) }
+
+// WriteKeys allows writing the keys matching the patterns.
+func (r *ACLRules) WriteKeys(patterns ...string) *ACLRules { return r.prefixed("%W~", patterns) }
+
+func (r *ACLRules) AllKeys() *ACLRules { return r.Rule("allkeys") }
+
+func (r *ACLRules) ResetKeys() *ACLRules { return r.Rule("resetkeys") }
+
+// Channels allows the Pub/Sub channels matching the patterns.
+func (r *ACLRules) Channels(patterns ...string) *ACLRules { return r.prefixed("&", patterns) }
+
+func (r *ACLRules) AllChannels() *ACLRules { return r.Rule("allchannels") }
+
+func (r *ACLRules) ResetChannels() *ACLRules { return r.Rule("resetchannels") }
+
+// AllowCommands allows the commands, e.g. "get" or "config|get".
+func (r *ACLRules) AllowCommands(commands ...string) *ACLRules { return r.prefixed("+", commands) }
+
+func (r *ACLRules) DenyCommands(commands ...string) *ACLRules { return r.prefixed("-", commands) }
+
+// AllowCategories allows the commands of the categories, e.g. "read".
+func (r *ACLRules) AllowCategories(categories ...string) *ACLRules {
+	return r.prefixed("+@", categories)
+}
+
+func (r *ACLRules) DenyCategories(categories ...string) *ACLRules {
+	return r.prefixed("-@", categories)
+}
+
+func (r *ACLRules) AllCommands() *ACLRules { return r.Rule("allcommands") }
+
+func (r *ACLRules) NoCommands() *ACLRules { return r.Rule("nocommands") }
+
+// Selector adds a selector, i.e. an additional set of key, channel and command rules
+// that is checked if the root rules do not allow the command.
+func (r *ACLRules) Selector(selector *ACLRules) *ACLRules {
+	return r.Rule("(" + selector.String() + ")")
+}
+
+func (r *ACLRules) ClearSelectors() *ACLRules { return r.Rule("clearselectors") }
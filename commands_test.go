@@ -1,11 +1,13 @@
 package redis_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	. "github.com/bsm/ginkgo/v2"
@@ -419,6 +421,62 @@ var _ = Describe("Commands", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(n).NotTo(BeZero())
 		})
+
+		It("should MemoryStats", func() {
+			err := client.Set(ctx, "foo", "bar", 0).Err()
+			Expect(err).NotTo(HaveOccurred())
+
+			stats, err := client.MemoryStats(ctx).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.PeakAllocated).To(BeNumerically(">", 0))
+			Expect(stats.TotalAllocated).To(BeNumerically(">", 0))
+			Expect(stats.KeysCount).To(BeNumerically(">=", 1))
+			Expect(stats.Fragmentation).To(BeNumerically(">", 0))
+			Expect(stats.DBs).To(HaveKey(redisOptions().DB))
+			Expect(stats.DBs[redisOptions().DB].OverheadHashtableMain).To(BeNumerically(">", 0))
+		})
+
+		It("should MemoryDoctor, MemoryMallocStats and MemoryPurge", func() {
+			Expect(client.MemoryDoctor(ctx).Val()).NotTo(BeEmpty())
+			Expect(client.MemoryMallocStats(ctx).Err()).NotTo(HaveOccurred())
+			Expect(client.MemoryPurge(ctx).Err()).NotTo(HaveOccurred())
+		})
+
+		It("should Latency", func() {
+			Expect(client.ConfigSet(ctx, "latency-monitor-threshold", "1").Err()).NotTo(HaveOccurred())
+			defer client.ConfigSet(ctx, "latency-monitor-threshold", "0")
+			Expect(client.LatencyReset(ctx).Err()).NotTo(HaveOccurred())
+
+			Expect(client.Do(ctx, "debug", "sleep", "0.01").Err()).NotTo(HaveOccurred())
+
+			events, err := client.LatencyLatest(ctx).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Name).To(Equal("command"))
+			Expect(events[0].Latest).To(BeNumerically(">=", 10*time.Millisecond))
+			Expect(events[0].Max).To(Equal(events[0].Latest))
+			Expect(events[0].Time).To(BeTemporally("~", time.Now(), time.Minute))
+
+			samples, err := client.LatencyHistory(ctx, "command").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(samples).To(HaveLen(1))
+			Expect(samples[0].Latency).To(Equal(events[0].Latest))
+
+			Expect(client.LatencyGraph(ctx, "command").Val()).To(ContainSubstring("command"))
+			Expect(client.LatencyDoctor(ctx).Val()).NotTo(BeEmpty())
+			Expect(client.LatencyReset(ctx, "command").Val()).To(Equal(int64(1)))
+			Expect(client.LatencyLatest(ctx).Val()).To(BeEmpty())
+		})
+
+		It("should LatencyHistogram", func() {
+			Expect(client.Set(ctx, "foo", "bar", 0).Err()).NotTo(HaveOccurred())
+
+			histograms, err := client.LatencyHistogram(ctx, "set").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(histograms).To(HaveKey("set"))
+			Expect(histograms["set"].Calls).To(BeNumerically(">=", 1))
+			Expect(histograms["set"].Buckets).NotTo(BeEmpty())
+		})
 	})
 
 	Describe("keys", func() {
@@ -1246,6 +1304,36 @@ var _ = Describe("Commands", func() {
 			Expect(get.Val()).To(Equal("hello"))
 		})
 
+		It("should GetStream", func() {
+			var buf bytes.Buffer
+			n, err := client.GetStream(ctx, "_", &buf).Result()
+			Expect(err).To(Equal(redis.Nil))
+			Expect(n).To(Equal(int64(0)))
+
+			value := strings.Repeat("hello", 100000)
+			Expect(client.Set(ctx, "key", value, 0).Err()).NotTo(HaveOccurred())
+
+			n, err = client.GetStream(ctx, "key", &buf).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(int64(len(value))))
+			Expect(buf.String()).To(Equal(value))
+
+			Expect(client.Ping(ctx).Err()).NotTo(HaveOccurred())
+		})
+
+		It("should SetReader", func() {
+			value := strings.Repeat("hello", 100000)
+			set := client.SetReader(ctx, "key", strings.NewReader(value), int64(len(value)))
+			Expect(set.Err()).NotTo(HaveOccurred())
+			Expect(set.Val()).To(Equal("OK"))
+
+			Expect(client.Get(ctx, "key").Val()).To(Equal(value))
+
+			set = client.SetReader(ctx, "key", strings.NewReader("hi"), 5)
+			Expect(set.Err()).To(MatchError("redis: got 2 bytes from the reader, wanted 5"))
+			Expect(client.Get(ctx, "key").Val()).To(Equal(value))
+		})
+
 		It("should GetBit", func() {
 			setBit := client.SetBit(ctx, "key", 7, 1)
 			Expect(setBit.Err()).NotTo(HaveOccurred())
@@ -2034,6 +2122,67 @@ var _ = Describe("Commands", func() {
 			Expect(len(logEntries)).To(Equal(0))
 		})
 
+		It("should ACL SETUSER and GETUSER", func() {
+			rules := redis.NewACLRules().Reset().On().AddPasswords("secret").
+				Keys("svc:*").ReadKeys("shared:*").Channels("events").
+				AllowCategories("read").AllowCommands("set").
+				Selector(redis.NewACLRules().Keys("cache:*").AllowCommands("get"))
+			Expect(rules.String()).To(Equal(
+				"reset on >secret ~svc:* %R~shared:* &events +@read +set (~cache:* +get)"))
+
+			err := client.ACLSetUser(ctx, "svc", rules.Rules()...).Err()
+			Expect(err).NotTo(HaveOccurred())
+			defer client.ACLDelUser(ctx, "svc")
+
+			user, err := client.ACLGetUser(ctx, "svc").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Flags).To(ContainElement("on"))
+			Expect(user.Passwords).To(HaveLen(1))
+			Expect(user.Commands).To(ContainSubstring("+@read"))
+			Expect(user.Commands).To(ContainSubstring("+set"))
+			Expect(user.Keys).To(Equal("~svc:* %R~shared:*"))
+			Expect(user.Channels).To(Equal("&events"))
+			Expect(user.Selectors).To(HaveLen(1))
+			Expect(user.Selectors[0].Keys).To(Equal("~cache:*"))
+			Expect(user.Selectors[0].Commands).To(ContainSubstring("+get"))
+
+			err = client.ACLGetUser(ctx, "missing").Err()
+			Expect(err).To(Equal(redis.Nil))
+		})
+
+		It("should ACL DELUSER, LIST and USERS", func() {
+			Expect(client.ACLSetUser(ctx, "u1", "on", "nopass").Err()).NotTo(HaveOccurred())
+			Expect(client.ACLSetUser(ctx, "u2").Err()).NotTo(HaveOccurred())
+
+			users, err := client.ACLUsers(ctx).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(ContainElements("default", "u1", "u2"))
+
+			list, err := client.ACLList(ctx).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(ContainElement(HavePrefix("user u1 on nopass")))
+
+			Expect(client.ACLDelUser(ctx, "u1", "u2", "missing").Val()).To(Equal(int64(2)))
+			Expect(client.ACLUsers(ctx).Val()).NotTo(ContainElement("u1"))
+		})
+
+		It("should ACL WHOAMI, GENPASS and CAT", func() {
+			Expect(client.ACLWhoAmI(ctx).Val()).To(Equal("default"))
+
+			pass, err := client.ACLGenPass(ctx, 0).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pass).To(HaveLen(64))
+			Expect(client.ACLGenPass(ctx, 32).Val()).To(HaveLen(8))
+
+			Expect(client.ACLCat(ctx).Val()).To(ContainElement("read"))
+			Expect(client.ACLCatArgs(ctx, "read").Val()).To(ContainElement("get"))
+		})
+
+		It("should fail ACL SAVE and LOAD without an ACL file", func() {
+			Expect(client.ACLSave(ctx).Err()).To(HaveOccurred())
+			Expect(client.ACLLoad(ctx).Err()).To(HaveOccurred())
+		})
+
 	})
 
 	Describe("hashes", func() {
@@ -2329,6 +2478,136 @@ var _ = Describe("Commands", func() {
 				Equal([]redis.KeyValue{{Key: "key2", Value: "hello2"}}),
 			))
 		})
+
+		It("should HExpire and HPersist", func() {
+			err := client.HSet(ctx, "hash", "key1", "hello1", "key2", "hello2").Err()
+			Expect(err).NotTo(HaveOccurred())
+
+			res, err := client.HExpire(ctx, "hash", 10*time.Second, "key1", "missing").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]redis.HFieldStatus{redis.HFieldUpdated, redis.HFieldMissing}))
+
+			res, err = client.HExpireWithArgs(ctx, "hash", 20*time.Second,
+				redis.HExpireArgs{NX: true}, "key1", "key2").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]redis.HFieldStatus{redis.HFieldConditionNotMet, redis.HFieldUpdated}))
+
+			res, err = client.HPExpireWithArgs(ctx, "hash", 5*time.Second,
+				redis.HExpireArgs{GT: true}, "key1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]redis.HFieldStatus{redis.HFieldConditionNotMet}))
+
+			ttl, err := client.HTTL(ctx, "hash", "key1", "key2", "missing").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl[0]).To(BeNumerically("~", 10, 1))
+			Expect(ttl[1]).To(BeNumerically("~", 20, 1))
+			Expect(ttl[2]).To(Equal(int64(-2)))
+
+			pttl, err := client.HPTTL(ctx, "hash", "key1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pttl[0]).To(BeNumerically("~", 10000, 1000))
+
+			res, err = client.HPersist(ctx, "hash", "key1", "key1", "missing").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]redis.HFieldStatus{
+				redis.HFieldUpdated, redis.HFieldNoExpiration, redis.HFieldMissing,
+			}))
+			Expect(client.HTTL(ctx, "hash", "key1").Val()).To(Equal([]int64{-1}))
+		})
+
+		It("should HExpireAt and HExpireTime", func() {
+			err := client.HSet(ctx, "hash", "key1", "hello1", "key2", "hello2").Err()
+			Expect(err).NotTo(HaveOccurred())
+
+			tm := time.Now().Add(time.Hour).Truncate(time.Second)
+			res, err := client.HExpireAt(ctx, "hash", tm, "key1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]redis.HFieldStatus{redis.HFieldUpdated}))
+			Expect(client.HExpireTime(ctx, "hash", "key1", "key2").Val()).To(Equal([]int64{tm.Unix(), -1}))
+
+			res, err = client.HPExpireAt(ctx, "hash", tm.Add(time.Second), "key2").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]redis.HFieldStatus{redis.HFieldUpdated}))
+			Expect(client.HPExpireTime(ctx, "hash", "key2").Val()).To(Equal([]int64{tm.Add(time.Second).UnixMilli()}))
+
+			res, err = client.HExpireAt(ctx, "hash", time.Now().Add(-time.Hour), "key1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]redis.HFieldStatus{redis.HFieldDeleted}))
+			Expect(client.HExists(ctx, "hash", "key1").Val()).To(BeFalse())
+		})
+
+		It("should HSetWithTTL", func() {
+			type session struct {
+				User  string `redis:"user"`
+				Token string `redis:"token,ttl=30s"`
+				CSRF  string `redis:"csrf,omitempty,ttl=1m"`
+			}
+
+			cmd, expireCmds := client.HSetWithTTL(ctx, "session", &session{User: "john", Token: "t"})
+			Expect(cmd.Val()).To(Equal(int64(2)))
+			Expect(expireCmds).To(HaveLen(1))
+			Expect(expireCmds[0].Val()).To(Equal([]redis.HFieldStatus{redis.HFieldUpdated}))
+
+			ttl, err := client.HTTL(ctx, "session", "user", "token", "csrf").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl[0]).To(Equal(int64(-1)))
+			Expect(ttl[1]).To(BeNumerically("~", 30, 1))
+			Expect(ttl[2]).To(Equal(int64(-2)))
+
+			cmds, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.HSetWithTTL(ctx, "session2", &session{User: "john", Token: "t"})
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmds).To(HaveLen(2))
+			Expect(client.HTTL(ctx, "session2", "token").Val()[0]).To(BeNumerically("~", 30, 1))
+
+			n, err := client.HSet(ctx, "session3", &session{User: "john", Token: "t"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(int64(2)))
+			Expect(client.HTTL(ctx, "session3", "token").Val()).To(Equal([]int64{-1}))
+
+			type invalid struct {
+				Token string `redis:"token,ttl=soon"`
+			}
+			cmd, expireCmds = client.HSetWithTTL(ctx, "session", invalid{Token: "t"})
+			Expect(cmd.Err()).To(MatchError(`redis: invalid ttl "soon" of field "token"`))
+			Expect(expireCmds).To(BeNil())
+		})
+
+		It("should HSet and scan nested structs", func() {
+			type address struct {
+				City string `redis:"city"`
+			}
+			type user struct {
+				Name    string         `redis:"name"`
+				Home    address        `redis:"home"`
+				Work    *address       `redis:"work"`
+				Created time.Time      `redis:"created,format=unixmilli"`
+				Scores  map[string]int `redis:"scores"`
+			}
+
+			src := user{
+				Name:    "john",
+				Home:    address{City: "Paris"},
+				Created: time.UnixMilli(1700000000123),
+				Scores:  map[string]int{"math": 5},
+			}
+			Expect(client.HSet(ctx, "user", src).Err()).NotTo(HaveOccurred())
+
+			Expect(client.HGetAll(ctx, "user").Val()).To(Equal(map[string]string{
+				"name":        "john",
+				"home.city":   "Paris",
+				"created":     "1700000000123",
+				"scores.math": "5",
+			}))
+
+			var dst user
+			Expect(client.HGetAll(ctx, "user").Scan(&dst)).NotTo(HaveOccurred())
+			Expect(dst.Created.Equal(src.Created)).To(BeTrue())
+			dst.Created = src.Created
+			Expect(dst).To(Equal(src))
+		})
 	})
 
 	Describe("hyperloglog", func() {
@@ -0,0 +1,70 @@
+package redisrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LimitExceededError is returned by ClientLimiter when the rate limit is exceeded.
+type LimitExceededError struct {
+	Result *Result
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("redisrate: rate limit %s exceeded, retry after %s",
+		e.Result.Limit, e.Result.RetryAfter)
+}
+
+// ClientLimiter adapts an Allower to redis.Limiter, so the limiter throttles
+// the commands of a client:
+//
+//	limiter := redisrate.NewLimiter(rdb)
+//	throttled := redis.NewClient(&redis.Options{
+//		Limiter: redisrate.NewClientLimiter(limiter, "backend", redisrate.PerSecond(1000)),
+//	})
+//
+// Every connection taken from the pool, i.e. every command or pipeline, is one event.
+// The Allower must use another client than the throttled one.
+type ClientLimiter struct {
+	allower Allower
+	key     string
+	limit   Limit
+
+	// Timeout of the rate limit check. Default is 1 second.
+	Timeout time.Duration
+}
+
+var _ redis.Limiter = (*ClientLimiter)(nil)
+
+// NewClientLimiter returns a ClientLimiter that shares the limit of the key
+// using the allower, e.g. Limiter or SlidingWindowLimiter.
+func NewClientLimiter(allower Allower, key string, limit Limit) *ClientLimiter {
+	return &ClientLimiter{
+		allower: allower,
+		key:     key,
+		limit:   limit,
+		Timeout: time.Second,
+	}
+}
+
+// Allow returns *LimitExceededError if the rate limit is exceeded,
+// or the error of the rate limit check.
+func (l *ClientLimiter) Allow() error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.Timeout)
+	defer cancel()
+
+	res, err := l.allower.AllowN(ctx, l.key, l.limit, 1)
+	if err != nil {
+		return err
+	}
+	if res.Allowed == 0 {
+		return &LimitExceededError{Result: res}
+	}
+	return nil
+}
+
+// ReportResult does nothing: the events are counted when they are allowed.
+func (l *ClientLimiter) ReportResult(result error) {}
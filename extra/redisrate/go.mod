@@ -0,0 +1,16 @@
+module github.com/redis/go-redis/extra/redisrate/v9
+
+go 1.19
+
+replace github.com/redis/go-redis/v9 => ../..
+
+require (
+	github.com/bsm/ginkgo/v2 v2.9.5
+	github.com/bsm/gomega v1.26.0
+	github.com/redis/go-redis/v9 v9.1.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+)
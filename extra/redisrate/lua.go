@@ -0,0 +1,109 @@
+package redisrate
+
+import "github.com/redis/go-redis/v9"
+
+// Copyright (c) 2017 Pavel Pravosud
+// https://github.com/rwz/redis-gcra/blob/master/vendor/perform_gcra_ratelimit.lua
+var gcra = redis.NewScript(`
+-- this script has side-effects, so it requires replicate commands mode
+redis.replicate_commands()
+
+local rate_limit_key = KEYS[1]
+local burst = ARGV[1]
+local rate = ARGV[2]
+local period = ARGV[3]
+local cost = tonumber(ARGV[4])
+
+local emission_interval = period / rate
+local increment = emission_interval * cost
+local burst_offset = emission_interval * burst
+
+-- redis returns time as an array containing two integers: seconds of the epoch
+-- time (10 digits) and microseconds (6 digits). for convenience we need to
+-- convert them to a floating point number. the resulting number is 16 digits,
+-- bordering on the limits of a 64-bit double-precision floating point number.
+-- adjust the epoch to be relative to Jan 1, 2017 00:00:00 GMT to avoid floating
+-- point problems. this approach is good until "now" is 2,483,228,799 (Wed, 09
+-- Sep 2048 01:46:39 GMT), when the adjusted value is 16 digits.
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local tat = redis.call("GET", rate_limit_key)
+
+if not tat then
+  tat = now
+else
+  tat = tonumber(tat)
+end
+
+tat = math.max(tat, now)
+
+local new_tat = tat + increment
+local allow_at = new_tat - burst_offset
+
+local diff = now - allow_at
+local remaining = diff / emission_interval
+
+if remaining < 0 then
+  local reset_after = tat - now
+  local retry_after = diff * -1
+  return {
+    0, -- allowed
+    0, -- remaining
+    tostring(retry_after),
+    tostring(reset_after),
+  }
+end
+
+local reset_after = new_tat - now
+if reset_after > 0 then
+  redis.call("SET", rate_limit_key, new_tat, "EX", math.ceil(reset_after))
+end
+local retry_after = -1
+return {cost, math.floor(remaining), tostring(retry_after), tostring(reset_after)}
+`)
+
+// slidingWindowLog stores the events in a sorted set scored by the time in microseconds.
+var slidingWindowLog = redis.NewScript(`
+-- this script has side-effects, so it requires replicate commands mode
+redis.replicate_commands()
+
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local id = ARGV[4]
+
+local now = redis.call("TIME")
+now = tonumber(now[1]) * 1000000 + tonumber(now[2])
+
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+local count = redis.call("ZCARD", key)
+
+local function reset_after()
+  local first = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+  if not first[2] then
+    return 0
+  end
+  return (tonumber(first[2]) + window - now) / 1000000
+end
+
+if count + cost > limit then
+  local retry_after = -1
+  -- the event that must leave the window for the cost to fit
+  local idx = count + cost - limit - 1
+  local event = redis.call("ZRANGE", key, idx, idx, "WITHSCORES")
+  if event[2] then
+    retry_after = (tonumber(event[2]) + window - now) / 1000000
+  end
+  return {0, math.max(limit - count, 0), tostring(retry_after), tostring(reset_after())}
+end
+
+for i = 1, cost do
+  redis.call("ZADD", key, now, id .. ":" .. i)
+end
+redis.call("PEXPIRE", key, math.ceil(window / 1000))
+
+return {cost, limit - count - cost, tostring(-1), tostring(reset_after())}
+`)
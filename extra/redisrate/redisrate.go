@@ -0,0 +1,271 @@
+// Package redisrate implements distributed rate limiters on top of Redis.
+//
+// Limiter implements the generic cell rate algorithm (GCRA), which allows bursts
+// and spreads the requests evenly over the period. SlidingWindowLimiter keeps
+// the log of the requests in a sorted set and allows at most Limit.Rate requests
+// in any window of Limit.Period. Both run atomically in a Lua script,
+// so the limits are shared by all the processes using the same Redis.
+package redisrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "rate:"
+
+type rediser interface {
+	redis.Scripter
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// Limit is the rate limit of Rate requests per Period with the Burst of requests.
+type Limit struct {
+	Rate   int
+	Burst  int
+	Period time.Duration
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d req/%s (burst %d)", l.Rate, fmtDur(l.Period), l.Burst)
+}
+
+func (l Limit) IsZero() bool {
+	return l == Limit{}
+}
+
+// validate returns an error if the rate, the period or, if burst is set, the burst
+// of the limit is not positive, because the scripts would divide by zero.
+func (l Limit) validate(burst bool) error {
+	if l.Rate <= 0 || l.Period <= 0 || (burst && l.Burst <= 0) {
+		return fmt.Errorf("redisrate: invalid limit %s", l)
+	}
+	return nil
+}
+
+func fmtDur(d time.Duration) string {
+	switch d {
+	case time.Second:
+		return "s"
+	case time.Minute:
+		return "m"
+	case time.Hour:
+		return "h"
+	}
+	return d.String()
+}
+
+func PerSecond(rate int) Limit {
+	return Limit{
+		Rate:   rate,
+		Period: time.Second,
+		Burst:  rate,
+	}
+}
+
+func PerMinute(rate int) Limit {
+	return Limit{
+		Rate:   rate,
+		Period: time.Minute,
+		Burst:  rate,
+	}
+}
+
+func PerHour(rate int) Limit {
+	return Limit{
+		Rate:   rate,
+		Period: time.Hour,
+		Burst:  rate,
+	}
+}
+
+// Result is the result of a rate limit check.
+type Result struct {
+	// Limit is the limit that was used to obtain this result.
+	Limit Limit
+
+	// Allowed is the number of events that may happen at time now.
+	Allowed int
+
+	// Remaining is the maximum number of requests that could be
+	// permitted instantaneously for this key given the current
+	// state. For example, if a rate limiter allows 10 requests per
+	// second and has already received 6 requests for this key this
+	// second, Remaining would be 4.
+	Remaining int
+
+	// RetryAfter is the time until the next request will be permitted.
+	// It should be -1 unless the rate limit has been exceeded.
+	RetryAfter time.Duration
+
+	// ResetAfter is the time until the RateLimiter returns to its
+	// initial state for a given key. For example, if a rate limiter
+	// manages requests per second and received one request 200ms ago,
+	// Reset would return 800ms. You can also think of this as the time
+	// until Limit and Remaining will be equal.
+	ResetAfter time.Duration
+}
+
+// Allower is implemented by Limiter and SlidingWindowLimiter.
+type Allower interface {
+	// AllowN reports whether n events may happen at time now.
+	AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error)
+}
+
+var (
+	_ Allower = (*Limiter)(nil)
+	_ Allower = (*SlidingWindowLimiter)(nil)
+)
+
+//------------------------------------------------------------------------------
+
+// Limiter controls how frequently events are allowed to happen
+// using the generic cell rate algorithm.
+type Limiter struct {
+	rdb rediser
+}
+
+// NewLimiter returns a new Limiter.
+func NewLimiter(rdb rediser) *Limiter {
+	return &Limiter{
+		rdb: rdb,
+	}
+}
+
+// Allow is a shortcut for AllowN(ctx, key, limit, 1).
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN reports whether n events may happen at time now.
+// The rate, the period and the burst of the limit must be positive.
+func (l *Limiter) AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	if err := limit.validate(true); err != nil {
+		return nil, err
+	}
+	args := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds(), n}
+	v, err := gcra.Run(ctx, l.rdb, []string{keyPrefix + key}, args...).Slice()
+	if err != nil {
+		return nil, err
+	}
+	return parseResult(limit, v)
+}
+
+// Reset gets a key and reset all limitations and previous usages.
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return l.rdb.Del(ctx, keyPrefix+key).Err()
+}
+
+//------------------------------------------------------------------------------
+
+// SlidingWindowLimiter allows at most Limit.Rate events in any window of Limit.Period.
+// Limit.Burst is not used. Unlike Limiter, it stores every event in the window,
+// so the memory grows with the rate.
+type SlidingWindowLimiter struct {
+	rdb rediser
+}
+
+// NewSlidingWindowLimiter returns a new SlidingWindowLimiter.
+func NewSlidingWindowLimiter(rdb rediser) *SlidingWindowLimiter {
+	return &SlidingWindowLimiter{
+		rdb: rdb,
+	}
+}
+
+// Allow is a shortcut for AllowN(ctx, key, limit, 1).
+func (l *SlidingWindowLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN reports whether n events may happen at time now.
+// The rate and the period of the limit must be positive.
+func (l *SlidingWindowLimiter) AllowN(
+	ctx context.Context, key string, limit Limit, n int,
+) (*Result, error) {
+	if err := limit.validate(false); err != nil {
+		return nil, err
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []interface{}{limit.Rate, limit.Period.Microseconds(), n, id}
+	v, err := slidingWindowLog.Run(ctx, l.rdb, []string{keyPrefix + "log:" + key}, args...).Slice()
+	if err != nil {
+		return nil, err
+	}
+	return parseResult(limit, v)
+}
+
+// Reset gets a key and reset all limitations and previous usages.
+func (l *SlidingWindowLimiter) Reset(ctx context.Context, key string) error {
+	return l.rdb.Del(ctx, keyPrefix+"log:"+key).Err()
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+//------------------------------------------------------------------------------
+
+// parseResult parses the {allowed, remaining, retry_after, reset_after} reply of the scripts.
+// The durations are in seconds and are returned as strings, because Lua numbers
+// are converted to integers.
+func parseResult(limit Limit, v []interface{}) (*Result, error) {
+	if len(v) != 4 {
+		return nil, fmt.Errorf("redisrate: unexpected reply %v", v)
+	}
+
+	retryAfter, err := parseSeconds(v[2])
+	if err != nil {
+		return nil, err
+	}
+	resetAfter, err := parseSeconds(v[3])
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, ok := v[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("redisrate: unexpected allowed %v", v[0])
+	}
+	remaining, ok := v[1].(int64)
+	if !ok {
+		return nil, fmt.Errorf("redisrate: unexpected remaining %v", v[1])
+	}
+
+	return &Result{
+		Limit:      limit,
+		Allowed:    int(allowed),
+		Remaining:  int(remaining),
+		RetryAfter: retryAfter,
+		ResetAfter: resetAfter,
+	}, nil
+}
+
+func parseSeconds(v interface{}) (time.Duration, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("redisrate: unexpected duration %v", v)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f == -1 {
+		return -1, nil
+	}
+	return time.Duration(f * float64(time.Second)), nil
+}
@@ -0,0 +1,140 @@
+package redisrate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/extra/redisrate/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestGinkgo(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "redisrate")
+}
+
+var _ = Describe("Limit", func() {
+	It("should format the limit", func() {
+		Expect(redisrate.PerSecond(10).String()).To(Equal("10 req/s (burst 10)"))
+		Expect(redisrate.PerMinute(10).String()).To(Equal("10 req/m (burst 10)"))
+		Expect(redisrate.PerHour(10).String()).To(Equal("10 req/h (burst 10)"))
+		Expect(redisrate.Limit{Rate: 1, Burst: 2, Period: 5 * time.Second}.String()).
+			To(Equal("1 req/5s (burst 2)"))
+		Expect(redisrate.Limit{}.IsZero()).To(BeTrue())
+	})
+
+	It("should reject invalid limits", func() {
+		ctx := context.Background()
+		rdb := redis.NewClient(&redis.Options{Addr: ":6379"})
+		defer rdb.Close()
+
+		for _, limit := range []redisrate.Limit{
+			{Rate: 0, Burst: 1, Period: time.Second},
+			{Rate: 1, Burst: 1, Period: 0},
+			{Rate: 1, Burst: 1, Period: -time.Second},
+			{Rate: 1, Burst: 0, Period: time.Second},
+		} {
+			_, err := redisrate.NewLimiter(rdb).Allow(ctx, "test_id", limit)
+			Expect(err).To(MatchError("redisrate: invalid limit " + limit.String()))
+		}
+
+		_, err := redisrate.NewSlidingWindowLimiter(rdb).Allow(ctx, "test_id", redisrate.Limit{Rate: 1})
+		Expect(err).To(MatchError("redisrate: invalid limit 1 req/0s (burst 0)"))
+	})
+})
+
+var _ = Describe("Limiter", func() {
+	ctx := context.Background()
+	var rdb *redis.Client
+
+	BeforeEach(func() {
+		rdb = redis.NewClient(&redis.Options{Addr: ":6379"})
+		Expect(rdb.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(rdb.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should allow the burst", func() {
+		l := redisrate.NewLimiter(rdb)
+		limit := redisrate.PerSecond(10)
+
+		res, err := l.Allow(ctx, "test_id", limit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Allowed).To(Equal(1))
+		Expect(res.Remaining).To(Equal(9))
+		Expect(res.RetryAfter).To(Equal(time.Duration(-1)))
+		Expect(res.ResetAfter).To(BeNumerically("~", 100*time.Millisecond, 10*time.Millisecond))
+
+		res, err = l.AllowN(ctx, "test_id", limit, 9)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Allowed).To(Equal(9))
+		Expect(res.Remaining).To(Equal(0))
+
+		res, err = l.Allow(ctx, "test_id", limit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Allowed).To(Equal(0))
+		Expect(res.Remaining).To(Equal(0))
+		Expect(res.RetryAfter).To(BeNumerically("~", 100*time.Millisecond, 10*time.Millisecond))
+		Expect(res.ResetAfter).To(BeNumerically("~", time.Second, 10*time.Millisecond))
+
+		Expect(l.Reset(ctx, "test_id")).NotTo(HaveOccurred())
+		res, err = l.Allow(ctx, "test_id", limit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Allowed).To(Equal(1))
+	})
+
+	It("should limit the sliding window", func() {
+		l := redisrate.NewSlidingWindowLimiter(rdb)
+		limit := redisrate.Limit{Rate: 3, Period: 200 * time.Millisecond}
+
+		res, err := l.AllowN(ctx, "test_id", limit, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Allowed).To(Equal(2))
+		Expect(res.Remaining).To(Equal(1))
+		Expect(res.RetryAfter).To(Equal(time.Duration(-1)))
+		Expect(res.ResetAfter).To(BeNumerically("~", 200*time.Millisecond, 10*time.Millisecond))
+
+		res, err = l.AllowN(ctx, "test_id", limit, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Allowed).To(Equal(0))
+		Expect(res.Remaining).To(Equal(1))
+		Expect(res.RetryAfter).To(BeNumerically("~", 200*time.Millisecond, 20*time.Millisecond))
+
+		res, err = l.AllowN(ctx, "test_id", limit, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Allowed).To(Equal(0))
+		Expect(res.RetryAfter).To(Equal(time.Duration(-1)))
+
+		time.Sleep(res.ResetAfter + 10*time.Millisecond)
+		res, err = l.AllowN(ctx, "test_id", limit, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Allowed).To(Equal(3))
+		Expect(res.Remaining).To(Equal(0))
+
+		Expect(l.Reset(ctx, "test_id")).NotTo(HaveOccurred())
+		Expect(rdb.Exists(ctx, "rate:log:test_id").Val()).To(Equal(int64(0)))
+	})
+
+	It("should throttle the client", func() {
+		throttled := redis.NewClient(&redis.Options{
+			Addr:    ":6379",
+			Limiter: redisrate.NewClientLimiter(redisrate.NewLimiter(rdb), "client", redisrate.PerMinute(2)),
+		})
+		defer throttled.Close()
+
+		Expect(throttled.Ping(ctx).Err()).NotTo(HaveOccurred())
+		Expect(throttled.Ping(ctx).Err()).NotTo(HaveOccurred())
+
+		err := throttled.Ping(ctx).Err()
+		var limitErr *redisrate.LimitExceededError
+		Expect(errors.As(err, &limitErr)).To(BeTrue())
+		Expect(limitErr.Result.RetryAfter).To(BeNumerically("~", 30*time.Second, time.Second))
+	})
+})
@@ -0,0 +1,261 @@
+package hscan
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Codec encodes and decodes the struct fields of a type.
+type Codec interface {
+	// Encode returns the Redis value of v.
+	Encode(v interface{}) (string, error)
+	// Decode returns the value of the type decoded from the Redis value s.
+	Decode(s string) (interface{}, error)
+}
+
+var (
+	codecs sync.Map // map[reflect.Type]Codec
+
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// RegisterCodec registers the codec of the type t that overrides the built-in encoding
+// of the struct fields of type t. Passing a nil codec unregisters the codec.
+func RegisterCodec(t reflect.Type, codec Codec) {
+	if codec == nil {
+		codecs.Delete(t)
+	} else {
+		codecs.Store(t, codec)
+	}
+	// Whether a struct type is nested or encoded as a single value depends on the codecs.
+	globalStructMap.reset()
+}
+
+func lookupCodec(t reflect.Type) (Codec, bool) {
+	if c, ok := codecs.Load(t); ok {
+		return c.(Codec), true
+	}
+	return nil, false
+}
+
+func decodeWithCodec(v reflect.Value, s string, codec Codec) error {
+	val, err := codec.Decode(s)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(val)
+	if !rv.IsValid() {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if !rv.Type().AssignableTo(v.Type()) {
+		return fmt.Errorf("redis: codec decoded %s, wanted %s", rv.Type(), v.Type())
+	}
+	v.Set(rv)
+	return nil
+}
+
+func durationUnit(format string) (time.Duration, error) {
+	switch format {
+	case "", "ns":
+		return time.Nanosecond, nil
+	case "us":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	default:
+		return 0, fmt.Errorf("redis: unknown time.Duration format %q", format)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// Field is a struct field encoded by EncodeStruct.
+type Field struct {
+	Name  string
+	Value interface{}
+	// TTL is the value of the ttl tag option, e.g. "30s".
+	TTL string
+}
+
+// EncodeStruct encodes the fields of the struct v that have the `redis` tag.
+// The fields of nested structs are named "<parent>.<field>", and the entries of maps
+// "<field>.<key>". The tag options are:
+//
+//   - omitempty skips the field with the zero value;
+//   - format sets the format of time.Time fields ("rfc3339nano" by default, "rfc3339",
+//     "unix", "unixmilli", "unixmicro", "unixnano" or a time.Parse layout) and
+//     time.Duration fields ("ns" by default, "us", "ms", "s" or "string");
+//   - ttl sets the TTL of the hash field, e.g. "ttl=30s".
+//
+// Nil pointers are skipped.
+func EncodeStruct(v reflect.Value) ([]Field, error) {
+	spec := globalStructMap.get(v.Type())
+	fields := make([]Field, 0, len(spec.fields))
+	for _, sf := range spec.fields {
+		fv, ok := sf.value(v, false)
+		if !ok {
+			continue
+		}
+		if sf.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		if sf.isMap {
+			var err error
+			if fields, err = appendMapFields(fields, sf, fv); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		val, ok, err := encodeValue(fv, sf.format)
+		if err != nil {
+			return nil, fmt.Errorf("redis: can't encode field %s: %w", sf.name, err)
+		}
+		if ok {
+			fields = append(fields, Field{Name: sf.name, Value: val, TTL: sf.ttl})
+		}
+	}
+	return fields, nil
+}
+
+func appendMapFields(fields []Field, sf *structField, v reflect.Value) ([]Field, error) {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	for _, key := range keys {
+		name := sf.name + "." + key.String()
+		val, ok, err := encodeValue(v.MapIndex(key), sf.format)
+		if err != nil {
+			return nil, fmt.Errorf("redis: can't encode field %s: %w", name, err)
+		}
+		if ok {
+			fields = append(fields, Field{Name: name, Value: val, TTL: sf.ttl})
+		}
+	}
+	return fields, nil
+}
+
+// encodeValue returns the value written for v. It returns false for nil pointers.
+func encodeValue(v reflect.Value, format string) (interface{}, bool, error) {
+	if codec, ok := lookupCodec(v.Type()); ok {
+		s, err := codec.Encode(v.Interface())
+		return s, err == nil, err
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, false, nil
+		}
+		return encodeValue(v.Elem(), format)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, true, nil
+		}
+		return v.Elem().Interface(), true, nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		s, err := encodeTime(v.Interface().(time.Time), format)
+		return s, err == nil, err
+	case durationType:
+		s, err := encodeDuration(time.Duration(v.Int()), format)
+		return s, err == nil, err
+	}
+
+	if v.Type().Name() != "" {
+		// The methods may have pointer receivers.
+		if !v.CanAddr() {
+			addr := reflect.New(v.Type()).Elem()
+			addr.Set(v)
+			v = addr
+		}
+		switch m := v.Addr().Interface().(type) {
+		case encoding.BinaryMarshaler:
+			b, err := m.MarshalBinary()
+			return b, err == nil, err
+		case encoding.TextMarshaler:
+			b, err := m.MarshalText()
+			return b, err == nil, err
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), true, nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true, nil
+	case reflect.String:
+		return v.String(), true, nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), true, nil
+		}
+	}
+	// The writer reports the unsupported type.
+	return v.Interface(), true, nil
+}
+
+func encodeTime(tm time.Time, format string) (string, error) {
+	switch format {
+	case "", "rfc3339nano":
+		return tm.Format(time.RFC3339Nano), nil
+	case "rfc3339":
+		return tm.Format(time.RFC3339), nil
+	case "unix":
+		return strconv.FormatInt(tm.Unix(), 10), nil
+	case "unixmilli":
+		return strconv.FormatInt(tm.UnixMilli(), 10), nil
+	case "unixmicro":
+		return strconv.FormatInt(tm.UnixMicro(), 10), nil
+	case "unixnano":
+		return strconv.FormatInt(tm.UnixNano(), 10), nil
+	default:
+		return tm.Format(format), nil
+	}
+}
+
+func encodeDuration(d time.Duration, format string) (string, error) {
+	if format == "string" {
+		return d.String(), nil
+	}
+	unit, err := durationUnit(format)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(int64(d/unit), 10), nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -1,10 +1,14 @@
 package hscan
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal/util"
 )
 
 // decoderFunc represents decoding functions for default built-in types.
@@ -37,7 +41,7 @@ var (
 		reflect.Array:         decodeUnsupported,
 		reflect.Chan:          decodeUnsupported,
 		reflect.Func:          decodeUnsupported,
-		reflect.Interface:     decodeUnsupported,
+		reflect.Interface:     decodeInterface,
 		reflect.Map:           decodeUnsupported,
 		reflect.Ptr:           decodeUnsupported,
 		reflect.Slice:         decodeSlice,
@@ -71,6 +75,46 @@ func Struct(dst interface{}) (StructValue, error) {
 	}, nil
 }
 
+// decodeValue decodes s into v using, in order, the registered codec of the type,
+// Scanner, encoding.BinaryUnmarshaler, encoding.TextUnmarshaler and the built-in decoders.
+func decodeValue(v reflect.Value, s, format string) error {
+	if codec, ok := lookupCodec(v.Type()); ok {
+		return decodeWithCodec(v, s, codec)
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(v.Elem(), s, format)
+	}
+
+	switch v.Type() {
+	case timeType:
+		return decodeTime(v, s, format)
+	case durationType:
+		return decodeDuration(v, s, format)
+	}
+
+	if v.CanAddr() && v.Type().Name() != "" {
+		switch scan := v.Addr().Interface().(type) {
+		case Scanner:
+			return scan.ScanRedis(s)
+		case encoding.BinaryUnmarshaler:
+			err := scan.UnmarshalBinary(util.StringToBytes(s))
+			// The value may have been written as text by other clients.
+			if text, ok := scan.(encoding.TextUnmarshaler); ok && err != nil {
+				return text.UnmarshalText(util.StringToBytes(s))
+			}
+			return err
+		case encoding.TextUnmarshaler:
+			return scan.UnmarshalText(util.StringToBytes(s))
+		}
+	}
+
+	return decoders[v.Kind()](v, s)
+}
+
 // Scan scans the results from a key-value Redis map result set to a destination struct.
 // The Redis keys are matched to the struct's field with the `redis` tag.
 func Scan(dst interface{}, keys []interface{}, vals []interface{}) error {
@@ -202,6 +246,66 @@ func decodeSlice(f reflect.Value, s string) error {
 	return nil
 }
 
+func decodeInterface(f reflect.Value, s string) error {
+	if f.NumMethod() > 0 {
+		return decodeUnsupported(f, s)
+	}
+	f.Set(reflect.ValueOf(s))
+	return nil
+}
+
+func decodeTime(f reflect.Value, s, format string) error {
+	var tm time.Time
+	switch format {
+	case "", "rfc3339nano", "rfc3339":
+		return f.Addr().Interface().(*time.Time).UnmarshalText(util.StringToBytes(s))
+	case "unix", "unixmilli", "unixmicro", "unixnano":
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "unix":
+			tm = time.Unix(n, 0)
+		case "unixmilli":
+			tm = time.UnixMilli(n)
+		case "unixmicro":
+			tm = time.UnixMicro(n)
+		default:
+			tm = time.Unix(0, n)
+		}
+	default:
+		var err error
+		if tm, err = time.Parse(format, s); err != nil {
+			return err
+		}
+	}
+	f.Set(reflect.ValueOf(tm))
+	return nil
+}
+
+func decodeDuration(f reflect.Value, s, format string) error {
+	if format == "string" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+
+	unit, err := durationUnit(format)
+	if err != nil {
+		return err
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	f.SetInt(n * int64(unit))
+	return nil
+}
+
 func decodeUnsupported(v reflect.Value, s string) error {
 	return fmt.Errorf("redis.Scan(unsupported %s)", v.Type())
 }
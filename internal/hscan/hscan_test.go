@@ -1,13 +1,21 @@
 package hscan
 
 import (
+	"bytes"
+	"database/sql"
+	"fmt"
 	"math"
+	"net"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	. "github.com/bsm/ginkgo/v2"
 	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9/internal/proto"
 )
 
 type data struct {
@@ -213,3 +221,234 @@ var _ = Describe("Scan", func() {
 		Expect(now.Unix()).To(Equal(tt.Time.Unix()))
 	})
 })
+
+type address struct {
+	City string `redis:"city"`
+	Zip  int    `redis:"zip"`
+}
+
+type Audit struct {
+	CreatedBy string `redis:"created_by"`
+}
+
+type point struct {
+	X, Y int16
+}
+
+func (p point) MarshalBinary() ([]byte, error) {
+	return []byte{byte(p.X), byte(p.Y)}, nil
+}
+
+func (p *point) UnmarshalBinary(b []byte) error {
+	if len(b) != 2 {
+		return fmt.Errorf("invalid point of %d bytes", len(b))
+	}
+	p.X, p.Y = int16(b[0]), int16(b[1])
+	return nil
+}
+
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+func (l *level) UnmarshalText(b []byte) error {
+	*l = level(len(b))
+	return nil
+}
+
+type cents struct {
+	n int64
+}
+
+type centsCodec struct{}
+
+func (centsCodec) Encode(v interface{}) (string, error) {
+	c := v.(cents)
+	return fmt.Sprintf("%d.%02d", c.n/100, c.n%100), nil
+}
+
+func (centsCodec) Decode(s string) (interface{}, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	return cents{n: int64(math.Round(f * 100))}, err
+}
+
+type user struct {
+	Audit
+	*Extra
+
+	Name     string            `redis:"name"`
+	Home     address           `redis:"home"`
+	Work     *address          `redis:"work"`
+	Created  time.Time         `redis:"created,format=unix"`
+	Birthday time.Time         `redis:"birthday,format=2006-01-02"`
+	Login    time.Time         `redis:"login"`
+	Timeout  time.Duration     `redis:"timeout,format=ms"`
+	Session  time.Duration     `redis:"session,format=string"`
+	Pos      point             `redis:"pos"`
+	Level    level             `redis:"level"`
+	Balance  cents             `redis:"balance"`
+	Attrs    map[string]string `redis:"attrs"`
+	Scores   map[string]int    `redis:"scores,omitempty"`
+	Nick     string            `redis:"nick,omitempty"`
+	Age      *int              `redis:"age"`
+	Any      interface{}       `redis:"any"`
+	Token    string            `redis:"token,ttl=30s"`
+}
+
+type Extra struct {
+	Note string `redis:"note"`
+}
+
+// roundTrip encodes the struct, writes and reads the values like a client,
+// and scans them into dst.
+func roundTrip(src, dst interface{}) map[string]string {
+	fields, err := EncodeStruct(reflect.ValueOf(src).Elem())
+	Expect(err).NotTo(HaveOccurred())
+
+	var args []interface{}
+	for _, f := range fields {
+		args = append(args, f.Value)
+	}
+	var buf bytes.Buffer
+	Expect(proto.NewWriter(&buf).WriteArgs(args)).NotTo(HaveOccurred())
+	reply, err := proto.NewReader(&buf).ReadReply()
+	Expect(err).NotTo(HaveOccurred())
+
+	keys := make(i, len(fields))
+	m := make(map[string]string, len(fields))
+	for n, f := range fields {
+		keys[n] = f.Name
+		m[f.Name] = reply.([]interface{})[n].(string)
+	}
+	Expect(Scan(dst, keys, reply.([]interface{}))).NotTo(HaveOccurred())
+	return m
+}
+
+var _ = Describe("EncodeStruct", func() {
+	BeforeEach(func() {
+		RegisterCodec(reflect.TypeOf(cents{}), centsCodec{})
+	})
+
+	AfterEach(func() {
+		RegisterCodec(reflect.TypeOf(cents{}), nil)
+	})
+
+	It("round-trips the fields", func() {
+		age := 42
+		src := &user{
+			Audit:    Audit{CreatedBy: "admin"},
+			Extra:    &Extra{Note: "vip"},
+			Name:     "john",
+			Home:     address{City: "Paris", Zip: 75001},
+			Created:  time.Unix(1700000000, 0),
+			Birthday: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+			Login:    time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC),
+			Timeout:  1500 * time.Millisecond,
+			Session:  90 * time.Minute,
+			Pos:      point{X: 3, Y: 4},
+			Level:    3,
+			Balance:  cents{n: 1234},
+			Attrs:    map[string]string{"color": "red", "size": "xl"},
+			Age:      &age,
+			Any:      "anything",
+			Token:    "t",
+		}
+
+		dst := new(user)
+		m := roundTrip(src, dst)
+		Expect(m).To(Equal(map[string]string{
+			"created_by":  "admin",
+			"note":        "vip",
+			"name":        "john",
+			"home.city":   "Paris",
+			"home.zip":    "75001",
+			"created":     "1700000000",
+			"birthday":    "1990-05-17",
+			"login":       "2023-01-02T03:04:05.000000006Z",
+			"timeout":     "1500",
+			"session":     "1h30m0s",
+			"pos":         "\x03\x04",
+			"level":       "***",
+			"balance":     "12.34",
+			"attrs.color": "red",
+			"attrs.size":  "xl",
+			"age":         "42",
+			"any":         "anything",
+			"token":       "t",
+		}))
+
+		Expect(dst.Created.Equal(src.Created)).To(BeTrue())
+		Expect(dst.Login.Equal(src.Login)).To(BeTrue())
+		dst.Created, dst.Login = src.Created, src.Login
+		Expect(dst).To(Equal(src))
+	})
+
+	It("skips nil pointers and empty fields", func() {
+		fields, err := EncodeStruct(reflect.ValueOf(user{Nick: ""}))
+		Expect(err).NotTo(HaveOccurred())
+
+		names := make([]string, 0, len(fields))
+		for _, f := range fields {
+			names = append(names, f.Name)
+		}
+		Expect(names).NotTo(ContainElements("note", "work.city", "age", "nick", "scores"))
+		Expect(names).To(ContainElements("created_by", "home.city", "any"))
+	})
+
+	It("returns the ttl option", func() {
+		fields, err := EncodeStruct(reflect.ValueOf(user{}))
+		Expect(err).NotTo(HaveOccurred())
+		for _, f := range fields {
+			if f.Name == "token" {
+				Expect(f.TTL).To(Equal("30s"))
+			} else {
+				Expect(f.TTL).To(BeEmpty())
+			}
+		}
+	})
+
+	It("allocates nested pointers when scanning", func() {
+		var u user
+		Expect(Scan(&u, i{"work.city", "note"}, i{"Berlin", "n"})).NotTo(HaveOccurred())
+		Expect(u.Work).To(Equal(&address{City: "Berlin"}))
+		Expect(u.Extra).To(Equal(&Extra{Note: "n"}))
+	})
+
+	It("falls back to text for binary unmarshalers", func() {
+		type ip struct {
+			Addr net.IP `redis:"addr"`
+		}
+		d := new(ip)
+		m := roundTrip(&ip{Addr: net.ParseIP("10.0.0.1")}, d)
+		Expect(m["addr"]).To(Equal("10.0.0.1"))
+		Expect(d.Addr.Equal(net.ParseIP("10.0.0.1"))).To(BeTrue())
+	})
+
+	It("reports the structs without tagged fields", func() {
+		type nullable struct {
+			Name sql.NullString `redis:"name"`
+		}
+		fields, err := EncodeStruct(reflect.ValueOf(nullable{}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fields).To(HaveLen(1))
+		Expect(fields[0].Name).To(Equal("name"))
+
+		var buf bytes.Buffer
+		err = proto.NewWriter(&buf).WriteArg(fields[0].Value)
+		Expect(err).To(MatchError(ContainSubstring("can't marshal sql.NullString")))
+
+		err = Scan(&nullable{}, i{"name"}, i{"john"})
+		Expect(err).To(MatchError(ContainSubstring("redis.Scan(unsupported sql.NullString)")))
+	})
+
+	It("reports invalid formats", func() {
+		type bad struct {
+			D time.Duration `redis:"d,format=days"`
+		}
+		_, err := EncodeStruct(reflect.ValueOf(bad{}))
+		Expect(err).To(MatchError(`redis: can't encode field d: redis: unknown time.Duration format "days"`))
+		Expect(Scan(&bad{}, i{"d"}, i{"1"})).To(HaveOccurred())
+	})
+})
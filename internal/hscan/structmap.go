@@ -6,8 +6,6 @@ import (
 	"reflect"
 	"strings"
 	"sync"
-
-	"github.com/redis/go-redis/v9/internal/util"
 )
 
 // structMap contains the map of struct fields for target structs
@@ -30,49 +28,223 @@ func (s *structMap) get(t reflect.Type) *structSpec {
 	return spec
 }
 
+// reset drops the cached specs, which depend on the registered codecs.
+func (s *structMap) reset() {
+	s.m.Range(func(key, _ interface{}) bool {
+		s.m.Delete(key)
+		return true
+	})
+}
+
 //------------------------------------------------------------------------------
 
 // structSpec contains the list of all fields in a target struct.
+// The fields of nested structs are named "<parent>.<field>".
 type structSpec struct {
-	m map[string]*structField
+	fields []*structField
+	m      map[string]*structField
+	// maps are the map fields, whose keys are named "<field>.<key>".
+	maps []*structField
 }
 
 func (s *structSpec) set(tag string, sf *structField) {
+	s.fields = append(s.fields, sf)
+	if sf.isMap {
+		s.maps = append(s.maps, sf)
+		return
+	}
 	s.m[tag] = sf
 }
 
 func newStructSpec(t reflect.Type, fieldTag string) *structSpec {
-	numField := t.NumField()
 	out := &structSpec{
-		m: make(map[string]*structField, numField),
+		m: make(map[string]*structField, t.NumField()),
 	}
+	out.addFields(t, fieldTag, "", "", nil, map[reflect.Type]bool{t: true})
+	return out
+}
 
-	for i := 0; i < numField; i++ {
+func (s *structSpec) addFields(
+	t reflect.Type, fieldTag, prefix, goPrefix string, index []int, visited map[reflect.Type]bool,
+) {
+	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
 
 		tag := f.Tag.Get(fieldTag)
-		if tag == "" || tag == "-" {
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		// The structs without tagged fields, e.g. sql.NullString, are encoded as a single value,
+		// which reports the unsupported type.
+		nested := ft.Kind() == reflect.Struct && !isLeaf(ft) && hasTaggedFields(ft, fieldTag, visited)
+
+		// Embedded structs without the tag are flattened into the parent.
+		if f.Anonymous && name == "" {
+			if nested && !visited[ft] {
+				visited[ft] = true
+				s.addFields(ft, fieldTag, prefix, goPrefix+f.Name+".", fieldIndex, visited)
+				delete(visited, ft)
+			}
+			continue
+		}
+		if name == "" || f.PkgPath != "" {
 			continue
 		}
 
-		tag = strings.Split(tag, ",")[0]
-		if tag == "" {
+		if nested {
+			if !visited[ft] {
+				visited[ft] = true
+				s.addFields(ft, fieldTag, prefix+name+".", goPrefix+f.Name+".", fieldIndex, visited)
+				delete(visited, ft)
+			}
 			continue
 		}
 
-		// Use the built-in decoder.
-		out.set(tag, &structField{index: i, fn: decoders[f.Type.Kind()]})
+		sf := &structField{
+			name:      prefix + name,
+			goName:    goPrefix + f.Name,
+			typ:       f.Type,
+			index:     fieldIndex,
+			omitEmpty: hasOption(opts, "omitempty"),
+			format:    optionValue(opts, "format"),
+			ttl:       optionValue(opts, "ttl"),
+		}
+		if f.Type.Kind() == reflect.Map && f.Type.Key().Kind() == reflect.String && !isLeaf(f.Type) {
+			sf.isMap = true
+		}
+		s.set(sf.name, sf)
 	}
+}
 
-	return out
+// hasTaggedFields reports whether the struct type t or its embedded structs
+// have any exported field with the tag.
+func hasTaggedFields(t reflect.Type, fieldTag string, visited map[reflect.Type]bool) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(fieldTag)
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !isLeaf(ft) && !visited[ft] {
+				visited[ft] = true
+				ok := hasTaggedFields(ft, fieldTag, visited)
+				delete(visited, ft)
+				if ok {
+					return true
+				}
+			}
+			continue
+		}
+		if name != "" && f.PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isLeaf reports whether the values of the struct or map type t are encoded as a single value
+// rather than as the fields of t.
+func isLeaf(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+	if _, ok := lookupCodec(t); ok {
+		return true
+	}
+	pt := reflect.PtrTo(t)
+	for _, iface := range []reflect.Type{
+		binaryMarshalerType, binaryUnmarshalerType, textMarshalerType, textUnmarshalerType, scannerType,
+	} {
+		if t.Implements(iface) || pt.Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+	textMarshalerType     = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	scannerType           = reflect.TypeOf((*Scanner)(nil)).Elem()
+)
+
+// hasOption reports whether the tag options, e.g. "omitempty,ttl=30s", contain the option.
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// optionValue returns the value of the tag option, e.g. "30s" for "ttl=30s".
+func optionValue(opts, name string) string {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if k, v, ok := strings.Cut(opt, "="); ok && k == name {
+			return v
+		}
+	}
+	return ""
 }
 
 //------------------------------------------------------------------------------
 
 // structField represents a single field in a target struct.
 type structField struct {
-	index int
-	fn    decoderFunc
+	name      string
+	goName    string
+	typ       reflect.Type
+	index     []int
+	omitEmpty bool
+	format    string
+	ttl       string
+	isMap     bool
+}
+
+// value returns the field of the struct v. If alloc is false, it returns false
+// when the field is inside a nil embedded or nested struct pointer,
+// otherwise the pointers are allocated.
+func (f *structField) value(v reflect.Value, alloc bool) (reflect.Value, bool) {
+	for i, x := range f.index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
 }
 
 //------------------------------------------------------------------------------
@@ -84,38 +256,35 @@ type StructValue struct {
 
 func (s StructValue) Scan(key string, value string) error {
 	field, ok := s.spec.m[key]
-	if !ok {
-		return nil
-	}
-
-	v := s.value.Field(field.index)
-	isPtr := v.Kind() == reflect.Ptr
-
-	if isPtr && v.IsNil() {
-		v.Set(reflect.New(v.Type().Elem()))
-	}
-	if !isPtr && v.Type().Name() != "" && v.CanAddr() {
-		v = v.Addr()
-		isPtr = true
+	if ok {
+		v, _ := field.value(s.value, true)
+		return s.wrapErr(field, value, decodeValue(v, value, field.format))
 	}
 
-	if isPtr && v.Type().NumMethod() > 0 && v.CanInterface() {
-		switch scan := v.Interface().(type) {
-		case Scanner:
-			return scan.ScanRedis(value)
-		case encoding.TextUnmarshaler:
-			return scan.UnmarshalText(util.StringToBytes(value))
+	for _, field := range s.spec.maps {
+		if !strings.HasPrefix(key, field.name) || len(key) <= len(field.name) || key[len(field.name)] != '.' {
+			continue
+		}
+		v, _ := field.value(s.value, true)
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
 		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := decodeValue(elem, value, field.format); err != nil {
+			return s.wrapErr(field, value, err)
+		}
+		mapKey := reflect.ValueOf(key[len(field.name)+1:]).Convert(v.Type().Key())
+		v.SetMapIndex(mapKey, elem)
+		return nil
 	}
 
-	if isPtr {
-		v = v.Elem()
-	}
+	return nil
+}
 
-	if err := field.fn(v, value); err != nil {
-		t := s.value.Type()
-		return fmt.Errorf("cannot scan redis.result %s into struct field %s.%s of type %s, error-%s",
-			value, t.Name(), t.Field(field.index).Name, t.Field(field.index).Type, err.Error())
+func (s StructValue) wrapErr(field *structField, value string, err error) error {
+	if err == nil {
+		return nil
 	}
-	return nil
+	return fmt.Errorf("cannot scan redis.result %s into struct field %s.%s of type %s, error-%s",
+		value, s.value.Type().Name(), field.goName, field.typ, err.Error())
 }
@@ -3,6 +3,7 @@ package pool
 import (
 	"bufio"
 	"context"
+	"errors"
 	"net"
 	"sync/atomic"
 	"time"
@@ -12,6 +13,11 @@ import (
 
 var noDeadline = time.Time{}
 
+var errUnexpectedRead = errors.New("unexpected read from socket")
+
+// pushReadTimeout bounds the time spent reading a partially received push reply.
+const pushReadTimeout = 100 * time.Millisecond
+
 type Conn struct {
 	usedAt  int64 // atomic
 	netConn net.Conn
@@ -23,6 +29,9 @@ type Conn struct {
 	Inited    bool
 	pooled    bool
 	createdAt time.Time
+
+	hasPushHandler bool
+	onClose        func()
 }
 
 func NewConn(netConn net.Conn) *Conn {
@@ -52,6 +61,59 @@ func (cn *Conn) SetNetConn(netConn net.Conn) {
 	cn.bw.Reset(netConn)
 }
 
+// SetPushHandler sets the function that handles RESP3 push replies received by the connection.
+// Connections with a push handler are not discarded by the pool because of pending push replies.
+func (cn *Conn) SetPushHandler(fn func(push []interface{}) error) {
+	cn.rd.SetPushHandler(fn)
+	cn.hasPushHandler = fn != nil
+}
+
+// KeepAttributes sets whether the RESP3 attributes of the replies are collected
+// instead of being discarded, see proto.Reader.Attributes.
+func (cn *Conn) KeepAttributes(keep bool) {
+	cn.rd.KeepAttributes(keep)
+}
+
+// SetCodec sets the codec that marshals the command arguments without built-in encoding.
+func (cn *Conn) SetCodec(codec proto.Codec) {
+	cn.wr.SetCodec(codec)
+}
+
+// SetOnClose sets the function that is called when the connection is closed.
+func (cn *Conn) SetOnClose(fn func()) {
+	cn.onClose = fn
+}
+
+// drainPushes handles the push replies that are received by the connection
+// but not read yet. It reports false if there is any other unread data.
+func (cn *Conn) drainPushes() bool {
+	if !cn.hasPushHandler {
+		return false
+	}
+	for {
+		if cn.rd.Buffered() == 0 {
+			switch err := connCheck(cn.netConn); err {
+			case nil:
+				return true
+			case errUnexpectedRead:
+			default:
+				return false
+			}
+		}
+
+		if err := cn.netConn.SetReadDeadline(time.Now().Add(pushReadTimeout)); err != nil {
+			return false
+		}
+		b, err := cn.rd.Peek(1)
+		if err != nil || b[0] != proto.RespPush {
+			return false
+		}
+		if err := cn.rd.HandlePush(); err != nil {
+			return false
+		}
+	}
+}
+
 func (cn *Conn) Write(b []byte) (int, error) {
 	return cn.netConn.Write(b)
 }
@@ -71,9 +133,30 @@ func (cn *Conn) WithReader(
 			return err
 		}
 	}
+	if cn.hasPushHandler {
+		if err := cn.readPushes(); err != nil {
+			return err
+		}
+	}
 	return fn(cn.rd)
 }
 
+// readPushes handles the push replies that precede the reply.
+func (cn *Conn) readPushes() error {
+	for {
+		b, err := cn.rd.Peek(1)
+		if err != nil {
+			return err
+		}
+		if b[0] != proto.RespPush {
+			return nil
+		}
+		if err := cn.rd.HandlePush(); err != nil {
+			return err
+		}
+	}
+}
+
 func (cn *Conn) WithWriter(
 	ctx context.Context, timeout time.Duration, fn func(wr *proto.Writer) error,
 ) error {
@@ -95,6 +178,9 @@ func (cn *Conn) WithWriter(
 }
 
 func (cn *Conn) Close() error {
+	if cn.onClose != nil {
+		cn.onClose()
+	}
 	return cn.netConn.Close()
 }
 
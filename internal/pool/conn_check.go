@@ -4,15 +4,12 @@
 package pool
 
 import (
-	"errors"
 	"io"
 	"net"
 	"syscall"
 	"time"
 )
 
-var errUnexpectedRead = errors.New("unexpected read from socket")
-
 func connCheck(conn net.Conn) error {
 	// Reset previous timeout.
 	_ = conn.SetDeadline(time.Time{})
@@ -30,7 +27,8 @@ func connCheck(conn net.Conn) error {
 
 	if err := rawConn.Read(func(fd uintptr) bool {
 		var buf [1]byte
-		n, err := syscall.Read(int(fd), buf[:])
+		// Peek so that the unread data can still be consumed, e.g. RESP3 push replies.
+		n, _, err := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK)
 		switch {
 		case n == 0 && err == nil:
 			sysErr = io.EOF
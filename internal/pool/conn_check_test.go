@@ -4,12 +4,15 @@
 package pool
 
 import (
+	"context"
 	"net"
 	"net/http/httptest"
 	"time"
 
 	. "github.com/bsm/ginkgo/v2"
 	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9/internal/proto"
 )
 
 var _ = Describe("tests conn_check with real conns", func() {
@@ -46,3 +49,88 @@ var _ = Describe("tests conn_check with real conns", func() {
 		Expect(conn.Close()).NotTo(HaveOccurred())
 	})
 })
+
+var _ = Describe("drains push replies", func() {
+	var ln net.Listener
+	var server, client net.Conn
+
+	BeforeEach(func() {
+		var err error
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		client, err = net.DialTimeout("tcp", ln.Addr().String(), time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		server, err = ln.Accept()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = server.Close()
+		_ = client.Close()
+		_ = ln.Close()
+	})
+
+	It("handles pending push replies", func() {
+		var pushes [][]interface{}
+		cn := NewConn(client)
+		cn.SetPushHandler(func(push []interface{}) error {
+			pushes = append(pushes, push)
+			return nil
+		})
+
+		_, err := server.Write([]byte(">2\r\n$10\r\ninvalidate\r\n*1\r\n$3\r\nfoo\r\n>2\r\n$10\r\ninvalidate\r\n_\r\n"))
+		Expect(err).NotTo(HaveOccurred())
+		time.Sleep(10 * time.Millisecond)
+
+		Expect(cn.drainPushes()).To(BeTrue())
+		Expect(pushes).To(Equal([][]interface{}{
+			{"invalidate", []interface{}{"foo"}},
+			{"invalidate", nil},
+		}))
+		Expect(connCheck(client)).NotTo(HaveOccurred())
+	})
+
+	It("reads push replies preceding the reply", func() {
+		var pushes [][]interface{}
+		cn := NewConn(client)
+		cn.SetPushHandler(func(push []interface{}) error {
+			pushes = append(pushes, push)
+			return nil
+		})
+
+		_, err := server.Write([]byte(">2\r\n$7\r\nmessage\r\n$5\r\nhello\r\n+OK\r\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = cn.WithReader(context.Background(), time.Second, func(rd *proto.Reader) error {
+			s, err := rd.ReadString()
+			Expect(s).To(Equal("OK"))
+			return err
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pushes).To(Equal([][]interface{}{{"message", "hello"}}))
+	})
+
+	It("reports other unread data", func() {
+		cn := NewConn(client)
+		cn.SetPushHandler(func(push []interface{}) error {
+			return nil
+		})
+
+		_, err := server.Write([]byte("+OK\r\n"))
+		Expect(err).NotTo(HaveOccurred())
+		time.Sleep(10 * time.Millisecond)
+
+		Expect(cn.drainPushes()).To(BeFalse())
+	})
+
+	It("requires a push handler", func() {
+		cn := NewConn(client)
+
+		_, err := server.Write([]byte(">1\r\n$3\r\nfoo\r\n"))
+		Expect(err).NotTo(HaveOccurred())
+		time.Sleep(10 * time.Millisecond)
+
+		Expect(cn.drainPushes()).To(BeFalse())
+	})
+})
@@ -348,7 +348,7 @@ func (p *ConnPool) popIdle() (*Conn, error) {
 }
 
 func (p *ConnPool) Put(ctx context.Context, cn *Conn) {
-	if cn.rd.Buffered() > 0 {
+	if cn.rd.Buffered() > 0 && !cn.drainPushes() {
 		internal.Logger.Printf(ctx, "Conn has unread data")
 		p.Remove(ctx, cn, BadConnError{})
 		return
@@ -493,8 +493,10 @@ func (p *ConnPool) isHealthyConn(cn *Conn) bool {
 		return false
 	}
 
-	if connCheck(cn.netConn) != nil {
-		return false
+	if err := connCheck(cn.netConn); err != nil {
+		if err != errUnexpectedRead || !cn.drainPushes() {
+			return false
+		}
 	}
 
 	cn.SetUsedAt(now)
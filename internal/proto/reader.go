@@ -2,6 +2,7 @@ package proto
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -31,10 +32,13 @@ const (
 	RespPush      = '>' // ><len>\r\n... (same as Array)
 )
 
-// Not used temporarily.
-// Redis has not used these two data types for the time being, and will implement them later.
-// Streamed           = "EOF:"
-// StreamedAggregated = '?'
+// RESP3 streamed types. Streamed values are converted to the values of the known length
+// when they are read, so the callers never see them.
+const (
+	RespStreamed    = '?' // $?\r\n;<len>\r\n<bytes>\r\n...;0\r\n or *?\r\n....\r\n
+	RespStreamedEnd = '.' // .\r\n terminates a streamed aggregate
+	RespChunk       = ';' // ;<len>\r\n<bytes>\r\n chunk of a streamed string
+)
 
 //------------------------------------------------------------------------------
 
@@ -53,17 +57,25 @@ func ParseErrorReply(line []byte) error {
 //------------------------------------------------------------------------------
 
 type Reader struct {
-	rd *bufio.Reader
+	rd  *bufio.Reader
+	src *pendingReader
+
+	onPush func(push []interface{}) error
+
+	keepAttrs bool
+	attrs     map[string]interface{}
 }
 
 func NewReader(rd io.Reader) *Reader {
+	src := &pendingReader{rd: rd}
 	return &Reader{
-		rd: bufio.NewReader(rd),
+		rd:  bufio.NewReader(src),
+		src: src,
 	}
 }
 
 func (r *Reader) Buffered() int {
-	return r.rd.Buffered()
+	return r.rd.Buffered() + len(r.src.pending)
 }
 
 func (r *Reader) Peek(n int) ([]byte, error) {
@@ -71,18 +83,72 @@ func (r *Reader) Peek(n int) ([]byte, error) {
 }
 
 func (r *Reader) Reset(rd io.Reader) {
-	r.rd.Reset(rd)
+	r.src.rd = rd
+	r.src.pending = nil
+	r.rd.Reset(r.src)
+}
+
+// SetPushHandler sets the function that is called for every out-of-band RESP3 push reply.
+// When the handler is set, push replies are consumed by the Reader and never returned
+// to the callers; otherwise they are returned as regular arrays.
+func (r *Reader) SetPushHandler(fn func(push []interface{}) error) {
+	r.onPush = fn
+}
+
+// KeepAttributes sets whether the RESP3 attributes of the replies are collected
+// instead of being discarded, see Attributes.
+func (r *Reader) KeepAttributes(keep bool) {
+	r.keepAttrs = keep
+	r.attrs = nil
+}
+
+// Attributes returns the attributes collected since the previous call.
+// It returns nil if there are no attributes.
+func (r *Reader) Attributes() map[string]interface{} {
+	attrs := r.attrs
+	r.attrs = nil
+	return attrs
+}
+
+// HandlePush reads the next reply, which must be a push reply, and passes it to the push handler.
+func (r *Reader) HandlePush() error {
+	line, err := r.readLine()
+	if err != nil {
+		return err
+	}
+	if line[0] != RespPush || r.onPush == nil {
+		return fmt.Errorf("redis: can't handle push reply: %.100q", line)
+	}
+	return r.dispatchPush(line)
+}
+
+func (r *Reader) dispatchPush(line []byte) error {
+	push, err := r.readSlice(line)
+	if err != nil {
+		return err
+	}
+	return r.onPush(push)
 }
 
 // PeekReplyType returns the data type of the next response without advancing the Reader,
-// and discard the attribute type.
+// and skips the attribute type and the push type if the push handler is set.
 func (r *Reader) PeekReplyType() (byte, error) {
 	b, err := r.rd.Peek(1)
 	if err != nil {
 		return 0, err
 	}
-	if b[0] == RespAttr {
-		if err = r.DiscardNext(); err != nil {
+	switch {
+	case b[0] == RespAttr:
+		line, err := r.readLine()
+		if err != nil {
+			return 0, err
+		}
+		if err = r.readAttr(line); err != nil {
+			return 0, err
+		}
+		return r.PeekReplyType()
+	case b[0] == RespPush && r.onPush != nil:
+		if err = r.HandlePush(); err != nil {
 			return 0, err
 		}
 		return r.PeekReplyType()
@@ -91,7 +157,7 @@ func (r *Reader) PeekReplyType() (byte, error) {
 }
 
 // ReadLine Return a valid reply, it will check the protocol or redis error,
-// and discard the attribute type.
+// and skips the attribute type and the push type if the push handler is set.
 func (r *Reader) ReadLine() ([]byte, error) {
 	line, err := r.readLine()
 	if err != nil {
@@ -110,10 +176,17 @@ func (r *Reader) ReadLine() ([]byte, error) {
 		}
 		return nil, err
 	case RespAttr:
-		if err = r.Discard(line); err != nil {
+		if err = r.readAttr(line); err != nil {
 			return nil, err
 		}
 		return r.ReadLine()
+	case RespPush:
+		if r.onPush != nil {
+			if err = r.dispatchPush(line); err != nil {
+				return nil, err
+			}
+			return r.ReadLine()
+		}
 	}
 
 	// Compatible with RESP2
@@ -124,10 +197,29 @@ func (r *Reader) ReadLine() ([]byte, error) {
 	return line, nil
 }
 
-// readLine returns an error if:
+// readLine is like readRawLine, but it replaces a streamed value with
+// the value of the known length.
+func (r *Reader) readLine() ([]byte, error) {
+	line, err := r.readRawLine()
+	if err != nil {
+		return nil, err
+	}
+	if !isStreamed(line) {
+		return line, nil
+	}
+
+	b, err := r.appendValue(nil, line)
+	if err != nil {
+		return nil, err
+	}
+	r.unread(b)
+	return r.readRawLine()
+}
+
+// readRawLine returns an error if:
 //   - there is a pending read error;
 //   - or line does not end with \r\n.
-func (r *Reader) readLine() ([]byte, error) {
+func (r *Reader) readRawLine() ([]byte, error) {
 	b, err := r.rd.ReadSlice('\n')
 	if err != nil {
 		if err != bufio.ErrBufferFull {
@@ -151,6 +243,150 @@ func (r *Reader) readLine() ([]byte, error) {
 	return b[:len(b)-2], nil
 }
 
+func isStreamed(line []byte) bool {
+	return len(line) == 2 && line[1] == RespStreamed
+}
+
+// appendValue reads the value that starts with line, converting streamed values
+// to the values of the known length, and appends its RESP representation to b.
+func (r *Reader) appendValue(b []byte, line []byte) ([]byte, error) {
+	typ := line[0]
+	switch typ {
+	case RespString, RespVerbatim, RespBlobError:
+		if isStreamed(line) {
+			if typ != RespString {
+				return nil, fmt.Errorf("redis: can't parse streamed reply: %.100q", line)
+			}
+			return r.appendStreamedString(b)
+		}
+		n, err := replyLen(line)
+		b = append(b, line...)
+		b = append(b, '\r', '\n')
+		if err != nil {
+			if err == Nil {
+				return b, nil
+			}
+			return nil, err
+		}
+		start := len(b)
+		b = append(b, make([]byte, n+2)...)
+		if _, err := io.ReadFull(r.rd, b[start:]); err != nil {
+			return nil, err
+		}
+		return b, nil
+	case RespArray, RespSet, RespPush, RespMap, RespAttr:
+		if isStreamed(line) {
+			return r.appendStreamedAggregate(b, typ)
+		}
+		n, err := replyLen(line)
+		b = append(b, line...)
+		b = append(b, '\r', '\n')
+		if err != nil {
+			if err == Nil {
+				return b, nil
+			}
+			return nil, err
+		}
+		if typ == RespMap || typ == RespAttr {
+			n *= 2
+		}
+		for i := 0; i < n; i++ {
+			if b, err = r.appendNextValue(b); err != nil {
+				return nil, err
+			}
+		}
+		if typ == RespAttr {
+			// Attributes are followed by the value they describe.
+			return r.appendNextValue(b)
+		}
+		return b, nil
+	case RespStatus, RespError, RespInt, RespNil, RespFloat, RespBool, RespBigInt:
+		b = append(b, line...)
+		return append(b, '\r', '\n'), nil
+	}
+	return nil, fmt.Errorf("redis: can't parse %.100q", line)
+}
+
+func (r *Reader) appendNextValue(b []byte) ([]byte, error) {
+	line, err := r.readRawLine()
+	if err != nil {
+		return nil, err
+	}
+	return r.appendValue(b, line)
+}
+
+func (r *Reader) appendStreamedString(b []byte) ([]byte, error) {
+	var data bytes.Buffer
+	if _, err := r.copyStreamedString(&data); err != nil {
+		return nil, err
+	}
+
+	b = append(b, RespString)
+	b = strconv.AppendInt(b, int64(data.Len()), 10)
+	b = append(b, '\r', '\n')
+	b = append(b, data.Bytes()...)
+	return append(b, '\r', '\n'), nil
+}
+
+func (r *Reader) appendStreamedAggregate(b []byte, typ byte) ([]byte, error) {
+	var elems []byte
+	var n int
+	for {
+		line, err := r.readRawLine()
+		if err != nil {
+			return nil, err
+		}
+		if line[0] == RespStreamedEnd {
+			break
+		}
+		if elems, err = r.appendValue(elems, line); err != nil {
+			return nil, err
+		}
+		n++
+	}
+
+	if typ == RespMap || typ == RespAttr {
+		if n%2 != 0 {
+			return nil, fmt.Errorf("redis: got %d elements in the streamed map, wanted a multiple of 2", n)
+		}
+		n /= 2
+	}
+
+	b = append(b, typ)
+	b = strconv.AppendInt(b, int64(n), 10)
+	b = append(b, '\r', '\n')
+	b = append(b, elems...)
+	if typ == RespAttr {
+		return r.appendNextValue(b)
+	}
+	return b, nil
+}
+
+// unread puts b in front of the data that is not read yet.
+func (r *Reader) unread(b []byte) {
+	if n := r.rd.Buffered(); n > 0 {
+		buffered, _ := r.rd.Peek(n)
+		b = append(b, buffered...)
+	}
+	r.src.pending = append(b, r.src.pending...)
+	r.rd.Reset(r.src)
+}
+
+// pendingReader returns the pending data before reading from rd.
+type pendingReader struct {
+	pending []byte
+	rd      io.Reader
+}
+
+func (r *pendingReader) Read(b []byte) (int, error) {
+	if len(r.pending) > 0 {
+		n := copy(b, r.pending)
+		r.pending = r.pending[n:]
+		return n, nil
+	}
+	return r.rd.Read(b)
+}
+
 func (r *Reader) ReadReply() (interface{}, error) {
 	line, err := r.ReadLine()
 	if err != nil {
@@ -292,6 +528,43 @@ func (r *Reader) readMap(line []byte) (map[interface{}]interface{}, error) {
 	return m, nil
 }
 
+// readAttr collects the attributes if KeepAttributes is enabled and discards them otherwise.
+func (r *Reader) readAttr(line []byte) error {
+	if !r.keepAttrs {
+		return r.Discard(line)
+	}
+
+	n, err := replyLen(line)
+	if err != nil {
+		return err
+	}
+	if r.attrs == nil {
+		r.attrs = make(map[string]interface{}, n)
+	}
+	for i := 0; i < n; i++ {
+		k, err := r.ReadReply()
+		if err != nil {
+			return err
+		}
+		v, err := r.ReadReply()
+		if err != nil {
+			if err == Nil {
+				v = nil
+			} else if rerr, ok := err.(RedisError); ok {
+				v = rerr
+			} else {
+				return err
+			}
+		}
+		key, ok := k.(string)
+		if !ok {
+			key = fmt.Sprint(k)
+		}
+		r.attrs[key] = v
+	}
+	return nil
+}
+
 // -------------------------------
 
 func (r *Reader) ReadInt() (int64, error) {
@@ -394,6 +667,79 @@ func (r *Reader) ReadString() (string, error) {
 	return "", fmt.Errorf("redis: can't parse reply=%.100q reading string", line)
 }
 
+// ReadStringTo copies the string reply to w without allocating the whole value
+// and returns the number of bytes written.
+func (r *Reader) ReadStringTo(w io.Writer) (int64, error) {
+	typ, err := r.PeekReplyType()
+	if err != nil {
+		return 0, err
+	}
+	if typ == RespString {
+		// Copy streamed strings chunk by chunk instead of converting them.
+		if b, err := r.rd.Peek(2); err == nil && b[1] == RespStreamed {
+			if _, err := r.readRawLine(); err != nil {
+				return 0, err
+			}
+			return r.copyStreamedString(w)
+		}
+	}
+
+	line, err := r.ReadLine()
+	if err != nil {
+		return 0, err
+	}
+	switch line[0] {
+	case RespString:
+		n, err := replyLen(line)
+		if err != nil {
+			return 0, err
+		}
+		return r.copyBulk(w, n)
+	case RespStatus, RespInt, RespFloat, RespBigInt:
+		n, err := w.Write(line[1:])
+		return int64(n), err
+	}
+	return 0, fmt.Errorf("redis: can't parse reply=%.100q reading string", line)
+}
+
+func (r *Reader) copyBulk(w io.Writer, n int) (int64, error) {
+	written, err := io.CopyN(w, r.rd, int64(n))
+	if err != nil {
+		return written, err
+	}
+	_, err = r.rd.Discard(2)
+	return written, err
+}
+
+func (r *Reader) copyStreamedString(w io.Writer) (int64, error) {
+	var written int64
+	for {
+		line, err := r.readRawLine()
+		if err != nil {
+			return written, err
+		}
+		if line[0] != RespChunk {
+			return written, fmt.Errorf("redis: can't parse streamed string chunk: %.100q", line)
+		}
+		n, err := util.Atoi(line[1:])
+		if err != nil {
+			return written, err
+		}
+		if n == 0 {
+			return written, nil
+		}
+		if n < 0 {
+			return written, fmt.Errorf("redis: invalid streamed string chunk: %q", line)
+		}
+
+		m, err := r.copyBulk(w, n)
+		written += m
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
 func (r *Reader) ReadBool() (bool, error) {
 	s, err := r.ReadString()
 	if err != nil {
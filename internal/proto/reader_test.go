@@ -3,6 +3,7 @@ package proto_test
 import (
 	"bytes"
 	"io"
+	"reflect"
 	"testing"
 
 	"github.com/redis/go-redis/v9/internal/proto"
@@ -83,6 +84,292 @@ func TestReader_ReadLine(t *testing.T) {
 	}
 }
 
+func TestReader_PushHandler(t *testing.T) {
+	r := proto.NewReader(bytes.NewBufferString(
+		">2\r\n$10\r\ninvalidate\r\n*1\r\n$3\r\nfoo\r\n" +
+			"$5\r\nhello\r\n" +
+			">2\r\n$10\r\ninvalidate\r\n_\r\n" +
+			":1\r\n",
+	))
+
+	var pushes [][]interface{}
+	r.SetPushHandler(func(push []interface{}) error {
+		pushes = append(pushes, push)
+		return nil
+	})
+
+	s, err := r.ReadString()
+	if err != nil || s != "hello" {
+		t.Fatalf("got %q, %v, wanted hello", s, err)
+	}
+	typ, err := r.PeekReplyType()
+	if err != nil || typ != proto.RespInt {
+		t.Fatalf("got %q, %v, wanted %q", typ, err, proto.RespInt)
+	}
+	n, err := r.ReadInt()
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v, wanted 1", n, err)
+	}
+
+	if len(pushes) != 2 {
+		t.Fatalf("got %d push replies, wanted 2", len(pushes))
+	}
+	if keys, ok := pushes[0][1].([]interface{}); !ok || len(keys) != 1 || keys[0] != "foo" {
+		t.Errorf("got %v, wanted [invalidate [foo]]", pushes[0])
+	}
+	if pushes[1][1] != nil {
+		t.Errorf("got %v, wanted [invalidate <nil>]", pushes[1])
+	}
+}
+
+func TestReader_Attributes(t *testing.T) {
+	const reply = "|1\r\n+key-popularity\r\n%1\r\n$1\r\na\r\n,0.19\r\n" +
+		"*2\r\n:1\r\n|1\r\n+ttl\r\n:3600\r\n:2\r\n" +
+		":3\r\n"
+
+	r := proto.NewReader(bytes.NewBufferString(reply))
+	r.KeepAttributes(true)
+
+	v, err := r.ReadReply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vals, ok := v.([]interface{}); !ok || len(vals) != 2 || vals[1] != int64(2) {
+		t.Fatalf("got %v, wanted [1 2]", v)
+	}
+
+	attrs := r.Attributes()
+	if len(attrs) != 2 || attrs["ttl"] != int64(3600) {
+		t.Fatalf("got %v, wanted key-popularity and ttl attributes", attrs)
+	}
+	if m, ok := attrs["key-popularity"].(map[interface{}]interface{}); !ok || m["a"] != 0.19 {
+		t.Errorf("got %v, wanted map[a:0.19]", attrs["key-popularity"])
+	}
+
+	if n, err := r.ReadInt(); err != nil || n != 3 {
+		t.Fatalf("got %d, %v, wanted 3", n, err)
+	}
+	if attrs := r.Attributes(); attrs != nil {
+		t.Errorf("got %v, wanted no attributes", attrs)
+	}
+
+	r = proto.NewReader(bytes.NewBufferString(reply))
+	if _, err := r.ReadReply(); err != nil {
+		t.Fatal(err)
+	}
+	if attrs := r.Attributes(); attrs != nil {
+		t.Errorf("got %v, wanted discarded attributes", attrs)
+	}
+}
+
+func TestReader_Streamed(t *testing.T) {
+	readTwo := func(read func(r *proto.Reader) (interface{}, error)) func(r *proto.Reader) (interface{}, error) {
+		return func(r *proto.Reader) (interface{}, error) {
+			v1, err := read(r)
+			if err != nil {
+				return nil, err
+			}
+			v2, err := read(r)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{v1, v2}, nil
+		}
+	}
+	readReply := func(r *proto.Reader) (interface{}, error) {
+		return r.ReadReply()
+	}
+	readString := func(r *proto.Reader) (interface{}, error) {
+		return r.ReadString()
+	}
+
+	tests := []struct {
+		name  string
+		reply string
+		read  func(r *proto.Reader) (interface{}, error)
+		want  interface{}
+	}{{
+		name:  "string",
+		reply: "$?\r\n;4\r\nHell\r\n;5\r\no wor\r\n;2\r\nld\r\n;0\r\n",
+		read:  readReply,
+		want:  "Hello world",
+	}, {
+		name:  "empty string",
+		reply: "$?\r\n;0\r\n",
+		read:  readString,
+		want:  "",
+	}, {
+		name:  "string with CRLF",
+		reply: "$?\r\n;3\r\na\r\n\r\n;1\r\nb\r\n;0\r\n",
+		read:  readString,
+		want:  "a\r\nb",
+	}, {
+		name:  "int from string",
+		reply: "$?\r\n;1\r\n4\r\n;1\r\n2\r\n;0\r\n",
+		read: func(r *proto.Reader) (interface{}, error) {
+			return r.ReadInt()
+		},
+		want: int64(42),
+	}, {
+		name:  "array",
+		reply: "*?\r\n:1\r\n$3\r\nfoo\r\n_\r\n.\r\n",
+		read:  readReply,
+		want:  []interface{}{int64(1), "foo", nil},
+	}, {
+		name:  "empty array",
+		reply: "*?\r\n.\r\n",
+		read:  readReply,
+		want:  []interface{}{},
+	}, {
+		name:  "set",
+		reply: "~?\r\n+a\r\n+b\r\n.\r\n",
+		read:  readReply,
+		want:  []interface{}{"a", "b"},
+	}, {
+		name:  "map",
+		reply: "%?\r\n+a\r\n:1\r\n+b\r\n:2\r\n.\r\n",
+		read:  readReply,
+		want:  map[interface{}]interface{}{"a": int64(1), "b": int64(2)},
+	}, {
+		name:  "nested",
+		reply: "*?\r\n$?\r\n;2\r\nab\r\n;0\r\n*?\r\n:1\r\n.\r\n*1\r\n%?\r\n+k\r\n+v\r\n.\r\n.\r\n",
+		read:  readReply,
+		want: []interface{}{
+			"ab",
+			[]interface{}{int64(1)},
+			[]interface{}{map[interface{}]interface{}{"k": "v"}},
+		},
+	}, {
+		name:  "attribute in array",
+		reply: "*?\r\n|1\r\n+ttl\r\n:1\r\n:10\r\n:20\r\n.\r\n",
+		read:  readReply,
+		want:  []interface{}{int64(10), int64(20)},
+	}, {
+		name:  "array len",
+		reply: "*?\r\n+a\r\n+b\r\n.\r\n",
+		read: func(r *proto.Reader) (interface{}, error) {
+			n, err := r.ReadArrayLen()
+			if err != nil {
+				return nil, err
+			}
+			vals := []interface{}{n}
+			for i := 0; i < n; i++ {
+				s, err := r.ReadString()
+				if err != nil {
+					return nil, err
+				}
+				vals = append(vals, s)
+			}
+			return vals, nil
+		},
+		want: []interface{}{2, "a", "b"},
+	}, {
+		name:  "map len",
+		reply: "%?\r\n+a\r\n$?\r\n;1\r\n1\r\n;0\r\n.\r\n",
+		read: func(r *proto.Reader) (interface{}, error) {
+			n, err := r.ReadMapLen()
+			if err != nil {
+				return nil, err
+			}
+			k, err := r.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			v, err := r.ReadInt()
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{n, k, v}, nil
+		},
+		want: []interface{}{1, "a", int64(1)},
+	}, {
+		name:  "discard",
+		reply: "*?\r\n$?\r\n;2\r\nab\r\n;0\r\n%?\r\n+k\r\n+v\r\n.\r\n.\r\n:1\r\n",
+		read: func(r *proto.Reader) (interface{}, error) {
+			if err := r.DiscardNext(); err != nil {
+				return nil, err
+			}
+			return r.ReadInt()
+		},
+		want: int64(1),
+	}, {
+		name:  "buffered reply after streamed",
+		reply: "*?\r\n:1\r\n.\r\n*?\r\n:2\r\n.\r\n",
+		read:  readTwo(readReply),
+		want:  []interface{}{[]interface{}{int64(1)}, []interface{}{int64(2)}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := proto.NewReader(bytes.NewBufferString(tt.reply))
+			got, err := tt.read(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, wanted %#v", got, tt.want)
+			}
+			if n := r.Buffered(); n != 0 {
+				t.Errorf("got %d unread bytes, wanted 0", n)
+			}
+		})
+	}
+}
+
+func TestReader_StreamedErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+	}{
+		{"bad chunk", "$?\r\n+OK\r\n"},
+		{"negative chunk", "$?\r\n;-1\r\n"},
+		{"odd map", "%?\r\n+a\r\n.\r\n"},
+		{"streamed verbatim", "=?\r\n;0\r\n"},
+		{"truncated array", "*?\r\n:1\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := proto.NewReader(bytes.NewBufferString(tt.reply))
+			if _, err := r.ReadReply(); err == nil {
+				t.Error("got nil error")
+			}
+		})
+	}
+}
+
+func TestReader_ReadStringTo(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  string
+		err   error
+	}{
+		{"$5\r\nhello\r\n:1\r\n", "hello", nil},
+		{"$0\r\n\r\n:1\r\n", "", nil},
+		{"$?\r\n;3\r\nhel\r\n;2\r\nlo\r\n;0\r\n:1\r\n", "hello", nil},
+		{"|1\r\n+ttl\r\n:1\r\n$5\r\nhello\r\n:1\r\n", "hello", nil},
+		{"+OK\r\n:1\r\n", "OK", nil},
+		{"$-1\r\n:1\r\n", "", proto.Nil},
+		{"_\r\n:1\r\n", "", proto.Nil},
+		{"-ERR failed\r\n:1\r\n", "", proto.RedisError("ERR failed")},
+	}
+
+	for _, tt := range tests {
+		r := proto.NewReader(bytes.NewBufferString(tt.reply))
+		var buf bytes.Buffer
+		n, err := r.ReadStringTo(&buf)
+		if err != tt.err {
+			t.Fatalf("%q: got %v, wanted %v", tt.reply, err, tt.err)
+		}
+		if buf.String() != tt.want || n != int64(len(tt.want)) {
+			t.Errorf("%q: got %q (%d bytes), wanted %q", tt.reply, buf.String(), n, tt.want)
+		}
+		if n, err := r.ReadInt(); err != nil || n != 1 {
+			t.Errorf("%q: got %d, %v reading the next reply", tt.reply, n, err)
+		}
+	}
+}
+
 func benchmarkParseReply(b *testing.B, reply string, wanterr bool) {
 	buf := new(bytes.Buffer)
 	for i := 0; i < b.N; i++ {
@@ -126,6 +126,20 @@ func Scan(b []byte, v interface{}) error {
 	}
 }
 
+// Scannable reports whether Scan can decode into v.
+func Scannable(v interface{}) bool {
+	switch v.(type) {
+	case *string, *[]byte,
+		*int, *int8, *int16, *int32, *int64,
+		*uint, *uint8, *uint16, *uint32, *uint64,
+		*float32, *float64, *bool, *time.Time, *time.Duration,
+		encoding.BinaryUnmarshaler, *net.IP:
+		return true
+	default:
+		return false
+	}
+}
+
 func ScanSlice(data []string, slice interface{}) error {
 	v := reflect.ValueOf(slice)
 	if !v.IsValid() {
@@ -18,11 +18,62 @@ type writer interface {
 	WriteString(s string) (n int, err error)
 }
 
+// BulkReader is an argument whose value of Size bytes is copied from a reader
+// to the connection without buffering the whole value.
+type BulkReader struct {
+	r    io.Reader
+	size int64
+
+	consumed bool
+	offset   int64
+}
+
+func NewBulkReader(r io.Reader, size int64) *BulkReader {
+	return &BulkReader{
+		r:    r,
+		size: size,
+	}
+}
+
+func (b *BulkReader) String() string {
+	return fmt.Sprintf("<%d bytes>", b.size)
+}
+
+// rewind prepares the reader to be written again, which is only possible for io.Seeker readers.
+func (b *BulkReader) rewind() error {
+	seeker, ok := b.r.(io.Seeker)
+	if !b.consumed {
+		b.consumed = true
+		if ok {
+			offset, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			b.offset = offset
+		}
+		return nil
+	}
+
+	if !ok {
+		return fmt.Errorf("redis: can't write the value of %d bytes again, reader is consumed", b.size)
+	}
+	_, err := seeker.Seek(b.offset, io.SeekStart)
+	return err
+}
+
+// Codec marshals the arguments that have no built-in encoding, e.g. structs.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
 type Writer struct {
 	writer
 
 	lenBuf []byte
 	numBuf []byte
+
+	codec Codec
 }
 
 func NewWriter(wr writer) *Writer {
@@ -34,6 +85,17 @@ func NewWriter(wr writer) *Writer {
 	}
 }
 
+// SetCodec sets the codec that marshals the arguments WriteArg has no encoding for.
+// Without a codec, writing such an argument fails.
+func (w *Writer) SetCodec(codec Codec) {
+	w.codec = codec
+}
+
+// Codec returns the codec set by SetCodec.
+func (w *Writer) Codec() Codec {
+	return w.codec
+}
+
 func (w *Writer) WriteArgs(args []interface{}) error {
 	if err := w.WriteByte(RespArray); err != nil {
 		return err
@@ -109,12 +171,35 @@ func (w *Writer) WriteArg(v interface{}) error {
 		return w.bytes(b)
 	case net.IP:
 		return w.bytes(v)
+	case *BulkReader:
+		return w.bulkReader(v)
 	default:
+		if w.codec != nil {
+			b, err := w.codec.Marshal(v)
+			if err != nil {
+				return err
+			}
+			return w.bytes(b)
+		}
 		return fmt.Errorf(
 			"redis: can't marshal %T (implement encoding.BinaryMarshaler)", v)
 	}
 }
 
+// Writable reports whether WriteArg can write v.
+func Writable(v interface{}) bool {
+	switch v.(type) {
+	case nil, string, []byte,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64, bool, time.Time, time.Duration,
+		encoding.BinaryMarshaler, net.IP, *BulkReader:
+		return true
+	default:
+		return false
+	}
+}
+
 func (w *Writer) bytes(b []byte) error {
 	if err := w.WriteByte(RespString); err != nil {
 		return err
@@ -131,6 +216,32 @@ func (w *Writer) bytes(b []byte) error {
 	return w.crlf()
 }
 
+func (w *Writer) bulkReader(b *BulkReader) error {
+	if err := b.rewind(); err != nil {
+		return err
+	}
+
+	if err := w.WriteByte(RespString); err != nil {
+		return err
+	}
+
+	w.lenBuf = strconv.AppendInt(w.lenBuf[:0], b.size, 10)
+	w.lenBuf = append(w.lenBuf, '\r', '\n')
+	if _, err := w.Write(w.lenBuf); err != nil {
+		return err
+	}
+
+	n, err := io.CopyN(w.writer, b.r, b.size)
+	if err == io.EOF {
+		return fmt.Errorf("redis: got %d bytes from the reader, wanted %d", n, b.size)
+	}
+	if err != nil {
+		return err
+	}
+
+	return w.crlf()
+}
+
 func (w *Writer) string(s string) error {
 	return w.bytes(util.StringToBytes(s))
 }
@@ -3,8 +3,10 @@ package proto_test
 import (
 	"bytes"
 	"encoding"
+	"encoding/json"
 	"fmt"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -73,8 +75,58 @@ var _ = Describe("WriteBuffer", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(buf.String()).To(Equal(fmt.Sprintf("*1\r\n$16\r\n%s\r\n", bytes.NewBuffer(ip))))
 	})
+
+	It("should copy bulk readers", func() {
+		r := strings.NewReader("hello world")
+		arg := proto.NewBulkReader(r, 5)
+
+		err := wr.WriteArgs([]interface{}{"set", "key", arg})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("*3\r\n$3\r\nset\r\n$3\r\nkey\r\n$5\r\nhello\r\n"))
+
+		// Seekers are rewound when the command is written again.
+		buf.Reset()
+		err = wr.WriteArg(arg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("$5\r\nhello\r\n"))
+	})
+
+	It("should not write consumed bulk readers again", func() {
+		arg := proto.NewBulkReader(bytes.NewBufferString("hello"), 5)
+		Expect(wr.WriteArg(arg)).NotTo(HaveOccurred())
+		Expect(wr.WriteArg(arg)).To(MatchError("redis: can't write the value of 5 bytes again, reader is consumed"))
+	})
+
+	It("should report short bulk readers", func() {
+		arg := proto.NewBulkReader(strings.NewReader("hi"), 5)
+		err := wr.WriteArg(arg)
+		Expect(err).To(MatchError("redis: got 2 bytes from the reader, wanted 5"))
+	})
+
+	It("should marshal unsupported args with the codec", func() {
+		type point struct{ X, Y int }
+
+		err := wr.WriteArg(point{1, 2})
+		Expect(err).To(MatchError("redis: can't marshal proto_test.point (implement encoding.BinaryMarshaler)"))
+
+		wr.SetCodec(jsonCodec{})
+		buf.Reset()
+		err = wr.WriteArgs([]interface{}{"set", point{1, 2}, &MyType{}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("*3\r\n$3\r\nset\r\n$13\r\n{\"X\":1,\"Y\":2}\r\n$5\r\nhello\r\n"))
+	})
 })
 
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
 type discard struct{}
 
 func (discard) Write(b []byte) (int, error) {
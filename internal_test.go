@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -77,6 +78,107 @@ var _ = Describe("newClusterState", func() {
 	})
 })
 
+var _ = Describe("clusterShardsSlots", func() {
+	shards := []ClusterShard{{
+		Slots: []SlotRange{{Start: 0, End: 99}, {Start: 200, End: 299}},
+		Nodes: []Node{{
+			ID:       "replica1",
+			IP:       "10.0.0.2",
+			Port:     6379,
+			TLSPort:  6380,
+			Role:     "replica",
+			Health:   "online",
+			Endpoint: "10.0.0.2",
+			Hostname: "replica1.example.com",
+		}, {
+			ID:       "master",
+			IP:       "10.0.0.1",
+			Port:     6379,
+			TLSPort:  6380,
+			Role:     "master",
+			Health:   "online",
+			Endpoint: "?",
+			Hostname: "master.example.com",
+
+			AvailabilityZone: "us-east-1a",
+		}, {
+			ID:     "replica2",
+			IP:     "10.0.0.3",
+			Port:   6379,
+			Role:   "replica",
+			Health: "loading",
+		}, {
+			ID:     "replica3",
+			IP:     "10.0.0.4",
+			Port:   6379,
+			Role:   "replica",
+			Health: "failed",
+		}},
+	}, {
+		Nodes: []Node{{ID: "empty", IP: "10.0.0.5", Port: 6379, Role: "master"}},
+	}}
+
+	It("converts shards to slots", func() {
+		slots := clusterShardsSlots(shards, false)
+		Expect(slots).To(HaveLen(2))
+		Expect(slots[0].Start).To(Equal(0))
+		Expect(slots[0].End).To(Equal(99))
+		Expect(slots[1].Start).To(Equal(200))
+		Expect(slots[1].End).To(Equal(299))
+
+		Expect(slots[0].Nodes).To(Equal([]ClusterNode{{
+			ID:   "master",
+			Addr: "master.example.com:6379",
+			NetworkingMetadata: map[string]string{
+				"ip":                "10.0.0.1",
+				"hostname":          "master.example.com",
+				"health":            "online",
+				"availability-zone": "us-east-1a",
+			},
+		}, {
+			ID:   "replica1",
+			Addr: "10.0.0.2:6379",
+			NetworkingMetadata: map[string]string{
+				"ip":       "10.0.0.2",
+				"hostname": "replica1.example.com",
+				"health":   "online",
+			},
+		}}))
+	})
+
+	It("uses TLS ports", func() {
+		slots := clusterShardsSlots(shards, true)
+		Expect(slots[0].Nodes[0].Addr).To(Equal("master.example.com:6380"))
+		Expect(slots[0].Nodes[1].Addr).To(Equal("10.0.0.2:6380"))
+	})
+
+	It("prefers the online master", func() {
+		slots := clusterShardsSlots([]ClusterShard{{
+			Slots: []SlotRange{{Start: 0, End: 16383}},
+			Nodes: []Node{
+				{ID: "old", IP: "10.0.0.1", Port: 6379, Role: "master", Health: "failed"},
+				{ID: "new", IP: "10.0.0.2", Port: 6379, Role: "master", Health: "online"},
+			},
+		}, {
+			Slots: []SlotRange{{Start: 100, End: 200}},
+			Nodes: []Node{
+				{ID: "failed", IP: "10.0.0.3", Port: 6379, Role: "master", Health: "failed"},
+			},
+		}}, false)
+		Expect(slots).To(HaveLen(2))
+		Expect(slots[0].Nodes).To(HaveLen(1))
+		Expect(slots[0].Nodes[0].ID).To(Equal("new"))
+		Expect(slots[1].Nodes[0].ID).To(Equal("failed"))
+	})
+
+	It("detects the unsupported CLUSTER SHARDS", func() {
+		Expect(isUnknownCommandError(proto.RedisError(
+			"ERR Unknown subcommand or wrong number of arguments for 'SHARDS'. Try CLUSTER HELP."))).To(BeTrue())
+		Expect(isUnknownCommandError(proto.RedisError("ERR unknown command 'cluster'"))).To(BeTrue())
+		Expect(isUnknownCommandError(proto.RedisError("LOADING Redis is loading the dataset in memory"))).To(BeFalse())
+	})
+})
+
 type fixedHash string
 
 func (h fixedHash) Get(string) string {
@@ -299,6 +401,60 @@ func (e timeoutErr) Error() string {
 	return "i/o timeout"
 }
 
+var _ = Describe("clusterSplitCmd", func() {
+	ctx := context.Background()
+
+	It("merges the MSET sent with Do when some pieces fail", func() {
+		cmd := NewCmd(ctx, "mset", "A", "a", "B", "b", "C", "c")
+		split := splitClusterCmd(ctx, cmd)
+		Expect(split.pieces).To(HaveLen(3))
+
+		split.pieces[0].(*Cmd).SetVal("OK")
+		split.pieces[1].SetErr(proto.RedisError("MOVED 3300 127.0.0.1:6379"))
+		split.pieces[2].(*Cmd).SetVal("OK")
+		split.merge()
+
+		var partialErr *ClusterPartialError
+		Expect(errors.As(cmd.Err(), &partialErr)).To(BeTrue())
+		Expect(partialErr.Keys).To(Equal(3))
+		Expect(partialErr.Errs).To(Equal(map[string]error{
+			"B": proto.RedisError("MOVED 3300 127.0.0.1:6379"),
+		}))
+		Expect(cmd.Val()).To(BeNil())
+	})
+
+	It("sums the counts of the pieces that succeeded", func() {
+		cmd := NewIntCmd(ctx, "del", "A", "B", "C", "D")
+		split := splitClusterCmd(ctx, cmd)
+		Expect(split.pieces).To(HaveLen(4))
+
+		split.pieces[0].(*IntCmd).SetVal(1)
+		split.pieces[1].SetErr(proto.RedisError("CLUSTERDOWN The cluster is down"))
+		split.pieces[2].(*IntCmd).SetVal(1)
+		split.pieces[3].SetErr(proto.RedisError("CLUSTERDOWN The cluster is down"))
+		split.merge()
+
+		var partialErr *ClusterPartialError
+		Expect(errors.As(cmd.Err(), &partialErr)).To(BeTrue())
+		Expect(partialErr.Errs).To(HaveLen(2))
+		Expect(partialErr.Errs).To(HaveKey("B"))
+		Expect(partialErr.Errs).To(HaveKey("D"))
+		Expect(cmd.Val()).To(Equal(int64(2)))
+	})
+
+	It("returns the error if all pieces fail", func() {
+		cmd := NewIntCmd(ctx, "exists", "A", "B")
+		split := splitClusterCmd(ctx, cmd)
+		for _, piece := range split.pieces {
+			piece.SetErr(proto.RedisError("CLUSTERDOWN The cluster is down"))
+		}
+		split.merge()
+
+		Expect(cmd.Err()).To(Equal(proto.RedisError("CLUSTERDOWN The cluster is down")))
+		Expect(cmd.Val()).To(Equal(int64(0)))
+	})
+})
+
 var _ = Describe("withConn", func() {
 	var client *Client
 
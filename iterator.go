@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"sync"
 )
 
 // ScanIterator is used to incrementally iterate over a collection of elements.
@@ -64,3 +65,229 @@ func (it *ScanIterator) Val() string {
 	}
 	return v
 }
+
+//------------------------------------------------------------------------------
+
+// ScanAllIterator is used to incrementally iterate over the keys of all the masters
+// of a ClusterClient or all the shards of a Ring. See ClusterClient.ScanAll.
+type ScanAllIterator struct {
+	match   string
+	count   int64
+	keyType string
+
+	// nodes returns the nodes to scan. The reload forces a fresh topology.
+	nodes func(ctx context.Context, reload bool) ([]scanAllNode, error)
+
+	mu      sync.Mutex
+	seen    map[string]struct{}
+	scanned map[string]string // node addr -> node owns
+
+	queue  []scanAllNode
+	cur    *scanAllNode
+	cursor uint64
+	page   []string
+	pos    int
+	val    string
+	err    error
+	done   bool
+}
+
+type scanAllNode struct {
+	addr string
+	// owns describes the keys of the node, e.g. its slot ranges.
+	// The node is scanned again when it changes.
+	owns   string
+	client *Client
+}
+
+func newScanAllIterator(
+	ctx context.Context,
+	match string,
+	count int64,
+	keyType string,
+	nodes func(ctx context.Context, reload bool) ([]scanAllNode, error),
+) *ScanAllIterator {
+	it := &ScanAllIterator{
+		match:   match,
+		count:   count,
+		keyType: keyType,
+		nodes:   nodes,
+		seen:    make(map[string]struct{}),
+		scanned: make(map[string]string),
+	}
+	it.queue, it.err = it.nextRound(ctx, false)
+	return it
+}
+
+// Err returns the last iterator error, if any.
+func (it *ScanAllIterator) Err() error {
+	return it.err
+}
+
+// Next advances the cursor and returns true if more keys can be read.
+func (it *ScanAllIterator) Next(ctx context.Context) bool {
+	for it.err == nil && !it.done {
+		for it.pos < len(it.page) {
+			key := it.page[it.pos]
+			it.pos++
+			if it.markSeen(key) {
+				it.val = key
+				return true
+			}
+		}
+		it.page, it.pos = nil, 0
+
+		switch {
+		case it.cur != nil:
+			node := *it.cur
+			keys, cursor, err := it.scan(ctx, node, it.cursor)
+			if err != nil {
+				it.cur = nil
+				it.err = it.nodeFailed(ctx, node, err)
+				continue
+			}
+			it.page, it.cursor = keys, cursor
+			if cursor == 0 {
+				it.cur = nil
+				it.markScanned(node)
+			}
+		case len(it.queue) > 0:
+			it.cur, it.cursor = &it.queue[0], 0
+			it.queue = it.queue[1:]
+		default:
+			it.queue, it.err = it.nextRound(ctx, true)
+			it.done = it.err == nil && len(it.queue) == 0
+		}
+	}
+	return false
+}
+
+// Val returns the key at the current cursor position.
+func (it *ScanAllIterator) Val() string {
+	return it.val
+}
+
+// ForEach concurrently scans the nodes and calls the fn for every key.
+// The fn is called concurrently by multiple goroutines. It returns the first error if any.
+// ForEach must not be mixed with Next.
+func (it *ScanAllIterator) ForEach(ctx context.Context, fn func(ctx context.Context, key string) error) error {
+	queue, err := it.queue, it.err
+	it.queue = nil
+
+	for err == nil && len(queue) > 0 {
+		var wg sync.WaitGroup
+		errCh := make(chan error, 1)
+
+		for _, node := range queue {
+			wg.Add(1)
+			go func(node scanAllNode) {
+				defer wg.Done()
+				if err := it.scanNode(ctx, node, fn); err != nil {
+					select {
+					case errCh <- err:
+					default:
+					}
+				}
+			}(node)
+		}
+
+		wg.Wait()
+
+		select {
+		case err := <-errCh:
+			return err
+		default:
+		}
+
+		queue, err = it.nextRound(ctx, true)
+	}
+	return err
+}
+
+func (it *ScanAllIterator) scanNode(
+	ctx context.Context, node scanAllNode, fn func(ctx context.Context, key string) error,
+) error {
+	var cursor uint64
+	for {
+		keys, next, err := it.scan(ctx, node, cursor)
+		if err != nil {
+			return it.nodeFailed(ctx, node, err)
+		}
+		for _, key := range keys {
+			if !it.markSeen(key) {
+				continue
+			}
+			if err := fn(ctx, key); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			it.markScanned(node)
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (it *ScanAllIterator) scan(ctx context.Context, node scanAllNode, cursor uint64) ([]string, uint64, error) {
+	if it.keyType != "" {
+		return node.client.ScanType(ctx, cursor, it.match, it.count, it.keyType).Result()
+	}
+	return node.client.Scan(ctx, cursor, it.match, it.count).Result()
+}
+
+// nextRound returns the nodes that are not scanned yet or whose keys changed
+// since they were scanned, e.g. because the slots were migrated.
+func (it *ScanAllIterator) nextRound(ctx context.Context, reload bool) ([]scanAllNode, error) {
+	nodes, err := it.nodes(ctx, reload)
+	if err != nil {
+		return nil, err
+	}
+
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	var queue []scanAllNode
+	for _, node := range nodes {
+		if owns, ok := it.scanned[node.addr]; !ok || owns != node.owns {
+			queue = append(queue, node)
+		}
+	}
+	return queue, nil
+}
+
+// nodeFailed returns nil if the failed node is gone after reloading the topology,
+// e.g. after a failover. Its keys are then scanned on the nodes that took them over.
+func (it *ScanAllIterator) nodeFailed(ctx context.Context, node scanAllNode, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	nodes, reloadErr := it.nodes(ctx, true)
+	if reloadErr != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if n.addr == node.addr {
+			return err
+		}
+	}
+	return nil
+}
+
+// markSeen returns false if the key was already returned.
+func (it *ScanAllIterator) markSeen(key string) bool {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	if _, ok := it.seen[key]; ok {
+		return false
+	}
+	it.seen[key] = struct{}{}
+	return true
+}
+
+func (it *ScanAllIterator) markScanned(node scanAllNode) {
+	it.mu.Lock()
+	it.scanned[node.addr] = node.owns
+	it.mu.Unlock()
+}
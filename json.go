@@ -0,0 +1,692 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// JSONCmdable is the RedisJSON command family.
+type JSONCmdable interface {
+	JSONArrAppend(ctx context.Context, key, path string, values ...interface{}) *IntPointerSliceCmd
+	JSONArrIndex(ctx context.Context, key, path string, value interface{}) *IntPointerSliceCmd
+	JSONArrIndexWithArgs(ctx context.Context, key, path string, options *JSONArrIndexArgs, value interface{}) *IntPointerSliceCmd
+	JSONArrInsert(ctx context.Context, key, path string, index int64, values ...interface{}) *IntPointerSliceCmd
+	JSONArrLen(ctx context.Context, key, path string) *IntPointerSliceCmd
+	JSONArrPop(ctx context.Context, key, path string, index int) *JSONSliceCmd
+	JSONArrTrim(ctx context.Context, key, path string, start, stop int) *IntPointerSliceCmd
+	JSONClear(ctx context.Context, key, path string) *IntCmd
+	JSONDel(ctx context.Context, key, path string) *IntCmd
+	JSONGet(ctx context.Context, key string, paths ...string) *JSONCmd
+	JSONGetWithArgs(ctx context.Context, key string, options *JSONGetArgs, paths ...string) *JSONCmd
+	JSONMerge(ctx context.Context, key, path string, value interface{}) *StatusCmd
+	JSONMGet(ctx context.Context, path string, keys ...string) *JSONSliceCmd
+	JSONMSet(ctx context.Context, docs ...JSONSetParams) *StatusCmd
+	JSONNumIncrBy(ctx context.Context, key, path string, value float64) *JSONCmd
+	JSONObjKeys(ctx context.Context, key, path string) *SliceCmd
+	JSONObjLen(ctx context.Context, key, path string) *IntPointerSliceCmd
+	JSONSet(ctx context.Context, key, path string, value interface{}) *StatusCmd
+	JSONSetMode(ctx context.Context, key, path string, value interface{}, mode string) *StatusCmd
+	JSONStrAppend(ctx context.Context, key, path, value string) *IntPointerSliceCmd
+	JSONStrLen(ctx context.Context, key, path string) *IntPointerSliceCmd
+	JSONType(ctx context.Context, key, path string) *JSONTypeCmd
+}
+
+type JSONGetArgs struct {
+	Indent  string
+	Newline string
+	Space   string
+}
+
+type JSONArrIndexArgs struct {
+	Start int
+	Stop  *int
+}
+
+type JSONSetParams struct {
+	Key   string
+	Path  string
+	Value interface{}
+}
+
+// jsonArg returns the JSON encoding of the value.
+// Strings and byte slices are expected to contain JSON and are sent as is.
+func jsonArg(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case []byte:
+		return value, nil
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+}
+
+// appendJSONArgs appends the JSON encoding of the values to the args.
+func appendJSONArgs(args []interface{}, values []interface{}) ([]interface{}, error) {
+	for _, value := range values {
+		arg, err := jsonArg(value)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+//------------------------------------------------------------------------------
+
+// JSONCmd is a reply that contains JSON. Scan decodes the reply into a Go value.
+type JSONCmd struct {
+	baseCmd
+
+	val string
+}
+
+var _ Cmder = (*JSONCmd)(nil)
+
+func NewJSONCmd(ctx context.Context, args ...interface{}) *JSONCmd {
+	return &JSONCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *JSONCmd) SetVal(val string) {
+	cmd.val = val
+}
+
+func (cmd *JSONCmd) Val() string {
+	return cmd.val
+}
+
+func (cmd *JSONCmd) Result() (string, error) {
+	return cmd.val, cmd.err
+}
+
+// Expanded returns the reply decoded into interface{} values.
+func (cmd *JSONCmd) Expanded() (interface{}, error) {
+	var val interface{}
+	if err := cmd.Scan(&val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Scan decodes the reply into dst using encoding/json. Note that the replies
+// of JSONPath queries, e.g. "$.a", are arrays of the matching values.
+func (cmd *JSONCmd) Scan(dst interface{}) error {
+	if cmd.err != nil {
+		return cmd.err
+	}
+	return json.Unmarshal([]byte(cmd.val), dst)
+}
+
+func (cmd *JSONCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *JSONCmd) readReply(rd *proto.Reader) (err error) {
+	cmd.val, err = readJSON(rd)
+	return err
+}
+
+// readJSON reads a JSON reply. RESP3 replies that are not strings,
+// e.g. the reply of JSON.NUMINCRBY, are encoded as JSON.
+func readJSON(rd *proto.Reader) (string, error) {
+	typ, err := rd.PeekReplyType()
+	if err != nil {
+		return "", err
+	}
+	switch typ {
+	case proto.RespString, proto.RespStatus, proto.RespVerbatim:
+		return rd.ReadString()
+	}
+
+	v, err := rd.ReadReply()
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(jsonValue(v))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// jsonValue converts the RESP3 maps to the types supported by encoding/json.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = jsonValue(elem)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			m[fmt.Sprint(k)] = jsonValue(elem)
+		}
+		return m
+	default:
+		return v
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// JSONSliceCmd is a reply that contains JSON values or nils, e.g. for the missing keys.
+type JSONSliceCmd struct {
+	baseCmd
+
+	val []interface{}
+}
+
+var _ Cmder = (*JSONSliceCmd)(nil)
+
+func NewJSONSliceCmd(ctx context.Context, args ...interface{}) *JSONSliceCmd {
+	return &JSONSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *JSONSliceCmd) SetVal(val []interface{}) {
+	cmd.val = val
+}
+
+// Val returns the JSON values as strings and nil for the missing values.
+func (cmd *JSONSliceCmd) Val() []interface{} {
+	return cmd.val
+}
+
+func (cmd *JSONSliceCmd) Result() ([]interface{}, error) {
+	return cmd.val, cmd.err
+}
+
+// Scan decodes the values into dst, which must be a pointer to a slice.
+// The missing values are decoded as JSON null.
+func (cmd *JSONSliceCmd) Scan(dst interface{}) error {
+	if cmd.err != nil {
+		return cmd.err
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range cmd.val {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if s, ok := v.(string); ok {
+			b.WriteString(s)
+		} else {
+			b.WriteString("null")
+		}
+	}
+	b.WriteByte(']')
+
+	return json.Unmarshal([]byte(b.String()), dst)
+}
+
+func (cmd *JSONSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *JSONSliceCmd) readReply(rd *proto.Reader) error {
+	typ, err := rd.PeekReplyType()
+	if err != nil {
+		return err
+	}
+	if typ != proto.RespArray {
+		// Legacy paths, e.g. ".a", return a single value.
+		switch s, err := readJSON(rd); {
+		case err == Nil:
+			cmd.val = []interface{}{nil}
+		case err != nil:
+			return err
+		default:
+			cmd.val = []interface{}{s}
+		}
+		return nil
+	}
+
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	cmd.val = make([]interface{}, n)
+	for i := 0; i < len(cmd.val); i++ {
+		switch s, err := readJSON(rd); {
+		case err == Nil:
+			cmd.val[i] = nil
+		case err != nil:
+			return err
+		default:
+			cmd.val[i] = s
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// IntPointerSliceCmd is a reply that contains integers or nils,
+// e.g. for the JSONPath matches that are not arrays.
+type IntPointerSliceCmd struct {
+	baseCmd
+
+	val []*int64
+}
+
+var _ Cmder = (*IntPointerSliceCmd)(nil)
+
+func NewIntPointerSliceCmd(ctx context.Context, args ...interface{}) *IntPointerSliceCmd {
+	return &IntPointerSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *IntPointerSliceCmd) SetVal(val []*int64) {
+	cmd.val = val
+}
+
+func (cmd *IntPointerSliceCmd) Val() []*int64 {
+	return cmd.val
+}
+
+func (cmd *IntPointerSliceCmd) Result() ([]*int64, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *IntPointerSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *IntPointerSliceCmd) readReply(rd *proto.Reader) error {
+	typ, err := rd.PeekReplyType()
+	if err != nil {
+		return err
+	}
+	if typ != proto.RespArray {
+		// Legacy paths, e.g. ".a", return a single integer.
+		n, err := rd.ReadInt()
+		if err != nil {
+			return err
+		}
+		cmd.val = []*int64{&n}
+		return nil
+	}
+
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	cmd.val = make([]*int64, n)
+	for i := 0; i < len(cmd.val); i++ {
+		switch n, err := rd.ReadInt(); {
+		case err == Nil:
+			cmd.val[i] = nil
+		case err != nil:
+			return err
+		default:
+			cmd.val[i] = &n
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// JSONTypeCmd is a reply of JSON.TYPE. The nested RESP3 reply is flattened,
+// so the reply is the same for both protocols.
+type JSONTypeCmd struct {
+	baseCmd
+
+	val []string
+}
+
+var _ Cmder = (*JSONTypeCmd)(nil)
+
+func NewJSONTypeCmd(ctx context.Context, args ...interface{}) *JSONTypeCmd {
+	return &JSONTypeCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *JSONTypeCmd) SetVal(val []string) {
+	cmd.val = val
+}
+
+func (cmd *JSONTypeCmd) Val() []string {
+	return cmd.val
+}
+
+func (cmd *JSONTypeCmd) Result() ([]string, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *JSONTypeCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *JSONTypeCmd) readReply(rd *proto.Reader) error {
+	cmd.val = cmd.val[:0]
+	return cmd.readTypes(rd)
+}
+
+func (cmd *JSONTypeCmd) readTypes(rd *proto.Reader) error {
+	typ, err := rd.PeekReplyType()
+	if err != nil {
+		return err
+	}
+	if typ != proto.RespArray {
+		s, err := rd.ReadString()
+		if err != nil {
+			return err
+		}
+		cmd.val = append(cmd.val, s)
+		return nil
+	}
+
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := cmd.readTypes(rd); err != nil && err != Nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// JSONArrAppend appends the JSON values into the array at path after the last element in it.
+// For more information - https://redis.io/commands/json.arrappend/
+func (c cmdable) JSONArrAppend(ctx context.Context, key, path string, values ...interface{}) *IntPointerSliceCmd {
+	args := []interface{}{"JSON.ARRAPPEND", key, path}
+	args, err := appendJSONArgs(args, values)
+	cmd := NewIntPointerSliceCmd(ctx, args...)
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONArrIndex searches for the first occurrence of the JSON value in the array at path.
+// For more information - https://redis.io/commands/json.arrindex/
+func (c cmdable) JSONArrIndex(ctx context.Context, key, path string, value interface{}) *IntPointerSliceCmd {
+	return c.JSONArrIndexWithArgs(ctx, key, path, nil, value)
+}
+
+// JSONArrIndexWithArgs searches for the first occurrence of the JSON value in the array at path
+// within the range of the array specified by the options.
+// For more information - https://redis.io/commands/json.arrindex/
+func (c cmdable) JSONArrIndexWithArgs(
+	ctx context.Context, key, path string, options *JSONArrIndexArgs, value interface{},
+) *IntPointerSliceCmd {
+	args := []interface{}{"JSON.ARRINDEX", key, path}
+	args, err := appendJSONArgs(args, []interface{}{value})
+	if options != nil {
+		args = append(args, options.Start)
+		if options.Stop != nil {
+			args = append(args, *options.Stop)
+		}
+	}
+	cmd := NewIntPointerSliceCmd(ctx, args...)
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONArrInsert inserts the JSON values into the array at path before the index.
+// For more information - https://redis.io/commands/json.arrinsert/
+func (c cmdable) JSONArrInsert(ctx context.Context, key, path string, index int64, values ...interface{}) *IntPointerSliceCmd {
+	args := []interface{}{"JSON.ARRINSERT", key, path, index}
+	args, err := appendJSONArgs(args, values)
+	cmd := NewIntPointerSliceCmd(ctx, args...)
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONArrLen reports the length of the array at path.
+// For more information - https://redis.io/commands/json.arrlen/
+func (c cmdable) JSONArrLen(ctx context.Context, key, path string) *IntPointerSliceCmd {
+	args := []interface{}{"JSON.ARRLEN", key, path}
+	cmd := NewIntPointerSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONArrPop removes and returns the element at the index in the array at path.
+// The index -1 is the last element.
+// For more information - https://redis.io/commands/json.arrpop/
+func (c cmdable) JSONArrPop(ctx context.Context, key, path string, index int) *JSONSliceCmd {
+	args := []interface{}{"JSON.ARRPOP", key, path, index}
+	cmd := NewJSONSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONArrTrim trims the array at path, so it contains only the inclusive range
+// of elements from start to stop.
+// For more information - https://redis.io/commands/json.arrtrim/
+func (c cmdable) JSONArrTrim(ctx context.Context, key, path string, start, stop int) *IntPointerSliceCmd {
+	args := []interface{}{"JSON.ARRTRIM", key, path, start, stop}
+	cmd := NewIntPointerSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONClear clears the containers and sets the numbers to 0 at path.
+// It returns the number of the cleared values.
+// For more information - https://redis.io/commands/json.clear/
+func (c cmdable) JSONClear(ctx context.Context, key, path string) *IntCmd {
+	args := []interface{}{"JSON.CLEAR", key, path}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONDel deletes the values at path. It returns the number of the deleted values.
+// For more information - https://redis.io/commands/json.del/
+func (c cmdable) JSONDel(ctx context.Context, key, path string) *IntCmd {
+	args := []interface{}{"JSON.DEL", key, path}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONGet returns the values at the paths as JSON. Multiple paths return
+// a JSON object keyed by the paths.
+// For more information - https://redis.io/commands/json.get/
+func (c cmdable) JSONGet(ctx context.Context, key string, paths ...string) *JSONCmd {
+	return c.JSONGetWithArgs(ctx, key, nil, paths...)
+}
+
+// JSONGetWithArgs returns the values at the paths as JSON formatted according to the options.
+// For more information - https://redis.io/commands/json.get/
+func (c cmdable) JSONGetWithArgs(ctx context.Context, key string, options *JSONGetArgs, paths ...string) *JSONCmd {
+	args := []interface{}{"JSON.GET", key}
+	if options != nil {
+		if options.Indent != "" {
+			args = append(args, "INDENT", options.Indent)
+		}
+		if options.Newline != "" {
+			args = append(args, "NEWLINE", options.Newline)
+		}
+		if options.Space != "" {
+			args = append(args, "SPACE", options.Space)
+		}
+	}
+	for _, path := range paths {
+		args = append(args, path)
+	}
+	cmd := NewJSONCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONMerge merges the JSON value into the value at path following RFC 7396.
+// For more information - https://redis.io/commands/json.merge/
+func (c cmdable) JSONMerge(ctx context.Context, key, path string, value interface{}) *StatusCmd {
+	args := []interface{}{"JSON.MERGE", key, path}
+	args, err := appendJSONArgs(args, []interface{}{value})
+	cmd := NewStatusCmd(ctx, args...)
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONMGet returns the values at path of the keys as JSON.
+// The missing keys and paths are returned as nil.
+// For more information - https://redis.io/commands/json.mget/
+func (c cmdable) JSONMGet(ctx context.Context, path string, keys ...string) *JSONSliceCmd {
+	args := make([]interface{}, 1, len(keys)+2)
+	args[0] = "JSON.MGET"
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	args = append(args, path)
+	cmd := NewJSONSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONMSet sets the JSON values of the docs atomically.
+// For more information - https://redis.io/commands/json.mset/
+func (c cmdable) JSONMSet(ctx context.Context, docs ...JSONSetParams) *StatusCmd {
+	args := make([]interface{}, 1, 3*len(docs)+1)
+	args[0] = "JSON.MSET"
+	var err error
+	for _, doc := range docs {
+		args = append(args, doc.Key, doc.Path)
+		if args, err = appendJSONArgs(args, []interface{}{doc.Value}); err != nil {
+			break
+		}
+	}
+	cmd := NewStatusCmd(ctx, args...)
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONNumIncrBy increments the numbers at path by the value.
+// It returns the new values as JSON.
+// For more information - https://redis.io/commands/json.numincrby/
+func (c cmdable) JSONNumIncrBy(ctx context.Context, key, path string, value float64) *JSONCmd {
+	args := []interface{}{"JSON.NUMINCRBY", key, path, value}
+	cmd := NewJSONCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONObjKeys returns the keys of the objects at path.
+// For more information - https://redis.io/commands/json.objkeys/
+func (c cmdable) JSONObjKeys(ctx context.Context, key, path string) *SliceCmd {
+	args := []interface{}{"JSON.OBJKEYS", key, path}
+	cmd := NewSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONObjLen reports the number of keys of the objects at path.
+// For more information - https://redis.io/commands/json.objlen/
+func (c cmdable) JSONObjLen(ctx context.Context, key, path string) *IntPointerSliceCmd {
+	args := []interface{}{"JSON.OBJLEN", key, path}
+	cmd := NewIntPointerSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONSet sets the JSON value at path. Values other than strings and byte slices
+// are encoded using encoding/json.
+// For more information - https://redis.io/commands/json.set/
+func (c cmdable) JSONSet(ctx context.Context, key, path string, value interface{}) *StatusCmd {
+	return c.JSONSetMode(ctx, key, path, value, "")
+}
+
+// JSONSetMode sets the JSON value at path. The mode "NX" sets the value only if it
+// does not exist, "XX" only if it already exists. redis.Nil is returned if the value
+// was not set.
+// For more information - https://redis.io/commands/json.set/
+func (c cmdable) JSONSetMode(ctx context.Context, key, path string, value interface{}, mode string) *StatusCmd {
+	args := []interface{}{"JSON.SET", key, path}
+	args, err := appendJSONArgs(args, []interface{}{value})
+	if mode != "" {
+		switch strings.ToUpper(mode) {
+		case "NX", "XX":
+			args = append(args, mode)
+		default:
+			err = fmt.Errorf("redis: JSON.SET unknown mode %q", mode)
+		}
+	}
+	cmd := NewStatusCmd(ctx, args...)
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONStrAppend appends the JSON string, e.g. `"foo"`, to the strings at path.
+// It returns the new lengths of the strings.
+// For more information - https://redis.io/commands/json.strappend/
+func (c cmdable) JSONStrAppend(ctx context.Context, key, path, value string) *IntPointerSliceCmd {
+	args := []interface{}{"JSON.STRAPPEND", key, path, value}
+	cmd := NewIntPointerSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONStrLen reports the lengths of the strings at path.
+// For more information - https://redis.io/commands/json.strlen/
+func (c cmdable) JSONStrLen(ctx context.Context, key, path string) *IntPointerSliceCmd {
+	args := []interface{}{"JSON.STRLEN", key, path}
+	cmd := NewIntPointerSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// JSONType reports the types of the values at path.
+// For more information - https://redis.io/commands/json.type/
+func (c cmdable) JSONType(ctx context.Context, key, path string) *JSONTypeCmd {
+	args := []interface{}{"JSON.TYPE", key, path}
+	cmd := NewJSONTypeCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
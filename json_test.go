@@ -0,0 +1,199 @@
+package redis_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type jsonUser struct {
+	Name string   `json:"name"`
+	Age  int      `json:"age"`
+	Tags []string `json:"tags"`
+}
+
+var _ = Describe("JSON commands", Label("json"), func() {
+	ctx := context.TODO()
+
+	for _, protocol := range []int{2, 3} {
+		protocol := protocol
+
+		Describe(fmt.Sprintf("RESP%d", protocol), func() {
+			var client *redis.Client
+
+			BeforeEach(func() {
+				client = redis.NewClient(&redis.Options{Addr: ":6379", Protocol: protocol})
+				Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				Expect(client.Close()).NotTo(HaveOccurred())
+			})
+
+			It("should JSONSet and JSONGet", Label("json.set", "json.get"), func() {
+				user := jsonUser{Name: "john", Age: 30, Tags: []string{"a"}}
+				Expect(client.JSONSet(ctx, "user", "$", user).Err()).NotTo(HaveOccurred())
+
+				var got jsonUser
+				Expect(client.JSONGet(ctx, "user").Scan(&got)).NotTo(HaveOccurred())
+				Expect(got).To(Equal(user))
+
+				var names []string
+				Expect(client.JSONGet(ctx, "user", "$.name").Scan(&names)).NotTo(HaveOccurred())
+				Expect(names).To(Equal([]string{"john"}))
+
+				res, err := client.JSONGet(ctx, "user", "$.name", "$.age").Expanded()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(map[string]interface{}{
+					"$.name": []interface{}{"john"},
+					"$.age":  []interface{}{float64(30)},
+				}))
+
+				err = client.JSONGet(ctx, "missing").Err()
+				Expect(err).To(Equal(redis.Nil))
+			})
+
+			It("should JSONGetWithArgs", Label("json.get"), func() {
+				Expect(client.JSONSet(ctx, "doc", "$", `{"a":1}`).Err()).NotTo(HaveOccurred())
+
+				res, err := client.JSONGetWithArgs(ctx, "doc", &redis.JSONGetArgs{
+					Indent:  "  ",
+					Newline: "\n",
+					Space:   " ",
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal("{\n  \"a\": 1\n}"))
+			})
+
+			It("should JSONSetMode", Label("json.set"), func() {
+				err := client.JSONSetMode(ctx, "doc", "$", `{"a":1}`, "XX").Err()
+				Expect(err).To(Equal(redis.Nil))
+
+				Expect(client.JSONSetMode(ctx, "doc", "$", `{"a":1}`, "NX").Err()).NotTo(HaveOccurred())
+				err = client.JSONSetMode(ctx, "doc", "$", `{"a":2}`, "NX").Err()
+				Expect(err).To(Equal(redis.Nil))
+
+				Expect(client.JSONGet(ctx, "doc", "$.a").Val()).To(Equal("[1]"))
+			})
+
+			It("should JSONMSet and JSONMGet", Label("json.mset", "json.mget"), func() {
+				err := client.JSONMSet(ctx,
+					redis.JSONSetParams{Key: "u1", Path: "$", Value: jsonUser{Name: "a"}},
+					redis.JSONSetParams{Key: "u2", Path: "$", Value: jsonUser{Name: "b"}},
+				).Err()
+				Expect(err).NotTo(HaveOccurred())
+
+				cmd := client.JSONMGet(ctx, "$", "u1", "missing", "u2")
+				Expect(cmd.Err()).NotTo(HaveOccurred())
+				Expect(cmd.Val()).To(HaveLen(3))
+				Expect(cmd.Val()[1]).To(BeNil())
+
+				var users [][]jsonUser
+				Expect(cmd.Scan(&users)).NotTo(HaveOccurred())
+				Expect(users).To(HaveLen(3))
+				Expect(users[0][0].Name).To(Equal("a"))
+				Expect(users[1]).To(BeNil())
+				Expect(users[2][0].Name).To(Equal("b"))
+			})
+
+			It("should JSONMerge", Label("json.merge"), func() {
+				Expect(client.JSONSet(ctx, "doc", "$", `{"a":1,"b":2}`).Err()).NotTo(HaveOccurred())
+				Expect(client.JSONMerge(ctx, "doc", "$", `{"b":null,"c":3}`).Err()).NotTo(HaveOccurred())
+				Expect(client.JSONGet(ctx, "doc").Val()).To(Equal(`{"a":1,"c":3}`))
+			})
+
+			It("should JSONDel and JSONClear", Label("json.del", "json.clear"), func() {
+				Expect(client.JSONSet(ctx, "doc", "$", `{"a":[1,2],"b":3}`).Err()).NotTo(HaveOccurred())
+
+				Expect(client.JSONClear(ctx, "doc", "$.a").Val()).To(Equal(int64(1)))
+				Expect(client.JSONDel(ctx, "doc", "$.b").Val()).To(Equal(int64(1)))
+				Expect(client.JSONGet(ctx, "doc").Val()).To(Equal(`{"a":[]}`))
+			})
+
+			It("should JSONNumIncrBy", Label("json.numincrby"), func() {
+				Expect(client.JSONSet(ctx, "doc", "$", `{"a":1}`).Err()).NotTo(HaveOccurred())
+
+				var vals []float64
+				Expect(client.JSONNumIncrBy(ctx, "doc", "$.a", 2).Scan(&vals)).NotTo(HaveOccurred())
+				Expect(vals).To(Equal([]float64{3}))
+			})
+
+			It("should JSONStrAppend and JSONStrLen", Label("json.strappend", "json.strlen"), func() {
+				Expect(client.JSONSet(ctx, "doc", "$", `{"a":"foo","b":1}`).Err()).NotTo(HaveOccurred())
+
+				res, err := client.JSONStrAppend(ctx, "doc", "$..*", `"bar"`).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(HaveLen(2))
+				Expect(*res[0]).To(Equal(int64(6)))
+				Expect(res[1]).To(BeNil())
+
+				res, err = client.JSONStrLen(ctx, "doc", "$.a").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res[0]).To(Equal(int64(6)))
+			})
+
+			It("should JSONArr commands", Label("json.arr"), func() {
+				Expect(client.JSONSet(ctx, "doc", "$", `{"a":[1]}`).Err()).NotTo(HaveOccurred())
+
+				res, err := client.JSONArrAppend(ctx, "doc", "$.a", 2, 3).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res[0]).To(Equal(int64(3)))
+
+				res, err = client.JSONArrInsert(ctx, "doc", "$.a", 0, 0).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res[0]).To(Equal(int64(4)))
+
+				res, err = client.JSONArrIndex(ctx, "doc", "$.a", 2).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res[0]).To(Equal(int64(2)))
+
+				stop := 1
+				res, err = client.JSONArrIndexWithArgs(ctx, "doc", "$.a", &redis.JSONArrIndexArgs{Start: 0, Stop: &stop}, 2).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res[0]).To(Equal(int64(-1)))
+
+				popped, err := client.JSONArrPop(ctx, "doc", "$.a", -1).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(popped).To(Equal([]interface{}{"3"}))
+
+				popped, err = client.JSONArrPop(ctx, "doc", ".a", -1).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(popped).To(Equal([]interface{}{"2"}))
+				Expect(client.JSONArrAppend(ctx, "doc", "$.a", 2).Err()).NotTo(HaveOccurred())
+
+				res, err = client.JSONArrTrim(ctx, "doc", "$.a", 1, 1).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res[0]).To(Equal(int64(1)))
+
+				res, err = client.JSONArrLen(ctx, "doc", "$.a").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res[0]).To(Equal(int64(1)))
+				Expect(client.JSONGet(ctx, "doc").Val()).To(Equal(`{"a":[1]}`))
+			})
+
+			It("should JSONObjKeys and JSONObjLen", Label("json.objkeys", "json.objlen"), func() {
+				Expect(client.JSONSet(ctx, "doc", "$", `{"a":1,"b":2}`).Err()).NotTo(HaveOccurred())
+
+				keys, err := client.JSONObjKeys(ctx, "doc", "$").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(keys).To(Equal([]interface{}{[]interface{}{"a", "b"}}))
+
+				res, err := client.JSONObjLen(ctx, "doc", "$").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res[0]).To(Equal(int64(2)))
+			})
+
+			It("should JSONType", Label("json.type"), func() {
+				Expect(client.JSONSet(ctx, "doc", "$", `{"a":1,"b":[]}`).Err()).NotTo(HaveOccurred())
+
+				types, err := client.JSONType(ctx, "doc", "$.*").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(types).To(Equal([]string{"integer", "array"}))
+			})
+		})
+	}
+})
@@ -0,0 +1,405 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+var (
+	// ErrLockNotObtained is returned by Locker.Obtain when the lock is held by another owner.
+	ErrLockNotObtained = errors.New("redis: lock not obtained")
+	// ErrLockNotHeld is returned when the lock has expired or is held by another owner.
+	ErrLockNotHeld = errors.New("redis: lock not held")
+)
+
+// The lock key holds the token of the owner. The fencing counter is incremented
+// every time the lock is obtained.
+var (
+	obtainLockScript = NewScript(`
+if redis.call("set", KEYS[1], ARGV[1], "nx", "px", ARGV[2]) then
+	return redis.call("incr", KEYS[2])
+end
+return false
+`)
+	refreshLockScript = NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0
+`)
+	releaseLockScript = NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+	raiseFencingScript = NewScript(`
+local n = tonumber(redis.call("get", KEYS[1]) or "0")
+if n < tonumber(ARGV[1]) then
+	redis.call("set", KEYS[1], ARGV[1])
+end
+return 1
+`)
+)
+
+// LockOptions are the options of Locker.Obtain.
+type LockOptions struct {
+	// Token identifies the owner of the lock.
+	// Default is a random 128-bit hex string.
+	Token string
+
+	// Maximum number of retries before giving up.
+	// Default is to not retry; -1 retries until the context is done.
+	MaxRetries int
+	// Minimum backoff between each retry.
+	// Default is 8 milliseconds.
+	MinRetryBackoff time.Duration
+	// Maximum backoff between each retry.
+	// Default is 512 milliseconds.
+	MaxRetryBackoff time.Duration
+
+	// AutoRenew extends the lease in the background every third of the TTL
+	// until the lock is released or lost, see Lock.Lost.
+	AutoRenew bool
+
+	// DriftFactor is the clock drift of the instances relative to the TTL
+	// that is subtracted from the validity of the lock.
+	// Default is 0.01.
+	DriftFactor float64
+}
+
+func (opt *LockOptions) init() {
+	if opt.MinRetryBackoff == 0 {
+		opt.MinRetryBackoff = 8 * time.Millisecond
+	}
+	if opt.MaxRetryBackoff == 0 {
+		opt.MaxRetryBackoff = 512 * time.Millisecond
+	}
+	if opt.DriftFactor == 0 {
+		opt.DriftFactor = 0.01
+	}
+}
+
+// Locker obtains distributed locks. With a single instance the lock is a key
+// set with SET NX PX. With several independent instances Locker implements
+// the Redlock algorithm: the lock is obtained when it is set on the majority
+// of the instances within its TTL.
+//
+// Every obtained lock has a fencing token, which is incremented on each
+// acquisition and can be used to reject the writes of the previous owners.
+// The counter is stored in the "<key>:fencing" key, so with ClusterClient
+// the lock key must have a hash tag, e.g. "{order:1}".
+type Locker struct {
+	clients []Scripter
+	quorum  int
+}
+
+// NewLocker returns a Locker that obtains the locks on the independent instances,
+// e.g. several Clients connected to different servers.
+func NewLocker(clients ...Scripter) *Locker {
+	if len(clients) == 0 {
+		panic("redis: NewLocker requires at least one client")
+	}
+	return &Locker{
+		clients: clients,
+		quorum:  len(clients)/2 + 1,
+	}
+}
+
+// Obtain obtains the lock for the ttl, which must be at least a millisecond.
+// It returns ErrLockNotObtained if the lock is held by another owner after the retries.
+func (l *Locker) Obtain(ctx context.Context, key string, ttl time.Duration, opt *LockOptions) (*Lock, error) {
+	if ttl < time.Millisecond {
+		return nil, fmt.Errorf("redis: invalid lock ttl %s", ttl)
+	}
+	if opt == nil {
+		opt = new(LockOptions)
+	}
+	o := *opt
+	o.init()
+
+	token := o.Token
+	if token == "" {
+		var err error
+		if token, err = randomToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	lock := &Lock{
+		locker: l,
+		key:    key,
+		token:  token,
+		ttl:    ttl,
+		drift:  o.DriftFactor,
+		lost:   make(chan struct{}),
+	}
+
+	for attempt := 0; o.MaxRetries < 0 || attempt <= o.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := internal.Sleep(ctx, internal.RetryBackoff(attempt, o.MinRetryBackoff, o.MaxRetryBackoff)); err != nil {
+				return nil, err
+			}
+		}
+
+		ok, err := lock.obtain(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if o.AutoRenew {
+				lock.startRenewal()
+			}
+			return lock, nil
+		}
+	}
+	return nil, ErrLockNotObtained
+}
+
+// run runs the script on all instances concurrently.
+func (l *Locker) run(
+	ctx context.Context, script *Script, keys []string, args ...interface{},
+) []*Cmd {
+	cmds := make([]*Cmd, len(l.clients))
+	var wg sync.WaitGroup
+	for i, c := range l.clients {
+		wg.Add(1)
+		go func(i int, c Scripter) {
+			defer wg.Done()
+			cmds[i] = script.Run(ctx, c, keys, args...)
+		}(i, c)
+	}
+	wg.Wait()
+	return cmds
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+//------------------------------------------------------------------------------
+
+// Lock is a lock obtained by Locker.
+type Lock struct {
+	locker *Locker
+	key    string
+	token  string
+	ttl    time.Duration
+	drift  float64
+
+	mu         sync.Mutex
+	fencing    int64
+	validUntil time.Time
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Key returns the lock key.
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Token returns the token of the owner that is stored in the lock key.
+func (l *Lock) Token() string {
+	return l.token
+}
+
+// FencingToken returns the fencing token, which is greater than the tokens
+// of all the previous owners of the lock.
+func (l *Lock) FencingToken() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fencing
+}
+
+// ValidUntil returns the time until the lock is held, taking the time spent
+// obtaining the lock and the clock drift into account.
+func (l *Lock) ValidUntil() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.validUntil
+}
+
+// Lost returns a channel that is closed when the automatic renewal
+// fails to extend the lease, see LockOptions.AutoRenew.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *Lock) fencingKey() string {
+	return l.key + ":fencing"
+}
+
+// validity returns the validity of the lease that started at start,
+// or 0 if the lease has expired.
+func (l *Lock) validity(start time.Time, ttl time.Duration) time.Duration {
+	drift := time.Duration(float64(ttl)*l.drift) + 2*time.Millisecond
+	if v := ttl - time.Since(start) - drift; v > 0 {
+		return v
+	}
+	return 0
+}
+
+func (l *Lock) obtain(ctx context.Context) (bool, error) {
+	start := time.Now()
+	cmds := l.locker.run(ctx, obtainLockScript,
+		[]string{l.key, l.fencingKey()}, l.token, l.ttl.Milliseconds())
+
+	var (
+		n       int
+		fencing int64
+		errs    int
+		lastErr error
+	)
+	for _, cmd := range cmds {
+		token, err := cmd.Int64()
+		if err != nil {
+			if err != Nil {
+				errs++
+				lastErr = err
+			}
+			continue
+		}
+		n++
+		if token > fencing {
+			fencing = token
+		}
+	}
+
+	validity := l.validity(start, l.ttl)
+	if n < l.locker.quorum || validity == 0 {
+		if n > 0 {
+			// Don't hold the minority of the instances until the keys expire.
+			l.locker.run(context.Background(), releaseLockScript, []string{l.key}, l.token)
+		}
+		if errs == len(cmds) {
+			return false, lastErr
+		}
+		return false, nil
+	}
+
+	if len(cmds) > 1 {
+		// The next owner gets a token greater than this one from any instance
+		// of the quorum that intersects with the current one.
+		cmds = l.locker.run(ctx, raiseFencingScript, []string{l.fencingKey()}, fencing)
+		if n, err := l.locker.countOK(cmds); n < l.locker.quorum {
+			l.locker.run(context.Background(), releaseLockScript, []string{l.key}, l.token)
+			return false, err
+		}
+	}
+
+	l.mu.Lock()
+	l.fencing = fencing
+	l.validUntil = start.Add(validity)
+	l.mu.Unlock()
+	return true, nil
+}
+
+// Refresh extends the lease of the lock to the ttl.
+// It returns ErrLockNotHeld if the lock has expired or is held by another owner.
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	start := time.Now()
+	cmds := l.locker.run(ctx, refreshLockScript, []string{l.key}, l.token, ttl.Milliseconds())
+
+	n, err := l.locker.countOK(cmds)
+	validity := l.validity(start, ttl)
+	if n < l.locker.quorum || validity == 0 {
+		if err != nil {
+			return err
+		}
+		return ErrLockNotHeld
+	}
+
+	l.mu.Lock()
+	l.validUntil = start.Add(validity)
+	l.mu.Unlock()
+	return nil
+}
+
+// Release releases the lock and stops the automatic renewal.
+// It returns ErrLockNotHeld if the lock has expired or is held by another owner.
+func (l *Lock) Release(ctx context.Context) error {
+	// The renewal is stopped once, even if Release is called concurrently.
+	// The mutex is not held while waiting, because the renewal uses it too.
+	l.mu.Lock()
+	stop, done := l.stop, l.done
+	l.stop, l.done = nil, nil
+	l.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	cmds := l.locker.run(ctx, releaseLockScript, []string{l.key}, l.token)
+	n, err := l.locker.countOK(cmds)
+	if n < l.locker.quorum {
+		if err != nil {
+			return err
+		}
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// countOK returns the number of the scripts that returned 1 and the last error.
+func (l *Locker) countOK(cmds []*Cmd) (int, error) {
+	var n int
+	var lastErr error
+	for _, cmd := range cmds {
+		v, err := cmd.Int64()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if v == 1 {
+			n++
+		}
+	}
+	return n, lastErr
+}
+
+func (l *Lock) startRenewal() {
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	go l.renew(l.stop, l.done)
+}
+
+func (l *Lock) renew(stop, done chan struct{}) {
+	defer close(done)
+
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := l.Refresh(ctx, l.ttl)
+		cancel()
+		if err == ErrLockNotHeld || (err != nil && time.Now().After(l.ValidUntil())) {
+			internal.Logger.Printf(context.Background(), "redis: lock %q is lost: %s", l.key, err)
+			l.lostOnce.Do(func() { close(l.lost) })
+			return
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package redis_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("Locker", func() {
+	ctx := context.Background()
+	var client *redis.Client
+	var locker *redis.Locker
+
+	BeforeEach(func() {
+		client = redis.NewClient(redisOptions())
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+		locker = redis.NewLocker(client)
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should obtain and release the lock", func() {
+		lock, err := locker.Obtain(ctx, "lock", time.Minute, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lock.Key()).To(Equal("lock"))
+		Expect(lock.Token()).To(HaveLen(32))
+		Expect(lock.FencingToken()).To(Equal(int64(1)))
+		Expect(lock.ValidUntil()).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		Expect(client.Get(ctx, "lock").Val()).To(Equal(lock.Token()))
+
+		_, err = locker.Obtain(ctx, "lock", time.Minute, nil)
+		Expect(err).To(Equal(redis.ErrLockNotObtained))
+
+		Expect(lock.Release(ctx)).NotTo(HaveOccurred())
+		Expect(client.Exists(ctx, "lock").Val()).To(Equal(int64(0)))
+		Expect(lock.Release(ctx)).To(Equal(redis.ErrLockNotHeld))
+
+		lock, err = locker.Obtain(ctx, "lock", time.Minute, &redis.LockOptions{Token: "owner"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lock.Token()).To(Equal("owner"))
+		Expect(lock.FencingToken()).To(Equal(int64(2)))
+	})
+
+	It("should reject an invalid ttl", func() {
+		_, err := locker.Obtain(ctx, "lock", time.Microsecond, &redis.LockOptions{AutoRenew: true})
+		Expect(err).To(MatchError("redis: invalid lock ttl 1µs"))
+		Expect(client.Exists(ctx, "lock").Val()).To(Equal(int64(0)))
+	})
+
+	It("should refresh the lock", func() {
+		lock, err := locker.Obtain(ctx, "lock", time.Second, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(lock.Refresh(ctx, time.Minute)).NotTo(HaveOccurred())
+		Expect(client.PTTL(ctx, "lock").Val()).To(BeNumerically(">", time.Second))
+
+		Expect(client.Set(ctx, "lock", "other", 0).Err()).NotTo(HaveOccurred())
+		Expect(lock.Refresh(ctx, time.Minute)).To(Equal(redis.ErrLockNotHeld))
+		Expect(lock.Release(ctx)).To(Equal(redis.ErrLockNotHeld))
+		Expect(client.Get(ctx, "lock").Val()).To(Equal("other"))
+	})
+
+	It("should retry until the lock is released", func() {
+		lock, err := locker.Obtain(ctx, "lock", 200*time.Millisecond, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		next, err := locker.Obtain(ctx, "lock", time.Minute, &redis.LockOptions{
+			MaxRetries:      -1,
+			MaxRetryBackoff: 50 * time.Millisecond,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next.FencingToken()).To(BeNumerically(">", lock.FencingToken()))
+
+		ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, err = locker.Obtain(ctx, "lock", time.Minute, &redis.LockOptions{MaxRetries: -1})
+		Expect(err).To(HaveOccurred())
+		Expect(err).NotTo(Equal(redis.ErrLockNotObtained))
+	})
+
+	It("should renew the lease", func() {
+		lock, err := locker.Obtain(ctx, "lock", 300*time.Millisecond, &redis.LockOptions{
+			AutoRenew: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		time.Sleep(time.Second)
+		Expect(client.Get(ctx, "lock").Val()).To(Equal(lock.Token()))
+		Expect(lock.Lost()).NotTo(BeClosed())
+
+		Expect(client.Del(ctx, "lock").Err()).NotTo(HaveOccurred())
+		Eventually(lock.Lost()).Should(BeClosed())
+		Expect(lock.Release(ctx)).To(Equal(redis.ErrLockNotHeld))
+	})
+
+	It("should stop the renewal once", func() {
+		lock, err := locker.Obtain(ctx, "lock", 300*time.Millisecond, &redis.LockOptions{
+			AutoRenew: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				errs <- lock.Release(ctx)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		var released int
+		for err := range errs {
+			if err == nil {
+				released++
+			} else {
+				Expect(err).To(Equal(redis.ErrLockNotHeld))
+			}
+		}
+		Expect(released).To(Equal(1))
+		Expect(lock.Lost()).NotTo(BeClosed())
+	})
+
+	Describe("Redlock", func() {
+		var clients []*redis.Client
+
+		BeforeEach(func() {
+			// Databases of the same server stand for the independent instances.
+			clients = nil
+			var scripters []redis.Scripter
+			for _, db := range []int{13, 14, 15} {
+				opt := redisOptions()
+				opt.DB = db
+				c := redis.NewClient(opt)
+				Expect(c.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+				clients = append(clients, c)
+				scripters = append(scripters, c)
+			}
+			locker = redis.NewLocker(scripters...)
+		})
+
+		AfterEach(func() {
+			for _, c := range clients {
+				Expect(c.Close()).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should obtain the lock on the majority", func() {
+			Expect(clients[0].Set(ctx, "lock", "other", 0).Err()).NotTo(HaveOccurred())
+
+			lock, err := locker.Obtain(ctx, "lock", time.Minute, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients[1].Get(ctx, "lock").Val()).To(Equal(lock.Token()))
+			Expect(clients[2].Get(ctx, "lock").Val()).To(Equal(lock.Token()))
+
+			Expect(lock.Release(ctx)).NotTo(HaveOccurred())
+			Expect(clients[0].Get(ctx, "lock").Val()).To(Equal("other"))
+		})
+
+		It("should not obtain the lock on the minority", func() {
+			Expect(clients[0].Set(ctx, "lock", "other", 0).Err()).NotTo(HaveOccurred())
+			Expect(clients[1].Set(ctx, "lock", "other", 0).Err()).NotTo(HaveOccurred())
+
+			_, err := locker.Obtain(ctx, "lock", time.Minute, nil)
+			Expect(err).To(Equal(redis.ErrLockNotObtained))
+			// The minority is released.
+			Expect(clients[2].Exists(ctx, "lock").Val()).To(Equal(int64(0)))
+		})
+
+		It("should keep the fencing tokens monotonic", func() {
+			Expect(clients[0].Set(ctx, "lock:fencing", 10, 0).Err()).NotTo(HaveOccurred())
+
+			lock, err := locker.Obtain(ctx, "lock", time.Minute, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lock.FencingToken()).To(Equal(int64(11)))
+			Expect(lock.Release(ctx)).NotTo(HaveOccurred())
+
+			// The quorum without the first instance still gets a greater token.
+			Expect(clients[0].Set(ctx, "lock", "other", 0).Err()).NotTo(HaveOccurred())
+			lock, err = locker.Obtain(ctx, "lock", time.Minute, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lock.FencingToken()).To(Equal(int64(12)))
+		})
+	})
+})
@@ -0,0 +1,360 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/pool"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// Commands that change the connection state and can't share a connection with other commands.
+var nonMultiplexableCmds = map[string]struct{}{
+	"auth":         {},
+	"client":       {},
+	"discard":      {},
+	"exec":         {},
+	"hello":        {},
+	"monitor":      {},
+	"multi":        {},
+	"psubscribe":   {},
+	"punsubscribe": {},
+	"quit":         {},
+	"reset":        {},
+	"select":       {},
+	"ssubscribe":   {},
+	"subscribe":    {},
+	"sunsubscribe": {},
+	"unsubscribe":  {},
+	"unwatch":      {},
+	"wait":         {},
+	"waitaof":      {},
+	"watch":        {},
+}
+
+// multiplexable reports whether the cmd can be sent on a multiplexed connection.
+// Blocking commands would delay the replies of the other commands.
+func multiplexable(cmd Cmder) bool {
+	if cmd.readTimeout() != nil {
+		return false
+	}
+	_, ok := nonMultiplexableCmds[cmd.Name()]
+	return !ok
+}
+
+func multiplexableCmds(cmds []Cmder) bool {
+	for _, cmd := range cmds {
+		if !multiplexable(cmd) {
+			return false
+		}
+	}
+	return true
+}
+
+// multiplexer sends the commands over a fixed number of connections
+// shared by all goroutines.
+type multiplexer struct {
+	newConn func(ctx context.Context) (*muxConn, error)
+
+	next  uint32 // atomic
+	slots []muxSlot
+}
+
+type muxSlot struct {
+	mu     sync.Mutex
+	cn     *muxConn
+	closed bool
+}
+
+func newMultiplexer(opt *Options, newConn func(ctx context.Context) (*muxConn, error)) *multiplexer {
+	return &multiplexer{
+		newConn: newConn,
+		slots:   make([]muxSlot, opt.MultiplexConns),
+	}
+}
+
+// conn returns the next connection, replacing the connection if it is broken.
+func (m *multiplexer) conn(ctx context.Context) (*muxConn, error) {
+	slot := &m.slots[int(atomic.AddUint32(&m.next, 1)-1)%len(m.slots)]
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+
+	if slot.closed {
+		return nil, pool.ErrClosed
+	}
+	if slot.cn != nil && !slot.cn.broken() {
+		return slot.cn, nil
+	}
+
+	cn, err := m.newConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slot.cn = cn
+	return cn, nil
+}
+
+func (m *multiplexer) close() {
+	for i := range m.slots {
+		slot := &m.slots[i]
+		slot.mu.Lock()
+		slot.closed = true
+		if slot.cn != nil {
+			slot.cn.fail(pool.ErrClosed)
+			slot.cn = nil
+		}
+		slot.mu.Unlock()
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type muxReq struct {
+	cmds  []Cmder
+	epoch uint64 // see clientCache.begin
+	err   error
+	done  chan struct{}
+
+	// abandoned is set when the context of the request is canceled before
+	// its replies are read. The replies are read and discarded.
+	abandoned bool
+	// reading is set when readLoop starts reading the replies into the cmds.
+	reading bool
+}
+
+// muxConn writes the commands in the order they are sent and a dedicated goroutine
+// reads the replies in the same order.
+type muxConn struct {
+	opt       *Options
+	cn        *pool.Conn
+	cache     *clientCache
+	closeConn func(cn *pool.Conn) error
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	inflight []*muxReq
+	err      error
+}
+
+func (c *baseClient) newMuxConn(ctx context.Context) (*muxConn, error) {
+	cn, err := c.newConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.setupConn(ctx, cn); err != nil {
+		_ = c.connPool.CloseConn(cn)
+		return nil, err
+	}
+
+	mc := &muxConn{
+		opt:       c.opt,
+		cn:        cn,
+		cache:     c.cache,
+		closeConn: c.connPool.CloseConn,
+	}
+	mc.cond = sync.NewCond(&mc.mu)
+	go mc.readLoop()
+	return mc, nil
+}
+
+func (mc *muxConn) broken() bool {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.err != nil
+}
+
+// do sends the cmds and waits for the replies or for the context to be done.
+// If the context is done before the replies are read, the request is abandoned and
+// readLoop discards its replies. If the replies are being read, the connection is
+// closed, because the replies are read into the cmds.
+func (mc *muxConn) do(ctx context.Context, cmds []Cmder) error {
+	req := &muxReq{
+		cmds: cmds,
+		done: make(chan struct{}),
+	}
+
+	mc.mu.Lock()
+	if mc.err != nil {
+		err := mc.err
+		mc.mu.Unlock()
+		setCmdsErr(cmds, err)
+		return err
+	}
+	if mc.cache != nil {
+		req.epoch = mc.cache.begin(cmds...)
+	}
+	if err := mc.cn.WithWriter(ctx, mc.opt.WriteTimeout, func(wr *proto.Writer) error {
+		return writeCmds(wr, cmds)
+	}); err != nil {
+		mc.mu.Unlock()
+		mc.fail(err)
+		setCmdsErr(cmds, err)
+		mc.storeCache(req)
+		return err
+	}
+	mc.inflight = append(mc.inflight, req)
+	mc.cond.Signal()
+	mc.mu.Unlock()
+
+	select {
+	case <-req.done:
+		return req.err
+	case <-ctx.Done():
+	}
+
+	err := ctx.Err()
+
+	mc.mu.Lock()
+	select {
+	case <-req.done:
+		mc.mu.Unlock()
+		return req.err
+	default:
+	}
+	if !req.reading && mc.err == nil {
+		req.abandoned = true
+		mc.mu.Unlock()
+		setCmdsErr(cmds, err)
+		mc.storeCache(req)
+		return err
+	}
+	mc.mu.Unlock()
+
+	mc.fail(err)
+	<-req.done
+	setCmdsErr(cmds, err)
+	return err
+}
+
+func (mc *muxConn) readLoop() {
+	for {
+		mc.mu.Lock()
+		for len(mc.inflight) == 0 && mc.err == nil {
+			mc.cond.Wait()
+		}
+		if mc.err != nil {
+			mc.mu.Unlock()
+			return
+		}
+		req := mc.inflight[0]
+		mc.inflight = mc.inflight[1:]
+		abandoned := req.abandoned
+		req.reading = !abandoned
+		mc.mu.Unlock()
+
+		if abandoned {
+			// The cmds belong to the caller again, so the replies are discarded.
+			err := mc.cn.WithReader(context.Background(), mc.opt.ReadTimeout, func(rd *proto.Reader) error {
+				return discardReplies(rd, len(req.cmds))
+			})
+			if err != nil {
+				mc.fail(err)
+			}
+			close(req.done)
+			continue
+		}
+
+		err := mc.cn.WithReader(context.Background(), mc.opt.ReadTimeout, func(rd *proto.Reader) error {
+			return pipelineReadCmds(rd, req.cmds)
+		})
+		if err != nil && !isRedisError(err) {
+			setCmdsErr(req.cmds, err)
+			mc.fail(err)
+		}
+		mc.storeCache(req)
+		req.err = err
+		close(req.done)
+	}
+}
+
+func discardReplies(rd *proto.Reader, n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := rd.ReadReply(); err != nil && !isRedisError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// fail marks the connection as broken, closes it and fails the in-flight commands.
+func (mc *muxConn) fail(err error) {
+	mc.mu.Lock()
+	if mc.err != nil {
+		mc.mu.Unlock()
+		return
+	}
+	mc.err = err
+	inflight := mc.inflight
+	mc.inflight = nil
+	mc.cond.Broadcast()
+	mc.mu.Unlock()
+
+	if err := mc.closeConn(mc.cn); err != nil {
+		internal.Logger.Printf(context.Background(), "redis: closing multiplexed connection failed: %s", err)
+	}
+	for _, req := range inflight {
+		if req.abandoned {
+			close(req.done)
+			continue
+		}
+		setCmdsErr(req.cmds, err)
+		mc.storeCache(req)
+		req.err = err
+		close(req.done)
+	}
+}
+
+// storeCache passes the replies of the req to the client-side cache.
+func (mc *muxConn) storeCache(req *muxReq) {
+	if mc.cache == nil {
+		return
+	}
+	for _, cmd := range req.cmds {
+		mc.cache.store(mc.cn, req.epoch, cmd, cmd.Err())
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func (c *baseClient) muxProcess(ctx context.Context, cmds []Cmder) error {
+	var lastErr error
+	for attempt := 0; attempt <= c.opt.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := internal.Sleep(ctx, c.retryBackoff(attempt)); err != nil {
+				setCmdsErr(cmds, err)
+				return err
+			}
+		}
+
+		err := c._muxProcess(ctx, cmds)
+		if err == nil || !shouldRetry(err, true) {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+func (c *baseClient) _muxProcess(ctx context.Context, cmds []Cmder) error {
+	if c.opt.Limiter != nil {
+		if err := c.opt.Limiter.Allow(); err != nil {
+			setCmdsErr(cmds, err)
+			return err
+		}
+	}
+
+	mc, err := c.mux.conn(ctx)
+	if err == nil {
+		err = mc.do(c.context(ctx), cmds)
+	} else {
+		setCmdsErr(cmds, err)
+	}
+
+	if c.opt.Limiter != nil {
+		c.opt.Limiter.ReportResult(err)
+	}
+	return err
+}
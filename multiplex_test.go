@@ -0,0 +1,123 @@
+package redis_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("Multiplexed connections", func() {
+	ctx := context.Background()
+	var client *redis.Client
+
+	poolGets := func() uint32 {
+		stats := client.PoolStats()
+		return stats.Hits + stats.Misses
+	}
+
+	BeforeEach(func() {
+		opt := redisOptions()
+		opt.MultiplexConns = 2
+		client = redis.NewClient(opt)
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("shares connections between goroutines", func() {
+		const n = 100
+
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				key := "key" + strconv.Itoa(i)
+				Expect(client.Set(ctx, key, i, 0).Err()).NotTo(HaveOccurred())
+				Expect(client.Get(ctx, key).Val()).To(Equal(strconv.Itoa(i)))
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(poolGets()).To(BeZero())
+	})
+
+	It("sends pipelines", func() {
+		cmds, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, "key", "hello", 0)
+			pipe.Get(ctx, "key")
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmds).To(HaveLen(2))
+		Expect(cmds[1].(*redis.StringCmd).Val()).To(Equal("hello"))
+
+		Expect(poolGets()).To(BeZero())
+	})
+
+	It("returns errors of each command", func() {
+		Expect(client.Set(ctx, "key", "hello", 0).Err()).NotTo(HaveOccurred())
+
+		cmds, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Incr(ctx, "key")
+			pipe.Get(ctx, "key")
+			return nil
+		})
+		Expect(err).To(MatchError("ERR value is not an integer or out of range"))
+		Expect(cmds[1].(*redis.StringCmd).Val()).To(Equal("hello"))
+
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+	})
+
+	It("sends blocking commands using the pool", func() {
+		err := client.BLPop(ctx, 100*time.Millisecond, "list").Err()
+		Expect(err).To(Equal(redis.Nil))
+		Expect(poolGets()).To(Equal(uint32(1)))
+	})
+
+	It("abandons the commands when the context is canceled", func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+		opt := redisOptions()
+		opt.MultiplexConns = 1
+		opt.ContextTimeoutEnabled = true
+		client = redis.NewClient(opt)
+
+		Expect(client.Set(ctx, "key", "hello", 0).Err()).NotTo(HaveOccurred())
+
+		slept := make(chan error, 1)
+		go func() {
+			slept <- client.Do(ctx, "debug", "sleep", "0.5").Err()
+		}()
+		time.Sleep(100 * time.Millisecond)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := client.Get(timeoutCtx, "key").Err()
+		Expect(err).To(Equal(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", 300*time.Millisecond))
+
+		Expect(<-slept).NotTo(HaveOccurred())
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+		Expect(poolGets()).To(BeZero())
+	})
+
+	It("returns an error after Close", func() {
+		Expect(client.Ping(ctx).Err()).NotTo(HaveOccurred())
+		Expect(client.Close()).NotTo(HaveOccurred())
+
+		Expect(client.Ping(ctx).Err()).To(MatchError("redis: client is closed"))
+
+		client = redis.NewClient(redisOptions())
+	})
+})
@@ -93,6 +93,16 @@ type Options struct {
 	// See https://redis.uptrace.dev/guide/go-redis-debugging.html#timeouts
 	ContextTimeoutEnabled bool
 
+	// AttributesEnabled enables collecting RESP3 attributes sent by the server along
+	// with the replies, see Cmd.Attributes. Attributes are discarded by default.
+	AttributesEnabled bool
+
+	// Codec marshals the command arguments that have no built-in encoding, e.g. structs,
+	// and unmarshals the values scanned by Cmd.Scan and StringCmd.Scan into such types.
+	// See JSONCodec and GobCodec. Without a codec, commands with such arguments fail
+	// with an error.
+	Codec Codec
+
 	// Type of connection pool.
 	// true for FIFO pool, false for LIFO pool.
 	// Note that FIFO has slightly higher overhead compared to LIFO,
@@ -134,6 +144,26 @@ type Options struct {
 	// Limiter interface used to implement circuit breaker or rate limiter.
 	Limiter Limiter
 
+	// Handlers of RESP3 push notifications keyed by the notification type,
+	// e.g. "invalidate". Push notifications of other types are discarded.
+	// Requires RESP3 protocol.
+	OnPush map[string]PushNotificationHandler
+
+	// Enables automatic pipelining of the commands issued concurrently.
+	// Blocking commands are never pipelined automatically.
+	AutoPipeline *AutoPipelineOptions
+
+	// Number of connections shared by all goroutines to send non-blocking commands
+	// and pipelines without taking a connection from the pool. Blocking commands,
+	// transactions, pubsub and commands that change the connection state,
+	// e.g. WATCH or SELECT, still use the pool.
+	// Default is 0, i.e. every command takes a connection from the pool.
+	MultiplexConns int
+
+	// Enables server-assisted client-side caching of read-only commands.
+	// Requires RESP3 protocol.
+	ClientCache *ClientCacheOptions
+
 	// Enables read only queries on slave/follower nodes.
 	readOnly bool
 }
@@ -49,8 +49,9 @@ type Pipeline struct {
 	cmdable
 	statefulCmdable
 
-	exec pipelineExecer
-	cmds []Cmder
+	exec  pipelineExecer
+	cmds  []Cmder
+	codec Codec
 }
 
 func (c *Pipeline) init() {
@@ -0,0 +1,50 @@
+package redis
+
+import "errors"
+
+var errPushRESP2 = errors.New("redis: push notifications require RESP3 protocol")
+
+// PushNotificationHandler handles RESP3 push notifications that the server sends
+// on regular connections out-of-band, e.g. client tracking invalidation messages.
+type PushNotificationHandler interface {
+	// HandlePushNotification is called with the whole notification, which starts with
+	// the notification type. The returned error is returned to the command whose
+	// reply is being read and the connection is closed.
+	HandlePushNotification(notification []interface{}) error
+}
+
+// PushNotificationHandlerFunc is an adapter to allow the use of ordinary functions
+// as push notification handlers.
+type PushNotificationHandlerFunc func(notification []interface{}) error
+
+func (fn PushNotificationHandlerFunc) HandlePushNotification(notification []interface{}) error {
+	return fn(notification)
+}
+
+// pushHandlers dispatches push notifications to the handlers registered for the notification type.
+type pushHandlers map[string][]PushNotificationHandler
+
+func newPushHandlers(onPush map[string]PushNotificationHandler) pushHandlers {
+	hs := make(pushHandlers, len(onPush))
+	for name, h := range onPush {
+		hs.add(name, h)
+	}
+	return hs
+}
+
+func (hs pushHandlers) add(name string, h PushNotificationHandler) {
+	hs[name] = append(hs[name], h)
+}
+
+func (hs pushHandlers) handle(push []interface{}) error {
+	if len(push) == 0 {
+		return nil
+	}
+	name, _ := push[0].(string)
+	for _, h := range hs[name] {
+		if err := h.HandlePushNotification(push); err != nil {
+			return err
+		}
+	}
+	return nil
+}
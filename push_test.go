@@ -0,0 +1,246 @@
+package redis_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// pushServer is a fake RESP3 server that sends the queued frames,
+// e.g. push notifications or attributes, before the next reply.
+type pushServer struct {
+	ln net.Listener
+
+	mu     sync.Mutex
+	pushes []string
+}
+
+func newPushServer() *pushServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+
+	s := &pushServer{ln: ln}
+	go s.serve()
+	return s
+}
+
+func (s *pushServer) Addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *pushServer) Close() error {
+	return s.ln.Close()
+}
+
+func (s *pushServer) Push(push string) {
+	s.mu.Lock()
+	s.pushes = append(s.pushes, push)
+	s.mu.Unlock()
+}
+
+func (s *pushServer) serve() {
+	for {
+		cn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.serveConn(cn)
+	}
+}
+
+func (s *pushServer) serveConn(cn net.Conn) {
+	defer cn.Close()
+
+	rd := bufio.NewReader(cn)
+	for {
+		args, err := readFakeCmd(rd)
+		if err != nil {
+			return
+		}
+
+		var reply string
+		switch strings.ToLower(args[0]) {
+		case "hello":
+			if _, err := cn.Write([]byte("%1\r\n$5\r\nproto\r\n:3\r\n")); err != nil {
+				return
+			}
+			continue
+		case "ping":
+			reply = "+PONG\r\n"
+		case "get":
+			reply = "$5\r\nhello\r\n"
+		default:
+			reply = fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+		}
+
+		s.mu.Lock()
+		pushes := s.pushes
+		s.pushes = nil
+		s.mu.Unlock()
+
+		if _, err := cn.Write([]byte(strings.Join(pushes, "") + reply)); err != nil {
+			return
+		}
+	}
+}
+
+func readFakeCmd(rd *bufio.Reader) ([]string, error) {
+	var n int
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Sscanf(line, "*%d\r\n", &n); err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		var size int
+		line, err := rd.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fmt.Sscanf(line, "$%d\r\n", &size); err != nil {
+			return nil, err
+		}
+		b := make([]byte, size+2)
+		if _, err := io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+		args[i] = string(b[:size])
+	}
+	return args, nil
+}
+
+var _ = Describe("Push notifications", func() {
+	ctx := context.Background()
+	var server *pushServer
+	var client *redis.Client
+
+	var mu sync.Mutex
+	var received [][]interface{}
+
+	handler := redis.PushNotificationHandlerFunc(func(notification []interface{}) error {
+		mu.Lock()
+		received = append(received, notification)
+		mu.Unlock()
+		return nil
+	})
+
+	BeforeEach(func() {
+		received = nil
+		server = newPushServer()
+		client = redis.NewClient(&redis.Options{
+			Addr:     server.Addr(),
+			PoolSize: 1,
+			OnPush: map[string]redis.PushNotificationHandler{
+				"message": handler,
+			},
+		})
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+		Expect(server.Close()).NotTo(HaveOccurred())
+	})
+
+	It("dispatches push notifications preceding the reply", func() {
+		server.Push(">3\r\n$7\r\nmessage\r\n$2\r\nch\r\n$5\r\nhello\r\n")
+		server.Push(">2\r\n$10\r\ninvalidate\r\n_\r\n")
+		server.Push(">3\r\n$7\r\nmessage\r\n$2\r\nch\r\n$5\r\nworld\r\n")
+
+		val, err := client.Get(ctx, "key").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("hello"))
+
+		Expect(client.Ping(ctx).Err()).NotTo(HaveOccurred())
+
+		Expect(received).To(Equal([][]interface{}{
+			{"message", "ch", "hello"},
+			{"message", "ch", "world"},
+		}))
+	})
+
+	It("returns handler errors", func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+		client = redis.NewClient(&redis.Options{
+			Addr: server.Addr(),
+			OnPush: map[string]redis.PushNotificationHandler{
+				"message": redis.PushNotificationHandlerFunc(func([]interface{}) error {
+					return errors.New("push failed")
+				}),
+			},
+		})
+
+		server.Push(">3\r\n$7\r\nmessage\r\n$2\r\nch\r\n$5\r\nhello\r\n")
+		err := client.Get(ctx, "key").Err()
+		Expect(err).To(MatchError("push failed"))
+
+		Expect(client.Get(ctx, "key").Val()).To(Equal("hello"))
+	})
+
+	It("requires RESP3", func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+		client = redis.NewClient(&redis.Options{
+			Addr:     server.Addr(),
+			Protocol: 2,
+			OnPush: map[string]redis.PushNotificationHandler{
+				"message": handler,
+			},
+		})
+
+		err := client.Ping(ctx).Err()
+		Expect(err).To(MatchError("redis: push notifications require RESP3 protocol"))
+	})
+})
+
+var _ = Describe("Attributes", func() {
+	ctx := context.Background()
+	var server *pushServer
+
+	BeforeEach(func() {
+		server = newPushServer()
+	})
+
+	AfterEach(func() {
+		Expect(server.Close()).NotTo(HaveOccurred())
+	})
+
+	It("sets attributes on the cmd", func() {
+		client := redis.NewClient(&redis.Options{
+			Addr:              server.Addr(),
+			AttributesEnabled: true,
+		})
+		defer client.Close()
+
+		server.Push("|1\r\n$3\r\nttl\r\n:3600\r\n")
+		cmd := client.Get(ctx, "key")
+		Expect(cmd.Val()).To(Equal("hello"))
+		Expect(cmd.Attributes()).To(Equal(map[string]interface{}{"ttl": int64(3600)}))
+
+		cmd = client.Get(ctx, "key")
+		Expect(cmd.Val()).To(Equal("hello"))
+		Expect(cmd.Attributes()).To(BeNil())
+	})
+
+	It("discards attributes by default", func() {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		defer client.Close()
+
+		server.Push("|1\r\n$3\r\nttl\r\n:3600\r\n")
+		cmd := client.Get(ctx, "key")
+		Expect(cmd.Val()).To(Equal("hello"))
+		Expect(cmd.Attributes()).To(BeNil())
+	})
+})
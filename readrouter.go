@@ -0,0 +1,173 @@
+package redis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal/rand"
+)
+
+// MetadataAvailabilityZone is the node metadata key of the availability zone.
+// It is reported by CLUSTER SHARDS on the servers that support it and can be
+// supplied by ClusterOptions.NodeMetadata.
+const MetadataAvailabilityZone = "availability-zone"
+
+// ReadNode describes a master or replica node to ReadRouter.
+type ReadNode struct {
+	Addr   string
+	Master bool
+	// Failing is true if the node recently failed, e.g. it is loading.
+	Failing bool
+	// Latency is the measured round-trip time of the node.
+	Latency time.Duration
+	// Metadata is the metadata of the node, e.g. MetadataAvailabilityZone.
+	// It must not be modified.
+	Metadata map[string]string
+}
+
+// ReadRouter selects the node for the read-only commands of a slot.
+// It is used by ClusterClient and NewFailoverClusterClient instead of
+// RouteByLatency and RouteRandomly.
+type ReadRouter interface {
+	// Route returns the index of the node in the nodes. The master is nodes[0].
+	// The master is used if the index is out of range.
+	Route(slot int, nodes []ReadNode) int
+}
+
+// ReadRouterFunc is an adapter to use a function as ReadRouter.
+type ReadRouterFunc func(slot int, nodes []ReadNode) int
+
+func (fn ReadRouterFunc) Route(slot int, nodes []ReadNode) int {
+	return fn(slot, nodes)
+}
+
+// randomNode returns a random node that matches, preferring the replicas, or -1.
+func randomNode(nodes []ReadNode, match func(node *ReadNode) bool) int {
+	master := -1
+	for _, i := range rand.Perm(len(nodes)) {
+		node := &nodes[i]
+		if node.Failing || !match(node) {
+			continue
+		}
+		if !node.Master {
+			return i
+		}
+		master = i
+	}
+	return master
+}
+
+//------------------------------------------------------------------------------
+
+// ZoneReadRouter routes the read-only commands to a random node in the Zone,
+// preferring the replicas, to avoid the cross-zone latency and traffic costs.
+// If no node in the Zone is available, it uses a random replica or the master.
+type ZoneReadRouter struct {
+	Zone string
+}
+
+var _ ReadRouter = (*ZoneReadRouter)(nil)
+
+func (r *ZoneReadRouter) Route(slot int, nodes []ReadNode) int {
+	if i := randomNode(nodes, func(node *ReadNode) bool {
+		return node.Metadata[MetadataAvailabilityZone] == r.Zone
+	}); i != -1 {
+		return i
+	}
+	return randomNode(nodes, func(node *ReadNode) bool {
+		return true
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// WeightedReadRouter routes the read-only commands to a random node
+// with the probability proportional to the Weight of the node.
+// The failing nodes and the nodes with the zero weight are skipped.
+// If no node has a weight, it uses the master.
+type WeightedReadRouter struct {
+	Weight func(node ReadNode) int
+}
+
+var _ ReadRouter = (*WeightedReadRouter)(nil)
+
+func (r *WeightedReadRouter) Route(slot int, nodes []ReadNode) int {
+	weights := make([]int, len(nodes))
+	var total int
+	for i, node := range nodes {
+		if node.Failing {
+			continue
+		}
+		if w := r.Weight(node); w > 0 {
+			weights[i] = w
+			total += w
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	n := rand.Intn(total)
+	for i, w := range weights {
+		if n < w {
+			return i
+		}
+		n -= w
+	}
+	return 0
+}
+
+//------------------------------------------------------------------------------
+
+// LatencyReadRouter routes the read-only commands to the node with the lowest latency.
+// Unlike RouteByLatency, it keeps using the node selected for a slot until another node
+// is faster by more than the Hysteresis, so the commands do not flip between the nodes
+// with similar latencies.
+type LatencyReadRouter struct {
+	Hysteresis time.Duration
+
+	mu       sync.Mutex
+	selected map[int]string // slot -> node addr
+}
+
+var _ ReadRouter = (*LatencyReadRouter)(nil)
+
+// NewLatencyReadRouter returns a LatencyReadRouter with the hysteresis.
+func NewLatencyReadRouter(hysteresis time.Duration) *LatencyReadRouter {
+	return &LatencyReadRouter{
+		Hysteresis: hysteresis,
+	}
+}
+
+func (r *LatencyReadRouter) Route(slot int, nodes []ReadNode) int {
+	best := -1
+	for i := range nodes {
+		if nodes[i].Failing {
+			continue
+		}
+		if best == -1 || nodes[i].Latency < nodes[best].Latency {
+			best = i
+		}
+	}
+	if best == -1 {
+		return 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if addr, ok := r.selected[slot]; ok {
+		for i := range nodes {
+			if nodes[i].Addr == addr && !nodes[i].Failing &&
+				nodes[i].Latency <= nodes[best].Latency+r.Hysteresis {
+				return i
+			}
+		}
+	}
+
+	if r.selected == nil {
+		r.selected = make(map[int]string)
+	}
+	r.selected[slot] = nodes[best].Addr
+	return best
+}
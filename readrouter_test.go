@@ -0,0 +1,131 @@
+package redis_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("ReadRouter", func() {
+	zoneNodes := func() []redis.ReadNode {
+		return []redis.ReadNode{{
+			Addr:     "master",
+			Master:   true,
+			Metadata: map[string]string{redis.MetadataAvailabilityZone: "a"},
+		}, {
+			Addr:     "replica-b",
+			Metadata: map[string]string{redis.MetadataAvailabilityZone: "b"},
+		}, {
+			Addr:     "replica-c",
+			Metadata: map[string]string{redis.MetadataAvailabilityZone: "c"},
+		}}
+	}
+
+	It("should prefer the nodes in the zone", func() {
+		nodes := zoneNodes()
+
+		router := &redis.ZoneReadRouter{Zone: "b"}
+		for i := 0; i < 10; i++ {
+			Expect(router.Route(0, nodes)).To(Equal(1))
+		}
+
+		router = &redis.ZoneReadRouter{Zone: "a"}
+		Expect(router.Route(0, nodes)).To(Equal(0))
+
+		nodes[1].Failing = true
+		router = &redis.ZoneReadRouter{Zone: "b"}
+		for i := 0; i < 10; i++ {
+			Expect(router.Route(0, nodes)).To(Equal(2))
+		}
+
+		nodes[2].Failing = true
+		Expect(router.Route(0, nodes)).To(Equal(0))
+	})
+
+	It("should route by weight", func() {
+		nodes := zoneNodes()
+		router := &redis.WeightedReadRouter{
+			Weight: func(node redis.ReadNode) int {
+				if node.Master {
+					return 0
+				}
+				return 1
+			},
+		}
+
+		routed := make(map[int]int)
+		for i := 0; i < 100; i++ {
+			routed[router.Route(0, nodes)]++
+		}
+		Expect(routed).NotTo(HaveKey(0))
+		Expect(routed[1]).To(BeNumerically(">", 0))
+		Expect(routed[2]).To(BeNumerically(">", 0))
+
+		nodes[1].Failing = true
+		nodes[2].Failing = true
+		Expect(router.Route(0, nodes)).To(Equal(0))
+	})
+
+	It("should keep the node within the hysteresis", func() {
+		nodes := zoneNodes()
+		nodes[0].Latency = 3 * time.Millisecond
+		nodes[1].Latency = 2 * time.Millisecond
+		nodes[2].Latency = 5 * time.Millisecond
+
+		router := redis.NewLatencyReadRouter(time.Millisecond)
+		Expect(router.Route(0, nodes)).To(Equal(1))
+
+		nodes[0].Latency = 1500 * time.Microsecond
+		Expect(router.Route(0, nodes)).To(Equal(1))
+		Expect(router.Route(1, nodes)).To(Equal(0))
+
+		nodes[0].Latency = 500 * time.Microsecond
+		Expect(router.Route(0, nodes)).To(Equal(0))
+
+		nodes[0].Failing = true
+		Expect(router.Route(0, nodes)).To(Equal(1))
+	})
+
+	It("should route the read-only commands of ClusterClient", func() {
+		var mu sync.Mutex
+		var routed [][]redis.ReadNode
+
+		zones := map[string]string{redisAddr: "a", "127.0.0.1" + redisAddr: "b"}
+		client := redis.NewClusterClient(&redis.ClusterOptions{
+			ClusterSlots: func(ctx context.Context) ([]redis.ClusterSlot, error) {
+				return []redis.ClusterSlot{{
+					Start: 0,
+					End:   16383,
+					Nodes: []redis.ClusterNode{{Addr: redisAddr}, {Addr: "127.0.0.1" + redisAddr}},
+				}}, nil
+			},
+			NodeMetadata: func(addr string) map[string]string {
+				return map[string]string{redis.MetadataAvailabilityZone: zones[addr]}
+			},
+			ReadRouter: redis.ReadRouterFunc(func(slot int, nodes []redis.ReadNode) int {
+				mu.Lock()
+				routed = append(routed, nodes)
+				mu.Unlock()
+				return (&redis.ZoneReadRouter{Zone: "b"}).Route(slot, nodes)
+			}),
+		})
+		defer client.Close()
+
+		Expect(client.Set(ctx, "key", "value", 0).Err()).NotTo(HaveOccurred())
+		Expect(client.Get(ctx, "key").Val()).To(Equal("value"))
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(routed).To(HaveLen(1))
+		Expect(routed[0]).To(HaveLen(2))
+		Expect(routed[0][0].Master).To(BeTrue())
+		Expect(routed[0][0].Metadata).To(HaveKeyWithValue(redis.MetadataAvailabilityZone, "a"))
+		Expect(routed[0][1].Addr).To(Equal("127.0.0.1" + redisAddr))
+		Expect(routed[0][1].Metadata).To(HaveKeyWithValue(redis.MetadataAvailabilityZone, "b"))
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"reflect"
 	"sync/atomic"
 	"time"
 
@@ -17,6 +18,16 @@ import (
 // Scanner internal/hscan.Scanner exposed interface.
 type Scanner = hscan.Scanner
 
+// StructCodec encodes and decodes the struct fields of a type, see RegisterStructCodec.
+type StructCodec = hscan.Codec
+
+// RegisterStructCodec registers the codec of the type of v, e.g. decimal.Decimal{},
+// used to encode the struct fields of the type in HSet and MSet and to decode them in Scan.
+// It overrides the built-in encoding and should be called before the structs are used.
+func RegisterStructCodec(v interface{}, codec StructCodec) {
+	hscan.RegisterCodec(reflect.TypeOf(v), codec)
+}
+
 // Nil reply returned by Redis when key does not exist.
 const Nil = proto.Nil
 
@@ -185,6 +196,11 @@ func (hs *hooksMixin) processTxPipelineHook(ctx context.Context, cmds []Cmder) e
 type baseClient struct {
 	opt      *Options
 	connPool pool.Pooler
+	cache    *clientCache
+
+	pushHandlers  pushHandlers
+	autoPipeliner *autoPipeliner
+	mux           *multiplexer
 
 	onClose func() error // hook called when client is closed
 }
@@ -201,6 +217,11 @@ func (c *baseClient) withTimeout(timeout time.Duration) *baseClient {
 
 	clone := c.clone()
 	clone.opt = opt
+	if c.autoPipeliner != nil {
+		clone.autoPipeliner = newAutoPipeliner(opt.AutoPipeline, clone.processPipeline)
+	}
+	// Multiplexed connections use the client timeouts, so the commands use the pool.
+	clone.mux = nil
 
 	return clone
 }
@@ -261,14 +282,43 @@ func (c *baseClient) _getConn(ctx context.Context) (*pool.Conn, error) {
 		return nil, err
 	}
 
+	if err := c.setupConn(ctx, cn); err != nil {
+		c.connPool.Remove(ctx, cn, err)
+		return nil, err
+	}
+
 	return cn, nil
 }
 
+// setupConn enables the optional features that are used by the commands,
+// but not by the pubsub connections.
+func (c *baseClient) setupConn(ctx context.Context, cn *pool.Conn) error {
+	if c.cache != nil {
+		if err := c.initTracking(ctx, cn); err != nil {
+			return err
+		}
+	}
+
+	if c.opt.AttributesEnabled {
+		cn.KeepAttributes(true)
+	}
+
+	if len(c.pushHandlers) > 0 {
+		if c.opt.Protocol == 2 {
+			return errPushRESP2
+		}
+		cn.SetPushHandler(c.pushHandlers.handle)
+	}
+
+	return nil
+}
+
 func (c *baseClient) initConn(ctx context.Context, cn *pool.Conn) error {
 	if cn.Inited {
 		return nil
 	}
 	cn.Inited = true
+	cn.SetCodec(c.opt.Codec)
 
 	username, password := c.opt.Username, c.opt.Password
 	if c.opt.CredentialsProvider != nil {
@@ -338,9 +388,13 @@ func (c *baseClient) releaseConn(ctx context.Context, cn *pool.Conn, err error)
 		c.opt.Limiter.ReportResult(err)
 	}
 
-	if isBadConn(err, false, c.opt.Addr) {
+	switch {
+	case isBadConn(err, false, c.opt.Addr):
 		c.connPool.Remove(ctx, cn, err)
-	} else {
+	case c.cache != nil && c.cache.stale(cn):
+		// The invalidation messages of the connection are lost.
+		c.connPool.Remove(ctx, cn, errStaleTracking)
+	default:
 		c.connPool.Put(ctx, cn)
 	}
 }
@@ -368,6 +422,20 @@ func (c *baseClient) dial(ctx context.Context, network, addr string) (net.Conn,
 }
 
 func (c *baseClient) process(ctx context.Context, cmd Cmder) error {
+	if c.cache != nil && c.cache.load(cmd) {
+		// The cached replies are not written, so the codec is set here.
+		if c.opt.Codec != nil {
+			cmd.setCodec(c.opt.Codec)
+		}
+		return cmd.Err()
+	}
+	if c.autoPipeliner != nil && autoPipelineable(cmd) {
+		return c.autoPipeliner.process(ctx, cmd)
+	}
+	if c.mux != nil && multiplexable(cmd) {
+		return c.muxProcess(ctx, []Cmder{cmd})
+	}
+
 	var lastErr error
 	for attempt := 0; attempt <= c.opt.MaxRetries; attempt++ {
 		attempt := attempt
@@ -391,14 +459,28 @@ func (c *baseClient) _process(ctx context.Context, cmd Cmder, attempt int) (bool
 
 	retryTimeout := uint32(0)
 	if err := c.withConn(ctx, func(ctx context.Context, cn *pool.Conn) error {
+		var epoch uint64
+		if c.cache != nil {
+			epoch = c.cache.begin(cmd)
+		}
+
 		if err := cn.WithWriter(c.context(ctx), c.opt.WriteTimeout, func(wr *proto.Writer) error {
 			return writeCmd(wr, cmd)
 		}); err != nil {
+			if c.cache != nil {
+				c.cache.store(cn, epoch, cmd, err)
+			}
 			atomic.StoreUint32(&retryTimeout, 1)
 			return err
 		}
 
-		if err := cn.WithReader(c.context(ctx), c.cmdTimeout(cmd), cmd.readReply); err != nil {
+		err := cn.WithReader(c.context(ctx), c.cmdTimeout(cmd), func(rd *proto.Reader) error {
+			return readCmdReply(rd, cmd)
+		})
+		if c.cache != nil {
+			c.cache.store(cn, epoch, cmd, err)
+		}
+		if err != nil {
 			if cmd.readTimeout() == nil {
 				atomic.StoreUint32(&retryTimeout, 1)
 			} else {
@@ -442,6 +524,9 @@ func (c *baseClient) Close() error {
 			firstErr = err
 		}
 	}
+	if c.mux != nil {
+		c.mux.close()
+	}
 	if err := c.connPool.Close(); err != nil && firstErr == nil {
 		firstErr = err
 	}
@@ -453,6 +538,12 @@ func (c *baseClient) getAddr() string {
 }
 
 func (c *baseClient) processPipeline(ctx context.Context, cmds []Cmder) error {
+	if c.mux != nil && multiplexableCmds(cmds) {
+		if err := c.muxProcess(ctx, cmds); err != nil {
+			return err
+		}
+		return cmdsFirstErr(cmds)
+	}
 	if err := c.generalProcessPipeline(ctx, cmds, c.pipelineProcessCmds); err != nil {
 		return err
 	}
@@ -497,6 +588,17 @@ func (c *baseClient) generalProcessPipeline(
 func (c *baseClient) pipelineProcessCmds(
 	ctx context.Context, cn *pool.Conn, cmds []Cmder,
 ) (bool, error) {
+	// The replies of the pipelines, including the batches of AutoPipeline, are cached.
+	var epoch uint64
+	if c.cache != nil {
+		epoch = c.cache.begin(cmds...)
+		defer func() {
+			for _, cmd := range cmds {
+				c.cache.store(cn, epoch, cmd, cmd.Err())
+			}
+		}()
+	}
+
 	if err := cn.WithWriter(c.context(ctx), c.opt.WriteTimeout, func(wr *proto.Writer) error {
 		return writeCmds(wr, cmds)
 	}); err != nil {
@@ -515,7 +617,7 @@ func (c *baseClient) pipelineProcessCmds(
 
 func pipelineReadCmds(rd *proto.Reader, cmds []Cmder) error {
 	for i, cmd := range cmds {
-		err := cmd.readReply(rd)
+		err := readCmdReply(rd, cmd)
 		cmd.SetErr(err)
 		if err != nil && !isRedisError(err) {
 			setCmdsErr(cmds[i+1:], err)
@@ -612,8 +714,19 @@ func NewClient(opt *Options) *Client {
 			opt: opt,
 		},
 	}
+	c.pushHandlers = newPushHandlers(opt.OnPush)
+	if opt.ClientCache != nil {
+		c.cache = newClientCache(opt.ClientCache)
+		c.pushHandlers.add("invalidate", PushNotificationHandlerFunc(c.cache.handlePush))
+	}
+	if opt.AutoPipeline != nil {
+		c.autoPipeliner = newAutoPipeliner(opt.AutoPipeline, c.baseClient.processPipeline)
+	}
 	c.init()
 	c.connPool = newConnPool(opt, c.dialHook)
+	if opt.MultiplexConns > 0 {
+		c.mux = newMultiplexer(opt, c.baseClient.newMuxConn)
+	}
 
 	return &c
 }
@@ -665,13 +778,30 @@ func (c *Client) PoolStats() *PoolStats {
 	return (*PoolStats)(stats)
 }
 
+// CacheStats returns client-side cache stats.
+func (c *Client) CacheStats() *CacheStats {
+	if c.cache == nil {
+		return &CacheStats{}
+	}
+	return c.cache.stats()
+}
+
+// AutoPipelineStats returns automatic pipelining stats.
+func (c *Client) AutoPipelineStats() *AutoPipelineStats {
+	if c.autoPipeliner == nil {
+		return &AutoPipelineStats{}
+	}
+	return c.autoPipeliner.stats()
+}
+
 func (c *Client) Pipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
 	return c.Pipeline().Pipelined(ctx, fn)
 }
 
 func (c *Client) Pipeline() Pipeliner {
 	pipe := Pipeline{
-		exec: pipelineExecer(c.processPipelineHook),
+		exec:  pipelineExecer(c.processPipelineHook),
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
@@ -688,6 +818,7 @@ func (c *Client) TxPipeline() Pipeliner {
 			cmds = wrapMultiExec(ctx, cmds)
 			return c.processTxPipelineHook(ctx, cmds)
 		},
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
@@ -804,7 +935,8 @@ func (c *Conn) Pipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder
 
 func (c *Conn) Pipeline() Pipeliner {
 	pipe := Pipeline{
-		exec: c.processPipelineHook,
+		exec:  c.processPipelineHook,
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
@@ -821,6 +953,7 @@ func (c *Conn) TxPipeline() Pipeliner {
 			cmds = wrapMultiExec(ctx, cmds)
 			return c.processTxPipelineHook(ctx, cmds)
 		},
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
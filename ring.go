@@ -83,6 +83,9 @@ type RingOptions struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 
+	Codec  Codec
+	OnPush map[string]PushNotificationHandler
+
 	// PoolFIFO uses FIFO mode for each node connection pool GET/PUT (default LIFO).
 	PoolFIFO bool
 
@@ -95,6 +98,10 @@ type RingOptions struct {
 
 	TLSConfig *tls.Config
 	Limiter   Limiter
+
+	// CircuitBreaker creates a CircuitBreaker per shard named by the shard address.
+	// It takes precedence over Limiter.
+	CircuitBreaker *CircuitBreakerOptions
 }
 
 func (opt *RingOptions) init() {
@@ -148,6 +155,9 @@ func (opt *RingOptions) clientOptions() *Options {
 		ReadTimeout:  opt.ReadTimeout,
 		WriteTimeout: opt.WriteTimeout,
 
+		Codec:  opt.Codec,
+		OnPush: opt.OnPush,
+
 		PoolFIFO:        opt.PoolFIFO,
 		PoolSize:        opt.PoolSize,
 		PoolTimeout:     opt.PoolTimeout,
@@ -172,6 +182,9 @@ type ringShard struct {
 func newRingShard(opt *RingOptions, addr string) *ringShard {
 	clopt := opt.clientOptions()
 	clopt.Addr = addr
+	if cb := nodeCircuitBreaker(opt.CircuitBreaker, addr); cb != nil {
+		clopt.Limiter = cb
+	}
 
 	return &ringShard{
 		Client: opt.NewClient(clopt),
@@ -653,6 +666,30 @@ func (c *Ring) ForEachShard(
 	}
 }
 
+// ScanAll returns an iterator over the keys of all the shards that match the pattern
+// and, unless keyType is empty, have the type. The count is passed to SCAN as the COUNT hint.
+// Use ScanAllIterator.ForEach to scan the shards concurrently.
+//
+// The shards that are down are scanned too, so the iterator fails with the error
+// of an unavailable shard instead of skipping its keys. The iterator lists the shards
+// again when the walk ends and scans the shards that were added. The keys are
+// deduplicated, so the iterator keeps all the returned keys in memory.
+func (c *Ring) ScanAll(ctx context.Context, match string, count int64, keyType string) *ScanAllIterator {
+	return newScanAllIterator(ctx, match, count, keyType, c.scanAllNodes)
+}
+
+func (c *Ring) scanAllNodes(ctx context.Context, reload bool) ([]scanAllNode, error) {
+	shards := c.sharding.List()
+	nodes := make([]scanAllNode, 0, len(shards))
+	for _, shard := range shards {
+		nodes = append(nodes, scanAllNode{
+			addr:   shard.addr,
+			client: shard.Client,
+		})
+	}
+	return nodes, nil
+}
+
 func (c *Ring) cmdsInfo(ctx context.Context) (map[string]*CommandInfo, error) {
 	shards := c.sharding.List()
 	var firstErr error
@@ -721,7 +758,8 @@ func (c *Ring) Pipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder
 
 func (c *Ring) Pipeline() Pipeliner {
 	pipe := Pipeline{
-		exec: pipelineExecer(c.processPipelineHook),
+		exec:  pipelineExecer(c.processPipelineHook),
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
@@ -737,6 +775,7 @@ func (c *Ring) TxPipeline() Pipeliner {
 			cmds = wrapMultiExec(ctx, cmds)
 			return c.processTxPipelineHook(ctx, cmds)
 		},
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
@@ -130,6 +130,46 @@ var _ = Describe("Redis Ring", func() {
 		Expect(ringShard2.Info(ctx, "keyspace").Val()).To(ContainSubstring("keys=44"))
 	})
 
+	It("scans the keys of all the shards", func() {
+		setRingKeys()
+		Expect(ring.HSet(ctx, "hash", "field", "value").Err()).NotTo(HaveOccurred())
+
+		var keys []string
+		iter := ring.ScanAll(ctx, "key*", 10, "")
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		Expect(iter.Err()).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(100))
+		Expect(keys).To(ContainElements("key0", "key99"))
+
+		var mu sync.Mutex
+		keys = nil
+		err := ring.ScanAll(ctx, "*", 10, "hash").ForEach(ctx, func(ctx context.Context, key string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			keys = append(keys, key)
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(Equal([]string{"hash"}))
+	})
+
+	It("fails to scan the keys of a shard that is down", func() {
+		opt := redisRingOptions()
+		opt.Addrs = map[string]string{
+			"ringShardOne": ":" + ringShard1Port,
+			"down":         "127.0.0.1:1",
+		}
+		ring := redis.NewRing(opt)
+		defer ring.Close()
+
+		iter := ring.ScanAll(ctx, "*", 10, "")
+		for iter.Next(ctx) {
+		}
+		Expect(iter.Err()).To(HaveOccurred())
+	})
+
 	It("uses single shard when one of the shards is down", func() {
 		// Stop ringShard2.
 		Expect(ringShard2.Close()).NotTo(HaveOccurred())
@@ -0,0 +1,1447 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+type searchCmdable interface {
+	FTAggregate(ctx context.Context, index, query string) *FTAggregateCmd
+	FTAggregateWithArgs(ctx context.Context, index, query string, options *FTAggregateOptions) *FTAggregateCmd
+	FTAliasAdd(ctx context.Context, index, alias string) *StatusCmd
+	FTAliasDel(ctx context.Context, alias string) *StatusCmd
+	FTAliasUpdate(ctx context.Context, index, alias string) *StatusCmd
+	FTAlter(ctx context.Context, index string, skipInitialScan bool, schema ...*FieldSchema) *StatusCmd
+	FTCreate(ctx context.Context, index string, options *FTCreateOptions, schema ...*FieldSchema) *StatusCmd
+	FTCursorDel(ctx context.Context, index string, cursorID int64) *StatusCmd
+	FTCursorRead(ctx context.Context, index string, cursorID int64, count int) *FTAggregateCmd
+	FTDropIndex(ctx context.Context, index string) *StatusCmd
+	FTDropIndexWithArgs(ctx context.Context, index string, options *FTDropIndexOptions) *StatusCmd
+	FTExplain(ctx context.Context, index, query string) *StringCmd
+	FTExplainWithArgs(ctx context.Context, index, query string, options *FTExplainOptions) *StringCmd
+	FTInfo(ctx context.Context, index string) *FTInfoCmd
+	FTSearch(ctx context.Context, index, query string) *FTSearchCmd
+	FTSearchWithArgs(ctx context.Context, index, query string, options *FTSearchOptions) *FTSearchCmd
+	FTSugAdd(ctx context.Context, key, suggestion string, score float64) *IntCmd
+	FTSugAddWithArgs(ctx context.Context, key, suggestion string, score float64, options *FTSugAddOptions) *IntCmd
+	FTSugDel(ctx context.Context, key, suggestion string) *BoolCmd
+	FTSugGet(ctx context.Context, key, prefix string) *FTSuggestionSliceCmd
+	FTSugGetWithArgs(ctx context.Context, key, prefix string, options *FTSugGetOptions) *FTSuggestionSliceCmd
+	FTSugLen(ctx context.Context, key string) *IntCmd
+}
+
+type SearchFieldType int
+
+const (
+	SearchFieldTypeInvalid SearchFieldType = iota
+	SearchFieldTypeText
+	SearchFieldTypeTag
+	SearchFieldTypeNumeric
+	SearchFieldTypeGeo
+	SearchFieldTypeVector
+)
+
+func (t SearchFieldType) String() string {
+	switch t {
+	case SearchFieldTypeText:
+		return "TEXT"
+	case SearchFieldTypeTag:
+		return "TAG"
+	case SearchFieldTypeNumeric:
+		return "NUMERIC"
+	case SearchFieldTypeGeo:
+		return "GEO"
+	case SearchFieldTypeVector:
+		return "VECTOR"
+	default:
+		return "INVALID"
+	}
+}
+
+// FieldSchema describes an indexed field. FieldName is a JSONPath for JSON indexes,
+// which usually requires As to name the field in queries.
+type FieldSchema struct {
+	FieldName string
+	As        string
+	FieldType SearchFieldType
+	Sortable  bool
+	UNF       bool
+	NoIndex   bool
+
+	// TEXT options.
+	NoStem   bool
+	Weight   float64
+	Phonetic string
+
+	// TAG options.
+	Separator     string
+	CaseSensitive bool
+
+	// TEXT and TAG options.
+	WithSuffixtrie bool
+
+	// VECTOR options.
+	VectorArgs *FTVectorArgs
+}
+
+// FTVectorArgs configures a VECTOR field. Exactly one of the algorithms must be set.
+type FTVectorArgs struct {
+	FlatOptions *FTFlatOptions
+	HNSWOptions *FTHNSWOptions
+}
+
+type FTFlatOptions struct {
+	Type            string // FLOAT32 or FLOAT64
+	Dim             int
+	DistanceMetric  string // L2, IP or COSINE
+	InitialCapacity int
+	BlockSize       int
+}
+
+type FTHNSWOptions struct {
+	Type            string // FLOAT32 or FLOAT64
+	Dim             int
+	DistanceMetric  string // L2, IP or COSINE
+	InitialCapacity int
+	M               int
+	EFConstruction  int
+	EFRuntime       int
+	Epsilon         float64
+}
+
+type FTCreateOptions struct {
+	OnHash          bool
+	OnJSON          bool
+	Prefix          []string
+	Filter          string
+	DefaultLanguage string
+	LanguageField   string
+	Score           float64
+	ScoreField      string
+	PayloadField    string
+	MaxTextFields   bool
+	NoOffsets       bool
+	Temporary       int
+	NoHL            bool
+	NoFields        bool
+	NoFreqs         bool
+	StopWords       []string
+	SkipInitialScan bool
+}
+
+type FTDropIndexOptions struct {
+	DeleteDocs bool
+}
+
+type FTExplainOptions struct {
+	Dialect int
+}
+
+type FTSugAddOptions struct {
+	Incr    bool
+	Payload string
+}
+
+type FTSugGetOptions struct {
+	Fuzzy        bool
+	Max          int
+	WithScores   bool
+	WithPayloads bool
+}
+
+type FTSearchFilter struct {
+	FieldName string
+	Min       interface{}
+	Max       interface{}
+}
+
+type FTSearchGeoFilter struct {
+	FieldName string
+	Longitude float64
+	Latitude  float64
+	Radius    float64
+	Unit      string
+}
+
+type FTSearchReturn struct {
+	FieldName string
+	As        string
+}
+
+type FTSearchSortBy struct {
+	FieldName string
+	Asc       bool
+	Desc      bool
+}
+
+type FTSearchOptions struct {
+	NoContent    bool
+	Verbatim     bool
+	NoStopWords  bool
+	WithScores   bool
+	WithPayloads bool
+	WithSortKeys bool
+	Filters      []FTSearchFilter
+	GeoFilter    []FTSearchGeoFilter
+	InKeys       []string
+	InFields     []string
+	Return       []FTSearchReturn
+	Slop         int
+	Timeout      int
+	InOrder      bool
+	Language     string
+	Expander     string
+	Scorer       string
+	ExplainScore bool
+	Payload      string
+	SortBy       []FTSearchSortBy
+	LimitOffset  int
+	Limit        int
+	Params       map[string]interface{}
+	Dialect      int
+}
+
+type FTAggregateLoad struct {
+	Field string
+	As    string
+}
+
+// FTAggregateReducer is a GROUPBY reducer, e.g. COUNT or SUM with the field as an argument.
+type FTAggregateReducer struct {
+	Reducer string
+	Args    []interface{}
+	As      string
+}
+
+type FTAggregateGroupBy struct {
+	Fields []string
+	Reduce []FTAggregateReducer
+}
+
+type FTAggregateSortBy struct {
+	FieldName string
+	Asc       bool
+	Desc      bool
+}
+
+type FTAggregateApply struct {
+	Field string
+	As    string
+}
+
+type FTAggregateWithCursor struct {
+	Count   int
+	MaxIdle int
+}
+
+type FTAggregateOptions struct {
+	Verbatim          bool
+	LoadAll           bool
+	Load              []FTAggregateLoad
+	Timeout           int
+	GroupBy           []FTAggregateGroupBy
+	SortBy            []FTAggregateSortBy
+	SortByMax         int
+	Apply             []FTAggregateApply
+	LimitOffset       int
+	Limit             int
+	Filter            string
+	WithCursor        bool
+	WithCursorOptions *FTAggregateWithCursor
+	Params            map[string]interface{}
+	Dialect           int
+}
+
+//------------------------------------------------------------------------------
+
+func appendFieldSchema(args []interface{}, field *FieldSchema) ([]interface{}, error) {
+	args = append(args, field.FieldName)
+	if field.As != "" {
+		args = append(args, "AS", field.As)
+	}
+	args = append(args, field.FieldType.String())
+
+	switch field.FieldType {
+	case SearchFieldTypeText:
+		if field.NoStem {
+			args = append(args, "NOSTEM")
+		}
+		if field.Weight > 0 {
+			args = append(args, "WEIGHT", field.Weight)
+		}
+		if field.Phonetic != "" {
+			args = append(args, "PHONETIC", field.Phonetic)
+		}
+		if field.WithSuffixtrie {
+			args = append(args, "WITHSUFFIXTRIE")
+		}
+	case SearchFieldTypeTag:
+		if field.Separator != "" {
+			args = append(args, "SEPARATOR", field.Separator)
+		}
+		if field.CaseSensitive {
+			args = append(args, "CASESENSITIVE")
+		}
+		if field.WithSuffixtrie {
+			args = append(args, "WITHSUFFIXTRIE")
+		}
+	case SearchFieldTypeNumeric, SearchFieldTypeGeo:
+	case SearchFieldTypeVector:
+		var err error
+		if args, err = appendVectorArgs(args, field.VectorArgs); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("redis: invalid type of the search field %q", field.FieldName)
+	}
+
+	if field.Sortable {
+		args = append(args, "SORTABLE")
+		if field.UNF {
+			args = append(args, "UNF")
+		}
+	}
+	if field.NoIndex {
+		args = append(args, "NOINDEX")
+	}
+	return args, nil
+}
+
+func appendVectorArgs(args []interface{}, v *FTVectorArgs) ([]interface{}, error) {
+	if v == nil || (v.FlatOptions == nil) == (v.HNSWOptions == nil) {
+		return nil, errors.New("redis: VECTOR field requires either FLAT or HNSW options")
+	}
+
+	var attrs []interface{}
+	if opt := v.FlatOptions; opt != nil {
+		args = append(args, "FLAT")
+		attrs = []interface{}{"TYPE", opt.Type, "DIM", opt.Dim, "DISTANCE_METRIC", opt.DistanceMetric}
+		if opt.InitialCapacity > 0 {
+			attrs = append(attrs, "INITIAL_CAP", opt.InitialCapacity)
+		}
+		if opt.BlockSize > 0 {
+			attrs = append(attrs, "BLOCK_SIZE", opt.BlockSize)
+		}
+	} else {
+		opt := v.HNSWOptions
+		args = append(args, "HNSW")
+		attrs = []interface{}{"TYPE", opt.Type, "DIM", opt.Dim, "DISTANCE_METRIC", opt.DistanceMetric}
+		if opt.InitialCapacity > 0 {
+			attrs = append(attrs, "INITIAL_CAP", opt.InitialCapacity)
+		}
+		if opt.M > 0 {
+			attrs = append(attrs, "M", opt.M)
+		}
+		if opt.EFConstruction > 0 {
+			attrs = append(attrs, "EF_CONSTRUCTION", opt.EFConstruction)
+		}
+		if opt.EFRuntime > 0 {
+			attrs = append(attrs, "EF_RUNTIME", opt.EFRuntime)
+		}
+		if opt.Epsilon > 0 {
+			attrs = append(attrs, "EPSILON", opt.Epsilon)
+		}
+	}
+
+	args = append(args, len(attrs))
+	return append(args, attrs...), nil
+}
+
+// appendSearchParams appends the query parameters sorted by name, so the args are stable.
+func appendSearchParams(args []interface{}, params map[string]interface{}) []interface{} {
+	if len(params) == 0 {
+		return args
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args = append(args, "PARAMS", 2*len(params))
+	for _, name := range names {
+		args = append(args, name, params[name])
+	}
+	return args
+}
+
+//------------------------------------------------------------------------------
+
+// FTCreate creates an index with the schema on the hashes or JSON documents.
+// For more information - https://redis.io/commands/ft.create/
+func (c cmdable) FTCreate(ctx context.Context, index string, options *FTCreateOptions, schema ...*FieldSchema) *StatusCmd {
+	args := []interface{}{"FT.CREATE", index}
+	var err error
+	if options != nil {
+		switch {
+		case options.OnHash && options.OnJSON:
+			err = errors.New("redis: FT.CREATE requires either OnHash or OnJSON")
+		case options.OnHash:
+			args = append(args, "ON", "HASH")
+		case options.OnJSON:
+			args = append(args, "ON", "JSON")
+		}
+		if len(options.Prefix) > 0 {
+			args = append(args, "PREFIX", len(options.Prefix))
+			for _, prefix := range options.Prefix {
+				args = append(args, prefix)
+			}
+		}
+		if options.Filter != "" {
+			args = append(args, "FILTER", options.Filter)
+		}
+		if options.DefaultLanguage != "" {
+			args = append(args, "LANGUAGE", options.DefaultLanguage)
+		}
+		if options.LanguageField != "" {
+			args = append(args, "LANGUAGE_FIELD", options.LanguageField)
+		}
+		if options.Score > 0 {
+			args = append(args, "SCORE", options.Score)
+		}
+		if options.ScoreField != "" {
+			args = append(args, "SCORE_FIELD", options.ScoreField)
+		}
+		if options.PayloadField != "" {
+			args = append(args, "PAYLOAD_FIELD", options.PayloadField)
+		}
+		if options.MaxTextFields {
+			args = append(args, "MAXTEXTFIELDS")
+		}
+		if options.Temporary > 0 {
+			args = append(args, "TEMPORARY", options.Temporary)
+		}
+		if options.NoOffsets {
+			args = append(args, "NOOFFSETS")
+		}
+		if options.NoHL {
+			args = append(args, "NOHL")
+		}
+		if options.NoFields {
+			args = append(args, "NOFIELDS")
+		}
+		if options.NoFreqs {
+			args = append(args, "NOFREQS")
+		}
+		if options.StopWords != nil {
+			args = append(args, "STOPWORDS", len(options.StopWords))
+			for _, word := range options.StopWords {
+				args = append(args, word)
+			}
+		}
+		if options.SkipInitialScan {
+			args = append(args, "SKIPINITIALSCAN")
+		}
+	}
+
+	if len(schema) == 0 && err == nil {
+		err = errors.New("redis: FT.CREATE requires at least one field")
+	}
+	args = append(args, "SCHEMA")
+	for _, field := range schema {
+		if err != nil {
+			break
+		}
+		args, err = appendFieldSchema(args, field)
+	}
+
+	cmd := NewStatusCmd(ctx, args...)
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTAlter adds the fields to the schema of the index.
+// For more information - https://redis.io/commands/ft.alter/
+func (c cmdable) FTAlter(ctx context.Context, index string, skipInitialScan bool, schema ...*FieldSchema) *StatusCmd {
+	args := []interface{}{"FT.ALTER", index}
+	if skipInitialScan {
+		args = append(args, "SKIPINITIALSCAN")
+	}
+	args = append(args, "SCHEMA", "ADD")
+
+	var err error
+	if len(schema) == 0 {
+		err = errors.New("redis: FT.ALTER requires at least one field")
+	}
+	for _, field := range schema {
+		if err != nil {
+			break
+		}
+		args, err = appendFieldSchema(args, field)
+	}
+
+	cmd := NewStatusCmd(ctx, args...)
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTDropIndex deletes the index and keeps the indexed documents.
+// For more information - https://redis.io/commands/ft.dropindex/
+func (c cmdable) FTDropIndex(ctx context.Context, index string) *StatusCmd {
+	return c.FTDropIndexWithArgs(ctx, index, nil)
+}
+
+// FTDropIndexWithArgs deletes the index. The option DeleteDocs deletes the indexed documents too.
+// For more information - https://redis.io/commands/ft.dropindex/
+func (c cmdable) FTDropIndexWithArgs(ctx context.Context, index string, options *FTDropIndexOptions) *StatusCmd {
+	args := []interface{}{"FT.DROPINDEX", index}
+	if options != nil && options.DeleteDocs {
+		args = append(args, "DD")
+	}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTAliasAdd adds the alias to the index.
+// For more information - https://redis.io/commands/ft.aliasadd/
+func (c cmdable) FTAliasAdd(ctx context.Context, index, alias string) *StatusCmd {
+	args := []interface{}{"FT.ALIASADD", alias, index}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTAliasDel removes the alias.
+// For more information - https://redis.io/commands/ft.aliasdel/
+func (c cmdable) FTAliasDel(ctx context.Context, alias string) *StatusCmd {
+	args := []interface{}{"FT.ALIASDEL", alias}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTAliasUpdate adds the alias to the index, removing it from any other index.
+// For more information - https://redis.io/commands/ft.aliasupdate/
+func (c cmdable) FTAliasUpdate(ctx context.Context, index, alias string) *StatusCmd {
+	args := []interface{}{"FT.ALIASUPDATE", alias, index}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTExplain returns the execution plan of the query.
+// For more information - https://redis.io/commands/ft.explain/
+func (c cmdable) FTExplain(ctx context.Context, index, query string) *StringCmd {
+	return c.FTExplainWithArgs(ctx, index, query, nil)
+}
+
+// FTExplainWithArgs returns the execution plan of the query using the dialect.
+// For more information - https://redis.io/commands/ft.explain/
+func (c cmdable) FTExplainWithArgs(ctx context.Context, index, query string, options *FTExplainOptions) *StringCmd {
+	args := []interface{}{"FT.EXPLAIN", index, query}
+	if options != nil && options.Dialect > 0 {
+		args = append(args, "DIALECT", options.Dialect)
+	}
+	cmd := NewStringCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSugAdd adds the suggestion to the auto-complete dictionary.
+// It returns the size of the dictionary.
+// For more information - https://redis.io/commands/ft.sugadd/
+func (c cmdable) FTSugAdd(ctx context.Context, key, suggestion string, score float64) *IntCmd {
+	return c.FTSugAddWithArgs(ctx, key, suggestion, score, nil)
+}
+
+// FTSugAddWithArgs adds the suggestion to the auto-complete dictionary.
+// The option Incr increments the score of the existing suggestion.
+// For more information - https://redis.io/commands/ft.sugadd/
+func (c cmdable) FTSugAddWithArgs(
+	ctx context.Context, key, suggestion string, score float64, options *FTSugAddOptions,
+) *IntCmd {
+	args := []interface{}{"FT.SUGADD", key, suggestion, score}
+	if options != nil {
+		if options.Incr {
+			args = append(args, "INCR")
+		}
+		if options.Payload != "" {
+			args = append(args, "PAYLOAD", options.Payload)
+		}
+	}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSugDel deletes the suggestion from the auto-complete dictionary.
+// For more information - https://redis.io/commands/ft.sugdel/
+func (c cmdable) FTSugDel(ctx context.Context, key, suggestion string) *BoolCmd {
+	args := []interface{}{"FT.SUGDEL", key, suggestion}
+	cmd := NewBoolCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSugGet returns the suggestions for the prefix.
+// For more information - https://redis.io/commands/ft.sugget/
+func (c cmdable) FTSugGet(ctx context.Context, key, prefix string) *FTSuggestionSliceCmd {
+	return c.FTSugGetWithArgs(ctx, key, prefix, nil)
+}
+
+// FTSugGetWithArgs returns the suggestions for the prefix.
+// For more information - https://redis.io/commands/ft.sugget/
+func (c cmdable) FTSugGetWithArgs(ctx context.Context, key, prefix string, options *FTSugGetOptions) *FTSuggestionSliceCmd {
+	args := []interface{}{"FT.SUGGET", key, prefix}
+	if options == nil {
+		options = &FTSugGetOptions{}
+	}
+	if options.Fuzzy {
+		args = append(args, "FUZZY")
+	}
+	if options.WithScores {
+		args = append(args, "WITHSCORES")
+	}
+	if options.WithPayloads {
+		args = append(args, "WITHPAYLOADS")
+	}
+	if options.Max > 0 {
+		args = append(args, "MAX", options.Max)
+	}
+	cmd := NewFTSuggestionSliceCmd(ctx, args...)
+	cmd.withScores = options.WithScores
+	cmd.withPayloads = options.WithPayloads
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSugLen returns the size of the auto-complete dictionary.
+// For more information - https://redis.io/commands/ft.suglen/
+func (c cmdable) FTSugLen(ctx context.Context, key string) *IntCmd {
+	args := []interface{}{"FT.SUGLEN", key}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTInfo returns information about the index.
+// For more information - https://redis.io/commands/ft.info/
+func (c cmdable) FTInfo(ctx context.Context, index string) *FTInfoCmd {
+	args := []interface{}{"FT.INFO", index}
+	cmd := NewFTInfoCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSearch searches the index with the query.
+// For more information - https://redis.io/commands/ft.search/
+func (c cmdable) FTSearch(ctx context.Context, index, query string) *FTSearchCmd {
+	return c.FTSearchWithArgs(ctx, index, query, nil)
+}
+
+// FTSearchWithArgs searches the index with the query.
+// For more information - https://redis.io/commands/ft.search/
+func (c cmdable) FTSearchWithArgs(ctx context.Context, index, query string, options *FTSearchOptions) *FTSearchCmd {
+	if options == nil {
+		options = &FTSearchOptions{}
+	}
+	args, err := ftSearchArgs(index, query, options)
+	cmd := NewFTSearchCmd(ctx, args...)
+	cmd.options = options
+	if err != nil {
+		cmd.SetErr(err)
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func ftSearchArgs(index, query string, options *FTSearchOptions) ([]interface{}, error) {
+	args := []interface{}{"FT.SEARCH", index, query}
+	if options.NoContent {
+		args = append(args, "NOCONTENT")
+	}
+	if options.Verbatim {
+		args = append(args, "VERBATIM")
+	}
+	if options.NoStopWords {
+		args = append(args, "NOSTOPWORDS")
+	}
+	if options.WithScores {
+		args = append(args, "WITHSCORES")
+	}
+	if options.WithPayloads {
+		args = append(args, "WITHPAYLOADS")
+	}
+	if options.WithSortKeys {
+		args = append(args, "WITHSORTKEYS")
+	}
+	for _, filter := range options.Filters {
+		args = append(args, "FILTER", filter.FieldName, filter.Min, filter.Max)
+	}
+	for _, filter := range options.GeoFilter {
+		args = append(args, "GEOFILTER", filter.FieldName,
+			filter.Longitude, filter.Latitude, filter.Radius, filter.Unit)
+	}
+	if len(options.InKeys) > 0 {
+		args = append(args, "INKEYS", len(options.InKeys))
+		for _, key := range options.InKeys {
+			args = append(args, key)
+		}
+	}
+	if len(options.InFields) > 0 {
+		args = append(args, "INFIELDS", len(options.InFields))
+		for _, field := range options.InFields {
+			args = append(args, field)
+		}
+	}
+	if len(options.Return) > 0 {
+		n := 0
+		for _, ret := range options.Return {
+			n++
+			if ret.As != "" {
+				n += 2
+			}
+		}
+		args = append(args, "RETURN", n)
+		for _, ret := range options.Return {
+			args = append(args, ret.FieldName)
+			if ret.As != "" {
+				args = append(args, "AS", ret.As)
+			}
+		}
+	}
+	if options.Slop > 0 {
+		args = append(args, "SLOP", options.Slop)
+	}
+	if options.Timeout > 0 {
+		args = append(args, "TIMEOUT", options.Timeout)
+	}
+	if options.InOrder {
+		args = append(args, "INORDER")
+	}
+	if options.Language != "" {
+		args = append(args, "LANGUAGE", options.Language)
+	}
+	if options.Expander != "" {
+		args = append(args, "EXPANDER", options.Expander)
+	}
+	if options.Scorer != "" {
+		args = append(args, "SCORER", options.Scorer)
+	}
+	if options.ExplainScore {
+		args = append(args, "EXPLAINSCORE")
+	}
+	if options.Payload != "" {
+		args = append(args, "PAYLOAD", options.Payload)
+	}
+	for _, sortBy := range options.SortBy {
+		args = append(args, "SORTBY", sortBy.FieldName)
+		switch {
+		case sortBy.Asc && sortBy.Desc:
+			return args, errors.New("redis: FT.SEARCH SORTBY requires either Asc or Desc")
+		case sortBy.Asc:
+			args = append(args, "ASC")
+		case sortBy.Desc:
+			args = append(args, "DESC")
+		}
+	}
+	if options.LimitOffset > 0 || options.Limit > 0 {
+		args = append(args, "LIMIT", options.LimitOffset, options.Limit)
+	}
+	args = appendSearchParams(args, options.Params)
+	if options.Dialect > 0 {
+		args = append(args, "DIALECT", options.Dialect)
+	}
+	return args, nil
+}
+
+// FTAggregate runs the aggregation query on the index.
+// For more information - https://redis.io/commands/ft.aggregate/
+func (c cmdable) FTAggregate(ctx context.Context, index, query string) *FTAggregateCmd {
+	return c.FTAggregateWithArgs(ctx, index, query, nil)
+}
+
+// FTAggregateWithArgs runs the aggregation query on the index. With the option WithCursor,
+// the result contains a cursor to read the following rows using FTCursorRead.
+// For more information - https://redis.io/commands/ft.aggregate/
+func (c cmdable) FTAggregateWithArgs(ctx context.Context, index, query string, options *FTAggregateOptions) *FTAggregateCmd {
+	args := []interface{}{"FT.AGGREGATE", index, query}
+	if options == nil {
+		options = &FTAggregateOptions{}
+	}
+	if options.Verbatim {
+		args = append(args, "VERBATIM")
+	}
+	if options.LoadAll {
+		args = append(args, "LOAD", "*")
+	} else if len(options.Load) > 0 {
+		n := 0
+		for _, load := range options.Load {
+			n++
+			if load.As != "" {
+				n += 2
+			}
+		}
+		args = append(args, "LOAD", n)
+		for _, load := range options.Load {
+			args = append(args, load.Field)
+			if load.As != "" {
+				args = append(args, "AS", load.As)
+			}
+		}
+	}
+	if options.Timeout > 0 {
+		args = append(args, "TIMEOUT", options.Timeout)
+	}
+	for _, groupBy := range options.GroupBy {
+		args = append(args, "GROUPBY", len(groupBy.Fields))
+		for _, field := range groupBy.Fields {
+			args = append(args, field)
+		}
+		for _, reducer := range groupBy.Reduce {
+			args = append(args, "REDUCE", reducer.Reducer, len(reducer.Args))
+			args = append(args, reducer.Args...)
+			if reducer.As != "" {
+				args = append(args, "AS", reducer.As)
+			}
+		}
+	}
+	if len(options.SortBy) > 0 {
+		sortArgs := make([]interface{}, 0, 2*len(options.SortBy))
+		for _, sortBy := range options.SortBy {
+			sortArgs = append(sortArgs, sortBy.FieldName)
+			switch {
+			case sortBy.Asc && sortBy.Desc:
+				cmd := NewFTAggregateCmd(ctx, args...)
+				cmd.SetErr(errors.New("redis: FT.AGGREGATE SORTBY requires either Asc or Desc"))
+				return cmd
+			case sortBy.Asc:
+				sortArgs = append(sortArgs, "ASC")
+			case sortBy.Desc:
+				sortArgs = append(sortArgs, "DESC")
+			}
+		}
+		args = append(args, "SORTBY", len(sortArgs))
+		args = append(args, sortArgs...)
+		if options.SortByMax > 0 {
+			args = append(args, "MAX", options.SortByMax)
+		}
+	}
+	for _, apply := range options.Apply {
+		args = append(args, "APPLY", apply.Field)
+		if apply.As != "" {
+			args = append(args, "AS", apply.As)
+		}
+	}
+	if options.LimitOffset > 0 || options.Limit > 0 {
+		args = append(args, "LIMIT", options.LimitOffset, options.Limit)
+	}
+	if options.Filter != "" {
+		args = append(args, "FILTER", options.Filter)
+	}
+	if options.WithCursor {
+		args = append(args, "WITHCURSOR")
+		if cursor := options.WithCursorOptions; cursor != nil {
+			if cursor.Count > 0 {
+				args = append(args, "COUNT", cursor.Count)
+			}
+			if cursor.MaxIdle > 0 {
+				args = append(args, "MAXIDLE", cursor.MaxIdle)
+			}
+		}
+	}
+	args = appendSearchParams(args, options.Params)
+	if options.Dialect > 0 {
+		args = append(args, "DIALECT", options.Dialect)
+	}
+
+	cmd := NewFTAggregateCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTCursorRead reads the next rows of the aggregation. The count 0 uses
+// the count of the cursor.
+// For more information - https://redis.io/commands/ft.cursor-read/
+func (c cmdable) FTCursorRead(ctx context.Context, index string, cursorID int64, count int) *FTAggregateCmd {
+	args := []interface{}{"FT.CURSOR", "READ", index, cursorID}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+	cmd := NewFTAggregateCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTCursorDel deletes the cursor.
+// For more information - https://redis.io/commands/ft.cursor-del/
+func (c cmdable) FTCursorDel(ctx context.Context, index string, cursorID int64) *StatusCmd {
+	args := []interface{}{"FT.CURSOR", "DEL", index, cursorID}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+//------------------------------------------------------------------------------
+
+// Document is a document returned by FT.SEARCH. Score, Payload and SortKey
+// are set only if requested.
+type Document struct {
+	ID      string
+	Score   *float64
+	Payload *string
+	SortKey *string
+	Fields  map[string]string
+}
+
+type FTSearchResult struct {
+	Total int64
+	Docs  []Document
+}
+
+type FTSearchCmd struct {
+	baseCmd
+
+	val     FTSearchResult
+	options *FTSearchOptions
+}
+
+var _ Cmder = (*FTSearchCmd)(nil)
+
+func NewFTSearchCmd(ctx context.Context, args ...interface{}) *FTSearchCmd {
+	return &FTSearchCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+		options: &FTSearchOptions{},
+	}
+}
+
+func (cmd *FTSearchCmd) SetVal(val FTSearchResult) {
+	cmd.val = val
+}
+
+func (cmd *FTSearchCmd) Val() FTSearchResult {
+	return cmd.val
+}
+
+func (cmd *FTSearchCmd) Result() (FTSearchResult, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *FTSearchCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *FTSearchCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+
+	res, err := parseFTSearch(v, cmd.options)
+	if err != nil {
+		return err
+	}
+	cmd.val = res
+	return nil
+}
+
+func parseFTSearch(v interface{}, options *FTSearchOptions) (FTSearchResult, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		return parseFTSearchRESP3(v)
+	case []interface{}:
+		return parseFTSearchRESP2(v, options)
+	default:
+		return FTSearchResult{}, fmt.Errorf("redis: unexpected FT.SEARCH reply type %T", v)
+	}
+}
+
+func parseFTSearchRESP2(reply []interface{}, options *FTSearchOptions) (FTSearchResult, error) {
+	var res FTSearchResult
+	if len(reply) == 0 {
+		return res, errors.New("redis: empty FT.SEARCH reply")
+	}
+
+	total, err := toInt64(reply[0])
+	if err != nil {
+		return res, err
+	}
+	res.Total = total
+
+	for i := 1; i < len(reply); {
+		id, err := toString(reply[i])
+		if err != nil {
+			return res, err
+		}
+		i++
+		doc := Document{ID: id}
+
+		if options.WithScores && i < len(reply) {
+			score := reply[i]
+			if explained, ok := score.([]interface{}); ok && len(explained) > 0 {
+				score = explained[0]
+			}
+			f, err := toFloat64(score)
+			if err != nil {
+				return res, err
+			}
+			doc.Score = &f
+			i++
+		}
+		if options.WithPayloads && i < len(reply) {
+			if s, ok := reply[i].(string); ok {
+				doc.Payload = &s
+			}
+			i++
+		}
+		if options.WithSortKeys && i < len(reply) {
+			if s, ok := reply[i].(string); ok {
+				doc.SortKey = &s
+			}
+			i++
+		}
+		if !options.NoContent && i < len(reply) {
+			doc.Fields = searchFields(reply[i])
+			i++
+		}
+
+		res.Docs = append(res.Docs, doc)
+	}
+	return res, nil
+}
+
+func parseFTSearchRESP3(reply map[interface{}]interface{}) (FTSearchResult, error) {
+	var res FTSearchResult
+
+	total, err := toInt64(reply["total_results"])
+	if err != nil {
+		return res, err
+	}
+	res.Total = total
+
+	results, _ := reply["results"].([]interface{})
+	res.Docs = make([]Document, 0, len(results))
+	for _, result := range results {
+		m, ok := result.(map[interface{}]interface{})
+		if !ok {
+			return res, fmt.Errorf("redis: unexpected FT.SEARCH result type %T", result)
+		}
+
+		var doc Document
+		doc.ID, _ = m["id"].(string)
+		if score, ok := m["score"]; ok {
+			if explained, ok := score.([]interface{}); ok && len(explained) > 0 {
+				score = explained[0]
+			}
+			f, err := toFloat64(score)
+			if err != nil {
+				return res, err
+			}
+			doc.Score = &f
+		}
+		if payload, ok := m["payload"].(string); ok {
+			doc.Payload = &payload
+		}
+		if sortKey, ok := m["sortkey"].(string); ok {
+			doc.SortKey = &sortKey
+		}
+		if fields, ok := m["extra_attributes"]; ok {
+			doc.Fields = searchFields(fields)
+		}
+
+		res.Docs = append(res.Docs, doc)
+	}
+	return res, nil
+}
+
+// searchFields converts the fields reply, which is a flat list of names and values
+// in RESP2 and a map in RESP3.
+func searchFields(v interface{}) map[string]string {
+	m := replyMap(v)
+	fields := make(map[string]string, len(m))
+	for k, v := range m {
+		switch v := v.(type) {
+		case string:
+			fields[k] = v
+		case nil:
+		default:
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+	return fields
+}
+
+//------------------------------------------------------------------------------
+
+type AggregateRow struct {
+	Fields map[string]interface{}
+}
+
+// FTAggregateResult is the result of FT.AGGREGATE. CursorID is 0
+// when there are no more rows to read or the aggregation has no cursor.
+type FTAggregateResult struct {
+	Total    int64
+	Rows     []AggregateRow
+	CursorID int64
+}
+
+type FTAggregateCmd struct {
+	baseCmd
+
+	val FTAggregateResult
+}
+
+var _ Cmder = (*FTAggregateCmd)(nil)
+
+func NewFTAggregateCmd(ctx context.Context, args ...interface{}) *FTAggregateCmd {
+	return &FTAggregateCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *FTAggregateCmd) SetVal(val FTAggregateResult) {
+	cmd.val = val
+}
+
+func (cmd *FTAggregateCmd) Val() FTAggregateResult {
+	return cmd.val
+}
+
+func (cmd *FTAggregateCmd) Result() (FTAggregateResult, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *FTAggregateCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *FTAggregateCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	res, err := parseFTAggregate(v)
+	if err != nil {
+		return err
+	}
+	cmd.val = res
+	return nil
+}
+
+func parseFTAggregate(v interface{}) (FTAggregateResult, error) {
+	var res FTAggregateResult
+
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		total, err := toInt64(v["total_results"])
+		if err != nil {
+			return res, err
+		}
+		res.Total = total
+
+		results, _ := v["results"].([]interface{})
+		res.Rows = make([]AggregateRow, 0, len(results))
+		for _, result := range results {
+			m, ok := result.(map[interface{}]interface{})
+			if !ok {
+				return res, fmt.Errorf("redis: unexpected FT.AGGREGATE result type %T", result)
+			}
+			res.Rows = append(res.Rows, AggregateRow{Fields: replyMap(m["extra_attributes"])})
+		}
+		return res, nil
+	case []interface{}:
+		if len(v) == 0 {
+			return res, errors.New("redis: empty FT.AGGREGATE reply")
+		}
+		if len(v) == 2 && isCursorReply(v[0]) {
+			// The reply with a cursor: [result, cursor id].
+			res, err := parseFTAggregate(v[0])
+			if err != nil {
+				return res, err
+			}
+			res.CursorID, err = toInt64(v[1])
+			return res, err
+		}
+
+		total, err := toInt64(v[0])
+		if err != nil {
+			return res, err
+		}
+		res.Total = total
+
+		res.Rows = make([]AggregateRow, 0, len(v)-1)
+		for _, row := range v[1:] {
+			res.Rows = append(res.Rows, AggregateRow{Fields: replyMap(row)})
+		}
+		return res, nil
+	default:
+		return res, fmt.Errorf("redis: unexpected FT.AGGREGATE reply type %T", v)
+	}
+}
+
+func isCursorReply(v interface{}) bool {
+	switch v.(type) {
+	case []interface{}, map[interface{}]interface{}:
+		return true
+	default:
+		return false
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type FTAttribute struct {
+	Identifier string
+	Attribute  string
+	Type       string
+	Weight     float64
+	Sortable   bool
+	NoStem     bool
+	NoIndex    bool
+	UNF        bool
+}
+
+type FTIndexDefinition struct {
+	KeyType      string
+	Prefixes     []string
+	Filter       string
+	DefaultScore float64
+}
+
+type FTInfoResult struct {
+	IndexName            string
+	IndexOptions         []string
+	IndexDefinition      FTIndexDefinition
+	Attributes           []FTAttribute
+	NumDocs              int64
+	MaxDocID             int64
+	NumTerms             int64
+	NumRecords           int64
+	Indexing             bool
+	PercentIndexed       float64
+	HashIndexingFailures int64
+}
+
+type FTInfoCmd struct {
+	baseCmd
+
+	val FTInfoResult
+}
+
+var _ Cmder = (*FTInfoCmd)(nil)
+
+func NewFTInfoCmd(ctx context.Context, args ...interface{}) *FTInfoCmd {
+	return &FTInfoCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *FTInfoCmd) SetVal(val FTInfoResult) {
+	cmd.val = val
+}
+
+func (cmd *FTInfoCmd) Val() FTInfoResult {
+	return cmd.val
+}
+
+func (cmd *FTInfoCmd) Result() (FTInfoResult, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *FTInfoCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *FTInfoCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	m := replyMap(v)
+	if m == nil {
+		return fmt.Errorf("redis: unexpected FT.INFO reply type %T", v)
+	}
+
+	var res FTInfoResult
+	res.IndexName, _ = m["index_name"].(string)
+	res.IndexOptions = searchStrings(m["index_options"])
+
+	def := replyMap(m["index_definition"])
+	res.IndexDefinition.KeyType, _ = def["key_type"].(string)
+	res.IndexDefinition.Prefixes = searchStrings(def["prefixes"])
+	res.IndexDefinition.Filter, _ = def["filter"].(string)
+	res.IndexDefinition.DefaultScore, _ = toFloat64(def["default_score"])
+
+	attrs, _ := m["attributes"].([]interface{})
+	for _, attr := range attrs {
+		res.Attributes = append(res.Attributes, parseFTAttribute(attr))
+	}
+
+	res.NumDocs, _ = toInt64(m["num_docs"])
+	res.MaxDocID, _ = toInt64(m["max_doc_id"])
+	res.NumTerms, _ = toInt64(m["num_terms"])
+	res.NumRecords, _ = toInt64(m["num_records"])
+	indexing, _ := toInt64(m["indexing"])
+	res.Indexing = indexing != 0
+	res.PercentIndexed, _ = toFloat64(m["percent_indexed"])
+	res.HashIndexingFailures, _ = toInt64(m["hash_indexing_failures"])
+
+	cmd.val = res
+	return nil
+}
+
+// parseFTAttribute parses the attribute that is a list of names and values
+// followed by the flags, e.g. SORTABLE, in RESP2 and a map with the flags in RESP3.
+func parseFTAttribute(v interface{}) FTAttribute {
+	var attr FTAttribute
+	flags := make(map[string]bool)
+
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		for k, val := range v {
+			attr.set(fmt.Sprint(k), val)
+		}
+		for _, flag := range searchStrings(v["flags"]) {
+			flags[flag] = true
+		}
+	case []interface{}:
+		for i := 0; i < len(v); i++ {
+			name, _ := v[i].(string)
+			switch name {
+			case "identifier", "attribute", "type", "WEIGHT", "SEPARATOR":
+				if i+1 < len(v) {
+					attr.set(name, v[i+1])
+					i++
+				}
+			default:
+				flags[name] = true
+			}
+		}
+	}
+
+	attr.Sortable = flags["SORTABLE"]
+	attr.NoStem = flags["NOSTEM"]
+	attr.NoIndex = flags["NOINDEX"]
+	attr.UNF = flags["UNF"]
+	return attr
+}
+
+func (attr *FTAttribute) set(name string, v interface{}) {
+	switch name {
+	case "identifier":
+		attr.Identifier, _ = v.(string)
+	case "attribute":
+		attr.Attribute, _ = v.(string)
+	case "type":
+		attr.Type, _ = v.(string)
+	case "WEIGHT":
+		attr.Weight, _ = toFloat64(v)
+	}
+}
+
+func searchStrings(v interface{}) []string {
+	vals, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	ss := make([]string, 0, len(vals))
+	for _, val := range vals {
+		ss = append(ss, fmt.Sprint(val))
+	}
+	return ss
+}
+
+//------------------------------------------------------------------------------
+
+type FTSuggestion struct {
+	Suggestion string
+	Score      float64
+	Payload    string
+}
+
+type FTSuggestionSliceCmd struct {
+	baseCmd
+
+	val          []FTSuggestion
+	withScores   bool
+	withPayloads bool
+}
+
+var _ Cmder = (*FTSuggestionSliceCmd)(nil)
+
+func NewFTSuggestionSliceCmd(ctx context.Context, args ...interface{}) *FTSuggestionSliceCmd {
+	return &FTSuggestionSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *FTSuggestionSliceCmd) SetVal(val []FTSuggestion) {
+	cmd.val = val
+}
+
+func (cmd *FTSuggestionSliceCmd) Val() []FTSuggestion {
+	return cmd.val
+}
+
+func (cmd *FTSuggestionSliceCmd) Result() ([]FTSuggestion, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *FTSuggestionSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *FTSuggestionSliceCmd) readReply(rd *proto.Reader) error {
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		if err == Nil {
+			cmd.val = nil
+			return nil
+		}
+		return err
+	}
+
+	step := 1
+	if cmd.withScores {
+		step++
+	}
+	if cmd.withPayloads {
+		step++
+	}
+
+	cmd.val = make([]FTSuggestion, 0, n/step)
+	for i := 0; i < n; i += step {
+		var sug FTSuggestion
+		if sug.Suggestion, err = rd.ReadString(); err != nil {
+			return err
+		}
+		if cmd.withScores {
+			if sug.Score, err = rd.ReadFloat(); err != nil {
+				return err
+			}
+		}
+		if cmd.withPayloads {
+			if sug.Payload, err = rd.ReadString(); err != nil && err != Nil {
+				return err
+			}
+		}
+		cmd.val = append(cmd.val, sug)
+	}
+	return nil
+}
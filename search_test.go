@@ -0,0 +1,204 @@
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func waitForIndexing(client *redis.Client, index string) {
+	Eventually(func() bool {
+		info, err := client.FTInfo(context.Background(), index).Result()
+		Expect(err).NotTo(HaveOccurred())
+		return !info.Indexing
+	}).WithTimeout(5 * time.Second).Should(BeTrue())
+}
+
+var _ = Describe("Search commands", Label("search"), func() {
+	ctx := context.TODO()
+
+	for _, protocol := range []int{2, 3} {
+		protocol := protocol
+
+		Describe(fmt.Sprintf("RESP%d", protocol), func() {
+			var client *redis.Client
+
+			BeforeEach(func() {
+				client = redis.NewClient(&redis.Options{Addr: ":6379", Protocol: protocol})
+				Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				Expect(client.Close()).NotTo(HaveOccurred())
+			})
+
+			createHashIndex := func() {
+				err := client.FTCreate(ctx, "idx", &redis.FTCreateOptions{OnHash: true, Prefix: []string{"doc:"}},
+					&redis.FieldSchema{FieldName: "title", FieldType: redis.SearchFieldTypeText, Sortable: true},
+					&redis.FieldSchema{FieldName: "tags", FieldType: redis.SearchFieldTypeTag},
+					&redis.FieldSchema{FieldName: "price", FieldType: redis.SearchFieldTypeNumeric, Sortable: true},
+				).Err()
+				Expect(err).NotTo(HaveOccurred())
+
+				client.HSet(ctx, "doc:1", "title", "hello world", "tags", "a,b", "price", 10)
+				client.HSet(ctx, "doc:2", "title", "hello redis", "tags", "b", "price", 20)
+				client.HSet(ctx, "doc:3", "title", "goodbye", "tags", "c", "price", 30)
+				waitForIndexing(client, "idx")
+			}
+
+			It("should FTCreate and FTInfo", Label("ft.create", "ft.info"), func() {
+				createHashIndex()
+
+				info, err := client.FTInfo(ctx, "idx").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.IndexName).To(Equal("idx"))
+				Expect(info.IndexDefinition.KeyType).To(Equal("HASH"))
+				Expect(info.IndexDefinition.Prefixes).To(Equal([]string{"doc:"}))
+				Expect(info.NumDocs).To(Equal(int64(3)))
+				Expect(info.Attributes).To(HaveLen(3))
+				Expect(info.Attributes[0].Identifier).To(Equal("title"))
+				Expect(info.Attributes[0].Type).To(Equal("TEXT"))
+				Expect(info.Attributes[0].Sortable).To(BeTrue())
+			})
+
+			It("should FTCreate with invalid fields", Label("ft.create"), func() {
+				err := client.FTCreate(ctx, "idx", nil).Err()
+				Expect(err).To(MatchError("redis: FT.CREATE requires at least one field"))
+
+				err = client.FTCreate(ctx, "idx", nil,
+					&redis.FieldSchema{FieldName: "v", FieldType: redis.SearchFieldTypeVector}).Err()
+				Expect(err).To(MatchError("redis: VECTOR field requires either FLAT or HNSW options"))
+			})
+
+			It("should FTSearch", Label("ft.search"), func() {
+				createHashIndex()
+
+				res, err := client.FTSearchWithArgs(ctx, "idx", "hello", &redis.FTSearchOptions{
+					WithScores: true,
+					SortBy:     []redis.FTSearchSortBy{{FieldName: "price", Asc: true}},
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Total).To(Equal(int64(2)))
+				Expect(res.Docs).To(HaveLen(2))
+				Expect(res.Docs[0].ID).To(Equal("doc:1"))
+				Expect(res.Docs[0].Score).NotTo(BeNil())
+				Expect(res.Docs[0].Fields["title"]).To(Equal("hello world"))
+				Expect(res.Docs[1].ID).To(Equal("doc:2"))
+
+				res, err = client.FTSearchWithArgs(ctx, "idx", "@price:[$min $max]", &redis.FTSearchOptions{
+					NoContent: true,
+					Params:    map[string]interface{}{"min": 15, "max": 40},
+					Dialect:   2,
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Total).To(Equal(int64(2)))
+				Expect(res.Docs[0].Fields).To(BeEmpty())
+
+				res, err = client.FTSearchWithArgs(ctx, "idx", "@tags:{b}", &redis.FTSearchOptions{
+					Return: []redis.FTSearchReturn{{FieldName: "title", As: "t"}},
+					Limit:  1,
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Total).To(Equal(int64(2)))
+				Expect(res.Docs).To(HaveLen(1))
+				Expect(res.Docs[0].Fields).To(HaveKey("t"))
+			})
+
+			It("should FTSearch JSON documents", Label("ft.search", "json"), func() {
+				err := client.FTCreate(ctx, "jidx", &redis.FTCreateOptions{OnJSON: true, Prefix: []string{"user:"}},
+					&redis.FieldSchema{FieldName: "$.name", As: "name", FieldType: redis.SearchFieldTypeText},
+					&redis.FieldSchema{FieldName: "$.age", As: "age", FieldType: redis.SearchFieldTypeNumeric},
+				).Err()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(client.JSONSet(ctx, "user:1", "$", `{"name":"john","age":30}`).Err()).NotTo(HaveOccurred())
+				waitForIndexing(client, "jidx")
+
+				res, err := client.FTSearch(ctx, "jidx", "@age:[20 40]").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Total).To(Equal(int64(1)))
+				Expect(res.Docs[0].Fields["$"]).To(Equal(`{"name":"john","age":30}`))
+			})
+
+			It("should FTAggregate", Label("ft.aggregate"), func() {
+				createHashIndex()
+
+				res, err := client.FTAggregateWithArgs(ctx, "idx", "*", &redis.FTAggregateOptions{
+					GroupBy: []redis.FTAggregateGroupBy{{
+						Fields: []string{"@tags"},
+						Reduce: []redis.FTAggregateReducer{{Reducer: "SUM", Args: []interface{}{"@price"}, As: "total"}},
+					}},
+					SortBy: []redis.FTAggregateSortBy{{FieldName: "@total", Desc: true}},
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Rows).To(HaveLen(3))
+				Expect(res.Rows[0].Fields).To(HaveKeyWithValue("tags", "c"))
+			})
+
+			It("should FTAggregate with cursor", Label("ft.aggregate", "ft.cursor"), func() {
+				createHashIndex()
+
+				res, err := client.FTAggregateWithArgs(ctx, "idx", "*", &redis.FTAggregateOptions{
+					Load:              []redis.FTAggregateLoad{{Field: "@title"}},
+					WithCursor:        true,
+					WithCursorOptions: &redis.FTAggregateWithCursor{Count: 2},
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Rows).To(HaveLen(2))
+				Expect(res.CursorID).NotTo(BeZero())
+
+				res, err = client.FTCursorRead(ctx, "idx", res.CursorID, 0).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Rows).To(HaveLen(1))
+			})
+
+			It("should FTAlter, FTAlias and FTDropIndex", Label("ft.alter", "ft.alias", "ft.dropindex"), func() {
+				createHashIndex()
+
+				err := client.FTAlter(ctx, "idx", false,
+					&redis.FieldSchema{FieldName: "body", FieldType: redis.SearchFieldTypeText}).Err()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(client.FTAliasAdd(ctx, "idx", "alias").Err()).NotTo(HaveOccurred())
+				Expect(client.FTSearch(ctx, "alias", "hello").Val().Total).To(Equal(int64(2)))
+				Expect(client.FTAliasUpdate(ctx, "idx", "alias").Err()).NotTo(HaveOccurred())
+				Expect(client.FTAliasDel(ctx, "alias").Err()).NotTo(HaveOccurred())
+
+				Expect(client.FTDropIndexWithArgs(ctx, "idx", &redis.FTDropIndexOptions{DeleteDocs: true}).Err()).NotTo(HaveOccurred())
+				Expect(client.Exists(ctx, "doc:1").Val()).To(BeZero())
+			})
+
+			It("should FTExplain", Label("ft.explain"), func() {
+				createHashIndex()
+
+				plan, err := client.FTExplain(ctx, "idx", "@title:hello").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan).To(ContainSubstring("hello"))
+			})
+
+			It("should FTSugAdd and FTSugGet", Label("ft.sugadd", "ft.sugget"), func() {
+				Expect(client.FTSugAdd(ctx, "sug", "hello", 1).Val()).To(Equal(int64(1)))
+				Expect(client.FTSugAddWithArgs(ctx, "sug", "help", 2, &redis.FTSugAddOptions{Payload: "p"}).Val()).To(Equal(int64(2)))
+				Expect(client.FTSugLen(ctx, "sug").Val()).To(Equal(int64(2)))
+
+				sugs, err := client.FTSugGetWithArgs(ctx, "sug", "hel", &redis.FTSugGetOptions{
+					WithScores:   true,
+					WithPayloads: true,
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sugs).To(HaveLen(2))
+				Expect(sugs[0].Suggestion).To(Equal("help"))
+				Expect(sugs[0].Score).To(BeNumerically(">", 0))
+				Expect(sugs[0].Payload).To(Equal("p"))
+
+				Expect(client.FTSugDel(ctx, "sug", "help").Val()).To(BeTrue())
+				Expect(client.FTSugGet(ctx, "sug", "hel").Val()).To(HaveLen(1))
+			})
+		})
+	}
+})
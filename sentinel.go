@@ -41,6 +41,12 @@ type FailoverOptions struct {
 	// Allows routing read-only commands to the random master or replica node.
 	// This option only works with NewFailoverClusterClient.
 	RouteRandomly bool
+	// ReadRouter selects the master or replica node for read-only commands,
+	// e.g. ZoneReadRouter. This option only works with NewFailoverClusterClient.
+	ReadRouter ReadRouter
+	// NodeMetadata returns the metadata of the node, e.g. MetadataAvailabilityZone,
+	// for ReadRouter. This option only works with NewFailoverClusterClient.
+	NodeMetadata func(addr string) map[string]string
 
 	// Route all commands to replica read-only nodes.
 	ReplicaOnly bool
@@ -67,6 +73,7 @@ type FailoverOptions struct {
 	ReadTimeout           time.Duration
 	WriteTimeout          time.Duration
 	ContextTimeoutEnabled bool
+	Codec                 Codec
 
 	PoolFIFO bool
 
@@ -78,6 +85,8 @@ type FailoverOptions struct {
 	ConnMaxLifetime time.Duration
 
 	TLSConfig *tls.Config
+
+	OnPush map[string]PushNotificationHandler
 }
 
 func (opt *FailoverOptions) clientOptions() *Options {
@@ -101,6 +110,7 @@ func (opt *FailoverOptions) clientOptions() *Options {
 		ReadTimeout:           opt.ReadTimeout,
 		WriteTimeout:          opt.WriteTimeout,
 		ContextTimeoutEnabled: opt.ContextTimeoutEnabled,
+		Codec:                 opt.Codec,
 
 		PoolFIFO:        opt.PoolFIFO,
 		PoolSize:        opt.PoolSize,
@@ -111,6 +121,7 @@ func (opt *FailoverOptions) clientOptions() *Options {
 		ConnMaxLifetime: opt.ConnMaxLifetime,
 
 		TLSConfig: opt.TLSConfig,
+		OnPush:    opt.OnPush,
 	}
 }
 
@@ -161,6 +172,8 @@ func (opt *FailoverOptions) clusterOptions() *ClusterOptions {
 
 		RouteByLatency: opt.RouteByLatency,
 		RouteRandomly:  opt.RouteRandomly,
+		ReadRouter:     opt.ReadRouter,
+		NodeMetadata:   opt.NodeMetadata,
 
 		MinRetryBackoff: opt.MinRetryBackoff,
 		MaxRetryBackoff: opt.MaxRetryBackoff,
@@ -168,6 +181,7 @@ func (opt *FailoverOptions) clusterOptions() *ClusterOptions {
 		DialTimeout:  opt.DialTimeout,
 		ReadTimeout:  opt.ReadTimeout,
 		WriteTimeout: opt.WriteTimeout,
+		Codec:        opt.Codec,
 
 		PoolFIFO:        opt.PoolFIFO,
 		PoolSize:        opt.PoolSize,
@@ -178,6 +192,7 @@ func (opt *FailoverOptions) clusterOptions() *ClusterOptions {
 		ConnMaxLifetime: opt.ConnMaxLifetime,
 
 		TLSConfig: opt.TLSConfig,
+		OnPush:    opt.OnPush,
 	}
 }
 
@@ -191,6 +206,9 @@ func NewFailoverClient(failoverOpt *FailoverOptions) *Client {
 	if failoverOpt.RouteRandomly {
 		panic("to route commands randomly, use NewFailoverClusterClient")
 	}
+	if failoverOpt.ReadRouter != nil {
+		panic("to route commands with ReadRouter, use NewFailoverClusterClient")
+	}
 
 	sentinelAddrs := make([]string, len(failoverOpt.SentinelAddrs))
 	copy(sentinelAddrs, failoverOpt.SentinelAddrs)
@@ -0,0 +1,1047 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+type timeseriesCmdable interface {
+	TSAdd(ctx context.Context, key string, timestamp interface{}, value float64) *IntCmd
+	TSAddWithArgs(ctx context.Context, key string, timestamp interface{}, value float64, options *TSOptions) *IntCmd
+	TSAlter(ctx context.Context, key string, options *TSAlterOptions) *StatusCmd
+	TSCreate(ctx context.Context, key string) *StatusCmd
+	TSCreateWithArgs(ctx context.Context, key string, options *TSOptions) *StatusCmd
+	TSCreateRule(ctx context.Context, sourceKey, destKey string, aggregator Aggregator, bucketDuration int) *StatusCmd
+	TSCreateRuleWithArgs(ctx context.Context, sourceKey, destKey string, aggregator Aggregator, bucketDuration int, options *TSCreateRuleOptions) *StatusCmd
+	TSDecrBy(ctx context.Context, key string, value float64) *IntCmd
+	TSDecrByWithArgs(ctx context.Context, key string, value float64, options *TSIncrDecrOptions) *IntCmd
+	TSDel(ctx context.Context, key string, fromTimestamp, toTimestamp int64) *IntCmd
+	TSDeleteRule(ctx context.Context, sourceKey, destKey string) *StatusCmd
+	TSGet(ctx context.Context, key string) *TSTimestampValueCmd
+	TSGetWithArgs(ctx context.Context, key string, options *TSGetOptions) *TSTimestampValueCmd
+	TSIncrBy(ctx context.Context, key string, value float64) *IntCmd
+	TSIncrByWithArgs(ctx context.Context, key string, value float64, options *TSIncrDecrOptions) *IntCmd
+	TSInfo(ctx context.Context, key string) *TSInfoCmd
+	TSMAdd(ctx context.Context, samples ...TSKeySample) *TSSampleResultSliceCmd
+	TSMGet(ctx context.Context, filters []string) *TSSeriesSliceCmd
+	TSMGetWithArgs(ctx context.Context, filters []string, options *TSMGetOptions) *TSSeriesSliceCmd
+	TSMRange(ctx context.Context, fromTimestamp, toTimestamp interface{}, filters []string) *TSSeriesSliceCmd
+	TSMRangeWithArgs(ctx context.Context, fromTimestamp, toTimestamp interface{}, filters []string, options *TSMRangeOptions) *TSSeriesSliceCmd
+	TSMRevRange(ctx context.Context, fromTimestamp, toTimestamp interface{}, filters []string) *TSSeriesSliceCmd
+	TSMRevRangeWithArgs(ctx context.Context, fromTimestamp, toTimestamp interface{}, filters []string, options *TSMRangeOptions) *TSSeriesSliceCmd
+	TSQueryIndex(ctx context.Context, filters []string) *StringSliceCmd
+	TSRange(ctx context.Context, key string, fromTimestamp, toTimestamp interface{}) *TSTimestampValueSliceCmd
+	TSRangeWithArgs(ctx context.Context, key string, fromTimestamp, toTimestamp interface{}, options *TSRangeOptions) *TSTimestampValueSliceCmd
+	TSRevRange(ctx context.Context, key string, fromTimestamp, toTimestamp interface{}) *TSTimestampValueSliceCmd
+	TSRevRangeWithArgs(ctx context.Context, key string, fromTimestamp, toTimestamp interface{}, options *TSRangeOptions) *TSTimestampValueSliceCmd
+}
+
+type Aggregator int
+
+const (
+	Invalid Aggregator = iota
+	Avg
+	Sum
+	Min
+	Max
+	Range
+	Count
+	First
+	Last
+	StdP
+	StdS
+	VarP
+	VarS
+	Twa
+)
+
+func (a Aggregator) String() string {
+	switch a {
+	case Avg:
+		return "AVG"
+	case Sum:
+		return "SUM"
+	case Min:
+		return "MIN"
+	case Max:
+		return "MAX"
+	case Range:
+		return "RANGE"
+	case Count:
+		return "COUNT"
+	case First:
+		return "FIRST"
+	case Last:
+		return "LAST"
+	case StdP:
+		return "STD.P"
+	case StdS:
+		return "STD.S"
+	case VarP:
+		return "VAR.P"
+	case VarS:
+		return "VAR.S"
+	case Twa:
+		return "TWA"
+	default:
+		return ""
+	}
+}
+
+// TSOptions configures a time series. DuplicatePolicy is sent as ON_DUPLICATE by TSAddWithArgs.
+type TSOptions struct {
+	Retention       int
+	ChunkSize       int
+	Encoding        string
+	DuplicatePolicy string
+	Labels          map[string]string
+}
+
+type TSAlterOptions struct {
+	Retention       int
+	ChunkSize       int
+	DuplicatePolicy string
+	Labels          map[string]string
+}
+
+type TSIncrDecrOptions struct {
+	Timestamp    interface{}
+	Retention    int
+	ChunkSize    int
+	Uncompressed bool
+	Labels       map[string]string
+}
+
+type TSCreateRuleOptions struct {
+	AlignTimestamp int64
+}
+
+type TSGetOptions struct {
+	Latest bool
+}
+
+type TSMGetOptions struct {
+	Latest         bool
+	WithLabels     bool
+	SelectedLabels []string
+}
+
+type TSRangeOptions struct {
+	Latest          bool
+	FilterByTS      []int64
+	FilterByValue   []float64 // min and max
+	Count           int
+	Align           interface{}
+	Aggregator      Aggregator
+	BucketDuration  int
+	BucketTimestamp string
+	Empty           bool
+}
+
+type TSMRangeOptions struct {
+	TSRangeOptions
+	WithLabels     bool
+	SelectedLabels []string
+	GroupByLabel   string
+	Reducer        Aggregator
+}
+
+// TSKeySample is a sample of the time series stored at Key.
+type TSKeySample struct {
+	Key       string
+	Timestamp interface{}
+	Value     float64
+}
+
+// tsTimestamp converts time.Time to milliseconds. Other values, e.g. "*", "-" or "+",
+// are sent as is.
+func tsTimestamp(timestamp interface{}) interface{} {
+	if tm, ok := timestamp.(time.Time); ok {
+		return tm.UnixMilli()
+	}
+	return timestamp
+}
+
+func appendTSLabels(args []interface{}, labels map[string]string) []interface{} {
+	if len(labels) == 0 {
+		return args
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args = append(args, "LABELS")
+	for _, name := range names {
+		args = append(args, name, labels[name])
+	}
+	return args
+}
+
+func appendTSFilters(args []interface{}, filters []string) []interface{} {
+	args = append(args, "FILTER")
+	for _, filter := range filters {
+		args = append(args, filter)
+	}
+	return args
+}
+
+func appendTSRangeOptions(args []interface{}, options *TSRangeOptions) []interface{} {
+	if options.Latest {
+		args = append(args, "LATEST")
+	}
+	if len(options.FilterByTS) > 0 {
+		args = append(args, "FILTER_BY_TS")
+		for _, ts := range options.FilterByTS {
+			args = append(args, ts)
+		}
+	}
+	if len(options.FilterByValue) == 2 {
+		args = append(args, "FILTER_BY_VALUE", options.FilterByValue[0], options.FilterByValue[1])
+	}
+	if options.Count > 0 {
+		args = append(args, "COUNT", options.Count)
+	}
+	if options.Aggregator != Invalid {
+		if options.Align != nil {
+			args = append(args, "ALIGN", tsTimestamp(options.Align))
+		}
+		args = append(args, "AGGREGATION", options.Aggregator.String(), options.BucketDuration)
+		if options.BucketTimestamp != "" {
+			args = append(args, "BUCKETTIMESTAMP", options.BucketTimestamp)
+		}
+		if options.Empty {
+			args = append(args, "EMPTY")
+		}
+	}
+	return args
+}
+
+//------------------------------------------------------------------------------
+
+// TSAdd adds the sample to the time series. The timestamp is in milliseconds,
+// time.Time or "*" for the server time. It returns the timestamp of the sample.
+// For more information - https://redis.io/commands/ts.add/
+func (c cmdable) TSAdd(ctx context.Context, key string, timestamp interface{}, value float64) *IntCmd {
+	return c.TSAddWithArgs(ctx, key, timestamp, value, nil)
+}
+
+// TSAddWithArgs adds the sample to the time series, creating the time series
+// with the options if it does not exist.
+// For more information - https://redis.io/commands/ts.add/
+func (c cmdable) TSAddWithArgs(
+	ctx context.Context, key string, timestamp interface{}, value float64, options *TSOptions,
+) *IntCmd {
+	args := []interface{}{"TS.ADD", key, tsTimestamp(timestamp), value}
+	if options != nil {
+		if options.Retention > 0 {
+			args = append(args, "RETENTION", options.Retention)
+		}
+		if options.Encoding != "" {
+			args = append(args, "ENCODING", options.Encoding)
+		}
+		if options.ChunkSize > 0 {
+			args = append(args, "CHUNK_SIZE", options.ChunkSize)
+		}
+		if options.DuplicatePolicy != "" {
+			args = append(args, "ON_DUPLICATE", options.DuplicatePolicy)
+		}
+		args = appendTSLabels(args, options.Labels)
+	}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSCreate creates an empty time series.
+// For more information - https://redis.io/commands/ts.create/
+func (c cmdable) TSCreate(ctx context.Context, key string) *StatusCmd {
+	return c.TSCreateWithArgs(ctx, key, nil)
+}
+
+// TSCreateWithArgs creates an empty time series with the options.
+// For more information - https://redis.io/commands/ts.create/
+func (c cmdable) TSCreateWithArgs(ctx context.Context, key string, options *TSOptions) *StatusCmd {
+	args := []interface{}{"TS.CREATE", key}
+	if options != nil {
+		if options.Retention > 0 {
+			args = append(args, "RETENTION", options.Retention)
+		}
+		if options.Encoding != "" {
+			args = append(args, "ENCODING", options.Encoding)
+		}
+		if options.ChunkSize > 0 {
+			args = append(args, "CHUNK_SIZE", options.ChunkSize)
+		}
+		if options.DuplicatePolicy != "" {
+			args = append(args, "DUPLICATE_POLICY", options.DuplicatePolicy)
+		}
+		args = appendTSLabels(args, options.Labels)
+	}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSAlter updates the options of the time series. The labels replace the existing labels.
+// For more information - https://redis.io/commands/ts.alter/
+func (c cmdable) TSAlter(ctx context.Context, key string, options *TSAlterOptions) *StatusCmd {
+	args := []interface{}{"TS.ALTER", key}
+	if options != nil {
+		if options.Retention > 0 {
+			args = append(args, "RETENTION", options.Retention)
+		}
+		if options.ChunkSize > 0 {
+			args = append(args, "CHUNK_SIZE", options.ChunkSize)
+		}
+		if options.DuplicatePolicy != "" {
+			args = append(args, "DUPLICATE_POLICY", options.DuplicatePolicy)
+		}
+		args = appendTSLabels(args, options.Labels)
+	}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSCreateRule creates a compaction rule that aggregates the samples of the source
+// time series into the buckets of the destination time series.
+// For more information - https://redis.io/commands/ts.createrule/
+func (c cmdable) TSCreateRule(
+	ctx context.Context, sourceKey, destKey string, aggregator Aggregator, bucketDuration int,
+) *StatusCmd {
+	return c.TSCreateRuleWithArgs(ctx, sourceKey, destKey, aggregator, bucketDuration, nil)
+}
+
+// TSCreateRuleWithArgs creates a compaction rule with the options.
+// For more information - https://redis.io/commands/ts.createrule/
+func (c cmdable) TSCreateRuleWithArgs(
+	ctx context.Context, sourceKey, destKey string, aggregator Aggregator, bucketDuration int,
+	options *TSCreateRuleOptions,
+) *StatusCmd {
+	args := []interface{}{"TS.CREATERULE", sourceKey, destKey, "AGGREGATION", aggregator.String(), bucketDuration}
+	if options != nil && options.AlignTimestamp != 0 {
+		args = append(args, options.AlignTimestamp)
+	}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSDeleteRule deletes the compaction rule.
+// For more information - https://redis.io/commands/ts.deleterule/
+func (c cmdable) TSDeleteRule(ctx context.Context, sourceKey, destKey string) *StatusCmd {
+	args := []interface{}{"TS.DELETERULE", sourceKey, destKey}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSIncrBy increases the value of the latest sample by the value.
+// It returns the timestamp of the sample.
+// For more information - https://redis.io/commands/ts.incrby/
+func (c cmdable) TSIncrBy(ctx context.Context, key string, value float64) *IntCmd {
+	return c.TSIncrByWithArgs(ctx, key, value, nil)
+}
+
+// TSIncrByWithArgs increases the value of the sample at the timestamp of the options
+// by the value.
+// For more information - https://redis.io/commands/ts.incrby/
+func (c cmdable) TSIncrByWithArgs(ctx context.Context, key string, value float64, options *TSIncrDecrOptions) *IntCmd {
+	return c.tsIncrDecrBy(ctx, "TS.INCRBY", key, value, options)
+}
+
+// TSDecrBy decreases the value of the latest sample by the value.
+// It returns the timestamp of the sample.
+// For more information - https://redis.io/commands/ts.decrby/
+func (c cmdable) TSDecrBy(ctx context.Context, key string, value float64) *IntCmd {
+	return c.TSDecrByWithArgs(ctx, key, value, nil)
+}
+
+// TSDecrByWithArgs decreases the value of the sample at the timestamp of the options
+// by the value.
+// For more information - https://redis.io/commands/ts.decrby/
+func (c cmdable) TSDecrByWithArgs(ctx context.Context, key string, value float64, options *TSIncrDecrOptions) *IntCmd {
+	return c.tsIncrDecrBy(ctx, "TS.DECRBY", key, value, options)
+}
+
+func (c cmdable) tsIncrDecrBy(
+	ctx context.Context, name, key string, value float64, options *TSIncrDecrOptions,
+) *IntCmd {
+	args := []interface{}{name, key, value}
+	if options != nil {
+		if options.Timestamp != nil {
+			args = append(args, "TIMESTAMP", tsTimestamp(options.Timestamp))
+		}
+		if options.Retention > 0 {
+			args = append(args, "RETENTION", options.Retention)
+		}
+		if options.Uncompressed {
+			args = append(args, "UNCOMPRESSED")
+		}
+		if options.ChunkSize > 0 {
+			args = append(args, "CHUNK_SIZE", options.ChunkSize)
+		}
+		args = appendTSLabels(args, options.Labels)
+	}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSDel deletes the samples between the timestamps, inclusive.
+// It returns the number of the deleted samples.
+// For more information - https://redis.io/commands/ts.del/
+func (c cmdable) TSDel(ctx context.Context, key string, fromTimestamp, toTimestamp int64) *IntCmd {
+	args := []interface{}{"TS.DEL", key, fromTimestamp, toTimestamp}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSMAdd adds the samples to the time series. It returns the timestamp or
+// the error of each sample, e.g. when the key does not exist.
+// For more information - https://redis.io/commands/ts.madd/
+func (c cmdable) TSMAdd(ctx context.Context, samples ...TSKeySample) *TSSampleResultSliceCmd {
+	args := make([]interface{}, 1, 3*len(samples)+1)
+	args[0] = "TS.MADD"
+	for _, sample := range samples {
+		args = append(args, sample.Key, tsTimestamp(sample.Timestamp), sample.Value)
+	}
+	cmd := NewTSSampleResultSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSGet returns the latest sample of the time series.
+// It returns redis.Nil if the time series has no samples.
+// For more information - https://redis.io/commands/ts.get/
+func (c cmdable) TSGet(ctx context.Context, key string) *TSTimestampValueCmd {
+	return c.TSGetWithArgs(ctx, key, nil)
+}
+
+// TSGetWithArgs returns the latest sample of the time series. The option Latest
+// returns the latest, possibly partial, bucket of a compaction.
+// For more information - https://redis.io/commands/ts.get/
+func (c cmdable) TSGetWithArgs(ctx context.Context, key string, options *TSGetOptions) *TSTimestampValueCmd {
+	args := []interface{}{"TS.GET", key}
+	if options != nil && options.Latest {
+		args = append(args, "LATEST")
+	}
+	cmd := NewTSTimestampValueCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSMGet returns the latest samples of the time series matching the filters.
+// For more information - https://redis.io/commands/ts.mget/
+func (c cmdable) TSMGet(ctx context.Context, filters []string) *TSSeriesSliceCmd {
+	return c.TSMGetWithArgs(ctx, filters, nil)
+}
+
+// TSMGetWithArgs returns the latest samples of the time series matching the filters.
+// For more information - https://redis.io/commands/ts.mget/
+func (c cmdable) TSMGetWithArgs(ctx context.Context, filters []string, options *TSMGetOptions) *TSSeriesSliceCmd {
+	args := []interface{}{"TS.MGET"}
+	if options != nil {
+		if options.Latest {
+			args = append(args, "LATEST")
+		}
+		if options.WithLabels {
+			args = append(args, "WITHLABELS")
+		}
+		if len(options.SelectedLabels) > 0 {
+			args = append(args, "SELECTED_LABELS")
+			for _, label := range options.SelectedLabels {
+				args = append(args, label)
+			}
+		}
+	}
+	args = appendTSFilters(args, filters)
+	cmd := NewTSSeriesSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSRange returns the samples between the timestamps, inclusive.
+// The timestamps "-" and "+" are the earliest and the latest samples.
+// For more information - https://redis.io/commands/ts.range/
+func (c cmdable) TSRange(ctx context.Context, key string, fromTimestamp, toTimestamp interface{}) *TSTimestampValueSliceCmd {
+	return c.TSRangeWithArgs(ctx, key, fromTimestamp, toTimestamp, nil)
+}
+
+// TSRangeWithArgs returns the samples between the timestamps, filtered or aggregated
+// according to the options.
+// For more information - https://redis.io/commands/ts.range/
+func (c cmdable) TSRangeWithArgs(
+	ctx context.Context, key string, fromTimestamp, toTimestamp interface{}, options *TSRangeOptions,
+) *TSTimestampValueSliceCmd {
+	return c.tsRange(ctx, "TS.RANGE", key, fromTimestamp, toTimestamp, options)
+}
+
+// TSRevRange returns the samples between the timestamps in the reverse order.
+// For more information - https://redis.io/commands/ts.revrange/
+func (c cmdable) TSRevRange(ctx context.Context, key string, fromTimestamp, toTimestamp interface{}) *TSTimestampValueSliceCmd {
+	return c.TSRevRangeWithArgs(ctx, key, fromTimestamp, toTimestamp, nil)
+}
+
+// TSRevRangeWithArgs returns the samples between the timestamps in the reverse order,
+// filtered or aggregated according to the options.
+// For more information - https://redis.io/commands/ts.revrange/
+func (c cmdable) TSRevRangeWithArgs(
+	ctx context.Context, key string, fromTimestamp, toTimestamp interface{}, options *TSRangeOptions,
+) *TSTimestampValueSliceCmd {
+	return c.tsRange(ctx, "TS.REVRANGE", key, fromTimestamp, toTimestamp, options)
+}
+
+func (c cmdable) tsRange(
+	ctx context.Context, name, key string, fromTimestamp, toTimestamp interface{}, options *TSRangeOptions,
+) *TSTimestampValueSliceCmd {
+	args := []interface{}{name, key, tsTimestamp(fromTimestamp), tsTimestamp(toTimestamp)}
+	if options != nil {
+		args = appendTSRangeOptions(args, options)
+	}
+	cmd := NewTSTimestampValueSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSMRange returns the samples between the timestamps of the time series matching the filters.
+// For more information - https://redis.io/commands/ts.mrange/
+func (c cmdable) TSMRange(ctx context.Context, fromTimestamp, toTimestamp interface{}, filters []string) *TSSeriesSliceCmd {
+	return c.TSMRangeWithArgs(ctx, fromTimestamp, toTimestamp, filters, nil)
+}
+
+// TSMRangeWithArgs returns the samples between the timestamps of the time series
+// matching the filters. With GroupByLabel, the series are grouped by the label value
+// and the samples of each group are reduced using Reducer.
+// For more information - https://redis.io/commands/ts.mrange/
+func (c cmdable) TSMRangeWithArgs(
+	ctx context.Context, fromTimestamp, toTimestamp interface{}, filters []string, options *TSMRangeOptions,
+) *TSSeriesSliceCmd {
+	return c.tsMRange(ctx, "TS.MRANGE", fromTimestamp, toTimestamp, filters, options)
+}
+
+// TSMRevRange returns the samples between the timestamps of the time series matching
+// the filters in the reverse order.
+// For more information - https://redis.io/commands/ts.mrevrange/
+func (c cmdable) TSMRevRange(ctx context.Context, fromTimestamp, toTimestamp interface{}, filters []string) *TSSeriesSliceCmd {
+	return c.TSMRevRangeWithArgs(ctx, fromTimestamp, toTimestamp, filters, nil)
+}
+
+// TSMRevRangeWithArgs returns the samples between the timestamps of the time series
+// matching the filters in the reverse order.
+// For more information - https://redis.io/commands/ts.mrevrange/
+func (c cmdable) TSMRevRangeWithArgs(
+	ctx context.Context, fromTimestamp, toTimestamp interface{}, filters []string, options *TSMRangeOptions,
+) *TSSeriesSliceCmd {
+	return c.tsMRange(ctx, "TS.MREVRANGE", fromTimestamp, toTimestamp, filters, options)
+}
+
+func (c cmdable) tsMRange(
+	ctx context.Context, name string, fromTimestamp, toTimestamp interface{}, filters []string, options *TSMRangeOptions,
+) *TSSeriesSliceCmd {
+	args := []interface{}{name, tsTimestamp(fromTimestamp), tsTimestamp(toTimestamp)}
+	if options == nil {
+		options = &TSMRangeOptions{}
+	}
+	args = appendTSRangeOptions(args, &options.TSRangeOptions)
+	if options.WithLabels {
+		args = append(args, "WITHLABELS")
+	}
+	if len(options.SelectedLabels) > 0 {
+		args = append(args, "SELECTED_LABELS")
+		for _, label := range options.SelectedLabels {
+			args = append(args, label)
+		}
+	}
+	// The filters precede GROUPBY.
+	args = appendTSFilters(args, filters)
+	if options.GroupByLabel != "" {
+		args = append(args, "GROUPBY", options.GroupByLabel, "REDUCE", options.Reducer.String())
+	}
+	cmd := NewTSSeriesSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSQueryIndex returns the keys of the time series matching the filters.
+// For more information - https://redis.io/commands/ts.queryindex/
+func (c cmdable) TSQueryIndex(ctx context.Context, filters []string) *StringSliceCmd {
+	args := []interface{}{"TS.QUERYINDEX"}
+	for _, filter := range filters {
+		args = append(args, filter)
+	}
+	cmd := NewStringSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSInfo returns information about the time series.
+// For more information - https://redis.io/commands/ts.info/
+func (c cmdable) TSInfo(ctx context.Context, key string) *TSInfoCmd {
+	args := []interface{}{"TS.INFO", key}
+	cmd := NewTSInfoCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+//------------------------------------------------------------------------------
+
+type TSTimestampValue struct {
+	Timestamp int64
+	Value     float64
+}
+
+// parseTSSample parses the sample reply [timestamp, value]. The value is
+// a string in RESP2 and a double in RESP3.
+func parseTSSample(v interface{}) (TSTimestampValue, bool, error) {
+	pair, ok := v.([]interface{})
+	if !ok {
+		return TSTimestampValue{}, false, fmt.Errorf("redis: unexpected sample type %T", v)
+	}
+	if len(pair) == 0 {
+		return TSTimestampValue{}, false, nil
+	}
+	if len(pair) != 2 {
+		return TSTimestampValue{}, false, fmt.Errorf("redis: got %d elements in the sample, wanted 2", len(pair))
+	}
+
+	ts, err := toInt64(pair[0])
+	if err != nil {
+		return TSTimestampValue{}, false, err
+	}
+	val, err := toFloat64(pair[1])
+	if err != nil {
+		return TSTimestampValue{}, false, err
+	}
+	return TSTimestampValue{Timestamp: ts, Value: val}, true, nil
+}
+
+func parseTSSamples(v interface{}) ([]TSTimestampValue, error) {
+	vals, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected samples type %T", v)
+	}
+	samples := make([]TSTimestampValue, 0, len(vals))
+	for _, val := range vals {
+		sample, ok, err := parseTSSample(val)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+// parseTSLabels parses the labels that are [name, value] pairs in RESP2 and a map in RESP3.
+func parseTSLabels(v interface{}) map[string]string {
+	labels := make(map[string]string)
+	switch v := v.(type) {
+	case []interface{}:
+		for _, pair := range v {
+			if pair, ok := pair.([]interface{}); ok && len(pair) == 2 {
+				if val, ok := pair[1].(string); ok {
+					labels[fmt.Sprint(pair[0])] = val
+				} else {
+					labels[fmt.Sprint(pair[0])] = ""
+				}
+			}
+		}
+	case map[interface{}]interface{}:
+		for k, val := range v {
+			if val, ok := val.(string); ok {
+				labels[fmt.Sprint(k)] = val
+			} else {
+				labels[fmt.Sprint(k)] = ""
+			}
+		}
+	}
+	return labels
+}
+
+type TSTimestampValueCmd struct {
+	baseCmd
+
+	val TSTimestampValue
+}
+
+var _ Cmder = (*TSTimestampValueCmd)(nil)
+
+func NewTSTimestampValueCmd(ctx context.Context, args ...interface{}) *TSTimestampValueCmd {
+	return &TSTimestampValueCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *TSTimestampValueCmd) SetVal(val TSTimestampValue) {
+	cmd.val = val
+}
+
+func (cmd *TSTimestampValueCmd) Val() TSTimestampValue {
+	return cmd.val
+}
+
+func (cmd *TSTimestampValueCmd) Result() (TSTimestampValue, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *TSTimestampValueCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *TSTimestampValueCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	sample, ok, err := parseTSSample(v)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return Nil
+	}
+	cmd.val = sample
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// TSSampleResult is the result of adding a sample with TS.MADD.
+// Err is set if the sample was not added.
+type TSSampleResult struct {
+	Timestamp int64
+	Err       error
+}
+
+// TSSampleResultSliceCmd is a reply of TS.MADD. The errors of the samples
+// do not fail the command and are returned in the results.
+type TSSampleResultSliceCmd struct {
+	baseCmd
+
+	val []TSSampleResult
+}
+
+var _ Cmder = (*TSSampleResultSliceCmd)(nil)
+
+func NewTSSampleResultSliceCmd(ctx context.Context, args ...interface{}) *TSSampleResultSliceCmd {
+	return &TSSampleResultSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *TSSampleResultSliceCmd) SetVal(val []TSSampleResult) {
+	cmd.val = val
+}
+
+func (cmd *TSSampleResultSliceCmd) Val() []TSSampleResult {
+	return cmd.val
+}
+
+func (cmd *TSSampleResultSliceCmd) Result() ([]TSSampleResult, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *TSSampleResultSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *TSSampleResultSliceCmd) readReply(rd *proto.Reader) error {
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	cmd.val = make([]TSSampleResult, n)
+	for i := 0; i < len(cmd.val); i++ {
+		ts, err := rd.ReadInt()
+		if err != nil && !isRedisError(err) {
+			return err
+		}
+		cmd.val[i] = TSSampleResult{Timestamp: ts, Err: err}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type TSTimestampValueSliceCmd struct {
+	baseCmd
+
+	val []TSTimestampValue
+}
+
+var _ Cmder = (*TSTimestampValueSliceCmd)(nil)
+
+func NewTSTimestampValueSliceCmd(ctx context.Context, args ...interface{}) *TSTimestampValueSliceCmd {
+	return &TSTimestampValueSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *TSTimestampValueSliceCmd) SetVal(val []TSTimestampValue) {
+	cmd.val = val
+}
+
+func (cmd *TSTimestampValueSliceCmd) Val() []TSTimestampValue {
+	return cmd.val
+}
+
+func (cmd *TSTimestampValueSliceCmd) Result() ([]TSTimestampValue, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *TSTimestampValueSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *TSTimestampValueSliceCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	cmd.val, err = parseTSSamples(v)
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+// TSSeries is a time series returned by TS.MGET and TS.MRANGE.
+// The labels are returned only if requested. For the grouped series,
+// Key is "<label>=<value>".
+type TSSeries struct {
+	Key     string
+	Labels  map[string]string
+	Samples []TSTimestampValue
+}
+
+type TSSeriesSliceCmd struct {
+	baseCmd
+
+	val []TSSeries
+}
+
+var _ Cmder = (*TSSeriesSliceCmd)(nil)
+
+func NewTSSeriesSliceCmd(ctx context.Context, args ...interface{}) *TSSeriesSliceCmd {
+	return &TSSeriesSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *TSSeriesSliceCmd) SetVal(val []TSSeries) {
+	cmd.val = val
+}
+
+func (cmd *TSSeriesSliceCmd) Val() []TSSeries {
+	return cmd.val
+}
+
+func (cmd *TSSeriesSliceCmd) Result() ([]TSSeries, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *TSSeriesSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *TSSeriesSliceCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+
+	// RESP2 replies are [key, labels, ...] arrays, and RESP3 replies
+	// are maps of the keys to [labels, ...] arrays. The samples are always last.
+	var series []TSSeries
+	switch v := v.(type) {
+	case []interface{}:
+		series = make([]TSSeries, 0, len(v))
+		for _, elem := range v {
+			vals, ok := elem.([]interface{})
+			if !ok || len(vals) < 3 {
+				return fmt.Errorf("redis: unexpected time series reply %v", elem)
+			}
+			s, err := parseTSSeries(fmt.Sprint(vals[0]), vals[1:])
+			if err != nil {
+				return err
+			}
+			series = append(series, s)
+		}
+	case map[interface{}]interface{}:
+		series = make([]TSSeries, 0, len(v))
+		for key, elem := range v {
+			vals, ok := elem.([]interface{})
+			if !ok || len(vals) < 2 {
+				return fmt.Errorf("redis: unexpected time series reply %v", elem)
+			}
+			s, err := parseTSSeries(fmt.Sprint(key), vals)
+			if err != nil {
+				return err
+			}
+			series = append(series, s)
+		}
+		sort.Slice(series, func(i, j int) bool {
+			return series[i].Key < series[j].Key
+		})
+	default:
+		return fmt.Errorf("redis: unexpected time series reply type %T", v)
+	}
+
+	cmd.val = series
+	return nil
+}
+
+func parseTSSeries(key string, vals []interface{}) (TSSeries, error) {
+	s := TSSeries{
+		Key:    key,
+		Labels: parseTSLabels(vals[0]),
+	}
+
+	last := vals[len(vals)-1]
+	if sample, ok, err := parseTSSample(last); err == nil {
+		// TS.MGET returns a single sample.
+		if ok {
+			s.Samples = []TSTimestampValue{sample}
+		}
+		return s, nil
+	}
+
+	samples, err := parseTSSamples(last)
+	if err != nil {
+		return s, err
+	}
+	s.Samples = samples
+	return s, nil
+}
+
+//------------------------------------------------------------------------------
+
+type TSRule struct {
+	DestKey        string
+	BucketDuration int64
+	Aggregator     string
+	AlignTimestamp int64
+}
+
+type TSInfo struct {
+	TotalSamples    int64
+	MemoryUsage     int64
+	FirstTimestamp  int64
+	LastTimestamp   int64
+	RetentionTime   int64
+	ChunkCount      int64
+	ChunkSize       int64
+	ChunkType       string
+	DuplicatePolicy string
+	Labels          map[string]string
+	SourceKey       string
+	Rules           []TSRule
+}
+
+type TSInfoCmd struct {
+	baseCmd
+
+	val TSInfo
+}
+
+var _ Cmder = (*TSInfoCmd)(nil)
+
+func NewTSInfoCmd(ctx context.Context, args ...interface{}) *TSInfoCmd {
+	return &TSInfoCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *TSInfoCmd) SetVal(val TSInfo) {
+	cmd.val = val
+}
+
+func (cmd *TSInfoCmd) Val() TSInfo {
+	return cmd.val
+}
+
+func (cmd *TSInfoCmd) Result() (TSInfo, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *TSInfoCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *TSInfoCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	m := replyMap(v)
+	if m == nil {
+		return fmt.Errorf("redis: unexpected TS.INFO reply type %T", v)
+	}
+
+	var info TSInfo
+	info.TotalSamples, _ = toInt64(m["totalSamples"])
+	info.MemoryUsage, _ = toInt64(m["memoryUsage"])
+	info.FirstTimestamp, _ = toInt64(m["firstTimestamp"])
+	info.LastTimestamp, _ = toInt64(m["lastTimestamp"])
+	info.RetentionTime, _ = toInt64(m["retentionTime"])
+	info.ChunkCount, _ = toInt64(m["chunkCount"])
+	info.ChunkSize, _ = toInt64(m["chunkSize"])
+	info.ChunkType, _ = m["chunkType"].(string)
+	info.DuplicatePolicy, _ = m["duplicatePolicy"].(string)
+	info.Labels = parseTSLabels(m["labels"])
+	info.SourceKey, _ = m["sourceKey"].(string)
+
+	switch rules := m["rules"].(type) {
+	case []interface{}:
+		for _, rule := range rules {
+			if vals, ok := rule.([]interface{}); ok && len(vals) >= 3 {
+				info.Rules = append(info.Rules, parseTSRule(fmt.Sprint(vals[0]), vals[1:]))
+			}
+		}
+	case map[interface{}]interface{}:
+		for key, rule := range rules {
+			if vals, ok := rule.([]interface{}); ok && len(vals) >= 2 {
+				info.Rules = append(info.Rules, parseTSRule(fmt.Sprint(key), vals))
+			}
+		}
+		sort.Slice(info.Rules, func(i, j int) bool {
+			return info.Rules[i].DestKey < info.Rules[j].DestKey
+		})
+	}
+
+	cmd.val = info
+	return nil
+}
+
+func parseTSRule(destKey string, vals []interface{}) TSRule {
+	rule := TSRule{DestKey: destKey}
+	rule.BucketDuration, _ = toInt64(vals[0])
+	rule.Aggregator, _ = vals[1].(string)
+	if len(vals) > 2 {
+		rule.AlignTimestamp, _ = toInt64(vals[2])
+	}
+	return rule
+}
@@ -0,0 +1,179 @@
+package redis_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("TimeSeries commands", Label("timeseries"), func() {
+	ctx := context.TODO()
+
+	for _, protocol := range []int{2, 3} {
+		protocol := protocol
+
+		Describe(fmt.Sprintf("RESP%d", protocol), func() {
+			var client *redis.Client
+
+			BeforeEach(func() {
+				client = redis.NewClient(&redis.Options{Addr: ":6379", Protocol: protocol})
+				Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				Expect(client.Close()).NotTo(HaveOccurred())
+			})
+
+			It("should TSCreate, TSAlter and TSInfo", Label("ts.create", "ts.alter", "ts.info"), func() {
+				err := client.TSCreateWithArgs(ctx, "ts", &redis.TSOptions{
+					Retention:       1000,
+					Encoding:        "UNCOMPRESSED",
+					DuplicatePolicy: "LAST",
+					Labels:          map[string]string{"sensor": "1"},
+				}).Err()
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err := client.TSInfo(ctx, "ts").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.RetentionTime).To(Equal(int64(1000)))
+				Expect(info.ChunkType).To(Equal("uncompressed"))
+				Expect(info.DuplicatePolicy).To(Equal("last"))
+				Expect(info.Labels).To(Equal(map[string]string{"sensor": "1"}))
+
+				err = client.TSAlter(ctx, "ts", &redis.TSAlterOptions{
+					Retention: 2000,
+					Labels:    map[string]string{"sensor": "2"},
+				}).Err()
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err = client.TSInfo(ctx, "ts").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.RetentionTime).To(Equal(int64(2000)))
+				Expect(info.Labels).To(Equal(map[string]string{"sensor": "2"}))
+			})
+
+			It("should TSAdd, TSMAdd and TSGet", Label("ts.add", "ts.madd", "ts.get"), func() {
+				Expect(client.TSAdd(ctx, "ts", 1, 1.5).Val()).To(Equal(int64(1)))
+				Expect(client.TSCreate(ctx, "ts2").Err()).NotTo(HaveOccurred())
+
+				res, err := client.TSMAdd(ctx,
+					redis.TSKeySample{Key: "ts", Timestamp: 2, Value: 2.5},
+					redis.TSKeySample{Key: "missing", Timestamp: 2, Value: 1},
+					redis.TSKeySample{Key: "ts2", Timestamp: 3, Value: 3},
+				).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(HaveLen(3))
+				Expect(res[0]).To(Equal(redis.TSSampleResult{Timestamp: 2}))
+				Expect(res[1].Err).To(HaveOccurred())
+				Expect(res[2]).To(Equal(redis.TSSampleResult{Timestamp: 3}))
+
+				sample, err := client.TSGet(ctx, "ts").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sample).To(Equal(redis.TSTimestampValue{Timestamp: 2, Value: 2.5}))
+
+				Expect(client.TSCreate(ctx, "empty").Err()).NotTo(HaveOccurred())
+				Expect(client.TSGet(ctx, "empty").Err()).To(Equal(redis.Nil))
+			})
+
+			It("should TSIncrBy and TSDecrBy", Label("ts.incrby", "ts.decrby"), func() {
+				_, err := client.TSIncrByWithArgs(ctx, "ts", 5, &redis.TSIncrDecrOptions{Timestamp: 1}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				_, err = client.TSDecrByWithArgs(ctx, "ts", 2, &redis.TSIncrDecrOptions{Timestamp: 1}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(client.TSGet(ctx, "ts").Val().Value).To(Equal(3.0))
+			})
+
+			It("should TSRange and TSRevRange", Label("ts.range", "ts.revrange"), func() {
+				for i := 1; i <= 10; i++ {
+					Expect(client.TSAdd(ctx, "ts", i, float64(i)).Err()).NotTo(HaveOccurred())
+				}
+
+				samples, err := client.TSRange(ctx, "ts", "-", "+").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(samples).To(HaveLen(10))
+				Expect(samples[0]).To(Equal(redis.TSTimestampValue{Timestamp: 1, Value: 1}))
+
+				samples, err = client.TSRangeWithArgs(ctx, "ts", 0, 10, &redis.TSRangeOptions{
+					Aggregator:     redis.Sum,
+					BucketDuration: 5,
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(samples).To(Equal([]redis.TSTimestampValue{
+					{Timestamp: 0, Value: 10},
+					{Timestamp: 5, Value: 35},
+					{Timestamp: 10, Value: 10},
+				}))
+
+				samples, err = client.TSRevRangeWithArgs(ctx, "ts", "-", "+", &redis.TSRangeOptions{
+					FilterByValue: []float64{3, 5},
+					Count:         2,
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(samples).To(Equal([]redis.TSTimestampValue{
+					{Timestamp: 5, Value: 5},
+					{Timestamp: 4, Value: 4},
+				}))
+
+				Expect(client.TSDel(ctx, "ts", 1, 5).Val()).To(Equal(int64(5)))
+			})
+
+			It("should TSMGet, TSMRange and TSQueryIndex", Label("ts.mget", "ts.mrange", "ts.queryindex"), func() {
+				for _, key := range []string{"a", "b"} {
+					err := client.TSCreateWithArgs(ctx, key, &redis.TSOptions{
+						Labels: map[string]string{"type": "temp", "name": key},
+					}).Err()
+					Expect(err).NotTo(HaveOccurred())
+					client.TSAdd(ctx, key, 1, 1)
+					client.TSAdd(ctx, key, 2, 2)
+				}
+
+				Expect(client.TSQueryIndex(ctx, []string{"type=temp"}).Val()).To(ConsistOf("a", "b"))
+
+				series, err := client.TSMGetWithArgs(ctx, []string{"type=temp"}, &redis.TSMGetOptions{
+					SelectedLabels: []string{"name"},
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(series).To(Equal([]redis.TSSeries{
+					{Key: "a", Labels: map[string]string{"name": "a"}, Samples: []redis.TSTimestampValue{{Timestamp: 2, Value: 2}}},
+					{Key: "b", Labels: map[string]string{"name": "b"}, Samples: []redis.TSTimestampValue{{Timestamp: 2, Value: 2}}},
+				}))
+
+				series, err = client.TSMRange(ctx, "-", "+", []string{"name=a"}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(series).To(HaveLen(1))
+				Expect(series[0].Key).To(Equal("a"))
+				Expect(series[0].Samples).To(HaveLen(2))
+
+				series, err = client.TSMRevRangeWithArgs(ctx, "-", "+", []string{"type=temp"}, &redis.TSMRangeOptions{
+					GroupByLabel: "type",
+					Reducer:      redis.Sum,
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(series).To(HaveLen(1))
+				Expect(series[0].Key).To(Equal("type=temp"))
+				Expect(series[0].Samples).To(Equal([]redis.TSTimestampValue{
+					{Timestamp: 2, Value: 4},
+					{Timestamp: 1, Value: 2},
+				}))
+			})
+
+			It("should TSCreateRule and TSDeleteRule", Label("ts.createrule", "ts.deleterule"), func() {
+				Expect(client.TSCreate(ctx, "src").Err()).NotTo(HaveOccurred())
+				Expect(client.TSCreate(ctx, "dst").Err()).NotTo(HaveOccurred())
+				Expect(client.TSCreateRule(ctx, "src", "dst", redis.Avg, 60).Err()).NotTo(HaveOccurred())
+
+				info, err := client.TSInfo(ctx, "src").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Rules).To(Equal([]redis.TSRule{{DestKey: "dst", BucketDuration: 60, Aggregator: "AVG"}}))
+				Expect(client.TSInfo(ctx, "dst").Val().SourceKey).To(Equal("src"))
+
+				Expect(client.TSDeleteRule(ctx, "src", "dst").Err()).NotTo(HaveOccurred())
+				Expect(client.TSInfo(ctx, "src").Val().Rules).To(BeEmpty())
+			})
+		})
+	}
+})
@@ -104,6 +104,7 @@ func (c *Tx) Pipeline() Pipeliner {
 		exec: func(ctx context.Context, cmds []Cmder) error {
 			return c.processPipelineHook(ctx, cmds)
 		},
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
@@ -134,6 +135,7 @@ func (c *Tx) TxPipeline() Pipeliner {
 			cmds = wrapMultiExec(ctx, cmds)
 			return c.processTxPipelineHook(ctx, cmds)
 		},
+		codec: c.opt.Codec,
 	}
 	pipe.init()
 	return &pipe
@@ -0,0 +1,291 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// TypedCmd is the result of a typed command, e.g. Get[T]. The reply of the
+// underlying command is decoded into T when the result is read, so typed commands
+// can be used in pipelines and read after Exec.
+type TypedCmd[T any] struct {
+	cmd    Cmder
+	decode func() (T, error)
+}
+
+func newTypedCmd[T any](cmd Cmder, decode func() (T, error)) *TypedCmd[T] {
+	return &TypedCmd[T]{
+		cmd:    cmd,
+		decode: decode,
+	}
+}
+
+// Cmd returns the underlying command.
+func (cmd *TypedCmd[T]) Cmd() Cmder {
+	return cmd.cmd
+}
+
+func (cmd *TypedCmd[T]) Val() T {
+	val, _ := cmd.Result()
+	return val
+}
+
+func (cmd *TypedCmd[T]) Err() error {
+	_, err := cmd.Result()
+	return err
+}
+
+// Result returns the decoded value. The reply is decoded on every call.
+func (cmd *TypedCmd[T]) Result() (T, error) {
+	if err := cmd.cmd.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+	return cmd.decode()
+}
+
+func (cmd *TypedCmd[T]) String() string {
+	return cmd.cmd.String()
+}
+
+// TypedZ is a sorted set member of type T.
+type TypedZ[T any] struct {
+	Score  float64
+	Member T
+}
+
+//------------------------------------------------------------------------------
+
+type codecCmdable struct {
+	Cmdable
+	codec Codec
+}
+
+// WithCodec returns c that uses the codec in the typed commands, e.g.
+//
+//	redis.Get[User](ctx, redis.WithCodec(rdb, msgpackCodec), "user:1")
+//
+// It also works with pipelines:
+//
+//	pipe := rdb.Pipeline()
+//	user := redis.Get[User](ctx, redis.WithCodec(pipe, msgpackCodec), "user:1")
+//	_, err := pipe.Exec(ctx)
+//	// use user.Result()
+func WithCodec(c Cmdable, codec Codec) Cmdable {
+	return codecCmdable{
+		Cmdable: c,
+		codec:   codec,
+	}
+}
+
+// typedCodec returns the codec set by WithCodec, or the codec of the client
+// (see Options.Codec), which is JSONCodec by default.
+func typedCodec(c Cmdable) Codec {
+	if c, ok := c.(codecCmdable); ok {
+		if c.codec != nil {
+			return c.codec
+		}
+		return typedCodec(c.Cmdable)
+	}
+	if c, ok := c.(interface{ valueCodec() Codec }); ok {
+		if codec := c.valueCodec(); codec != nil {
+			return codec
+		}
+	}
+	return JSONCodec{}
+}
+
+func (c *baseClient) valueCodec() Codec    { return c.opt.Codec }
+func (c *ClusterClient) valueCodec() Codec { return c.opt.Codec }
+func (c *Ring) valueCodec() Codec          { return c.opt.Codec }
+func (c *Pipeline) valueCodec() Codec      { return c.codec }
+
+func marshalValue(codec Codec, v interface{}) (interface{}, error) {
+	if proto.Writable(v) {
+		return v, nil
+	}
+	return codec.Marshal(v)
+}
+
+func unmarshalValue[T any](codec Codec, s string) (T, error) {
+	var v T
+	if proto.Scannable(&v) {
+		err := proto.Scan([]byte(s), &v)
+		return v, err
+	}
+	err := codec.Unmarshal([]byte(s), &v)
+	return v, err
+}
+
+func unmarshalValues[T any](codec Codec, ss []string) ([]T, error) {
+	vals := make([]T, len(ss))
+	for i, s := range ss {
+		v, err := unmarshalValue[T](codec, s)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Get returns the value of the key decoded into T.
+func Get[T any](ctx context.Context, c Cmdable, key string) *TypedCmd[T] {
+	codec := typedCodec(c)
+	cmd := c.Get(ctx, key)
+	return newTypedCmd(cmd, func() (T, error) {
+		return unmarshalValue[T](codec, cmd.Val())
+	})
+}
+
+// Set sets the key to the encoded value.
+func Set[T any](ctx context.Context, c Cmdable, key string, value T, expiration time.Duration) *StatusCmd {
+	arg, err := marshalValue(typedCodec(c), value)
+	if err != nil {
+		cmd := NewStatusCmd(ctx, "set", key)
+		cmd.SetErr(err)
+		return cmd
+	}
+	return c.Set(ctx, key, arg, expiration)
+}
+
+// HGet returns the value of the hash field decoded into T.
+func HGet[T any](ctx context.Context, c Cmdable, key, field string) *TypedCmd[T] {
+	codec := typedCodec(c)
+	cmd := c.HGet(ctx, key, field)
+	return newTypedCmd(cmd, func() (T, error) {
+		return unmarshalValue[T](codec, cmd.Val())
+	})
+}
+
+// HGetAll returns the hash fields and their values decoded into T.
+func HGetAll[T any](ctx context.Context, c Cmdable, key string) *TypedCmd[map[string]T] {
+	codec := typedCodec(c)
+	cmd := c.HGetAll(ctx, key)
+	return newTypedCmd(cmd, func() (map[string]T, error) {
+		m := make(map[string]T, len(cmd.Val()))
+		for field, s := range cmd.Val() {
+			v, err := unmarshalValue[T](codec, s)
+			if err != nil {
+				return nil, err
+			}
+			m[field] = v
+		}
+		return m, nil
+	})
+}
+
+// HSet sets the hash fields to the encoded values.
+func HSet[T any](ctx context.Context, c Cmdable, key string, values map[string]T) *IntCmd {
+	codec := typedCodec(c)
+	args := make([]interface{}, 0, 2*len(values))
+	for field, value := range values {
+		arg, err := marshalValue(codec, value)
+		if err != nil {
+			cmd := NewIntCmd(ctx, "hset", key)
+			cmd.SetErr(err)
+			return cmd
+		}
+		args = append(args, field, arg)
+	}
+	return c.HSet(ctx, key, args...)
+}
+
+// LIndex returns the list element decoded into T.
+func LIndex[T any](ctx context.Context, c Cmdable, key string, index int64) *TypedCmd[T] {
+	codec := typedCodec(c)
+	cmd := c.LIndex(ctx, key, index)
+	return newTypedCmd(cmd, func() (T, error) {
+		return unmarshalValue[T](codec, cmd.Val())
+	})
+}
+
+// LRange returns the list elements decoded into T.
+func LRange[T any](ctx context.Context, c Cmdable, key string, start, stop int64) *TypedCmd[[]T] {
+	codec := typedCodec(c)
+	cmd := c.LRange(ctx, key, start, stop)
+	return newTypedCmd(cmd, func() ([]T, error) {
+		return unmarshalValues[T](codec, cmd.Val())
+	})
+}
+
+// LPush prepends the encoded values to the list.
+func LPush[T any](ctx context.Context, c Cmdable, key string, values ...T) *IntCmd {
+	args, err := marshalValues(typedCodec(c), values)
+	if err != nil {
+		cmd := NewIntCmd(ctx, "lpush", key)
+		cmd.SetErr(err)
+		return cmd
+	}
+	return c.LPush(ctx, key, args...)
+}
+
+// RPush appends the encoded values to the list.
+func RPush[T any](ctx context.Context, c Cmdable, key string, values ...T) *IntCmd {
+	args, err := marshalValues(typedCodec(c), values)
+	if err != nil {
+		cmd := NewIntCmd(ctx, "rpush", key)
+		cmd.SetErr(err)
+		return cmd
+	}
+	return c.RPush(ctx, key, args...)
+}
+
+func marshalValues[T any](codec Codec, values []T) ([]interface{}, error) {
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		arg, err := marshalValue(codec, value)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// ZAdd adds the members with the encoded values to the sorted set.
+func ZAdd[T any](ctx context.Context, c Cmdable, key string, members ...TypedZ[T]) *IntCmd {
+	codec := typedCodec(c)
+	zs := make([]Z, len(members))
+	for i, member := range members {
+		arg, err := marshalValue(codec, member.Member)
+		if err != nil {
+			cmd := NewIntCmd(ctx, "zadd", key)
+			cmd.SetErr(err)
+			return cmd
+		}
+		zs[i] = Z{Score: member.Score, Member: arg}
+	}
+	return c.ZAdd(ctx, key, zs...)
+}
+
+// ZRange returns the sorted set members decoded into T.
+func ZRange[T any](ctx context.Context, c Cmdable, key string, start, stop int64) *TypedCmd[[]T] {
+	codec := typedCodec(c)
+	cmd := c.ZRange(ctx, key, start, stop)
+	return newTypedCmd(cmd, func() ([]T, error) {
+		return unmarshalValues[T](codec, cmd.Val())
+	})
+}
+
+// ZRangeWithScores returns the sorted set members decoded into T with their scores.
+func ZRangeWithScores[T any](ctx context.Context, c Cmdable, key string, start, stop int64) *TypedCmd[[]TypedZ[T]] {
+	codec := typedCodec(c)
+	cmd := c.ZRangeWithScores(ctx, key, start, stop)
+	return newTypedCmd(cmd, func() ([]TypedZ[T], error) {
+		zs := make([]TypedZ[T], len(cmd.Val()))
+		for i, z := range cmd.Val() {
+			s, _ := z.Member.(string)
+			v, err := unmarshalValue[T](codec, s)
+			if err != nil {
+				return nil, err
+			}
+			zs[i] = TypedZ[T]{Score: z.Score, Member: v}
+		}
+		return zs, nil
+	})
+}
@@ -0,0 +1,199 @@
+package redis_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type typedUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+// upperCodec wraps JSON to check that the codec passed to WithCodec is used.
+type upperCodec struct {
+	marshaled int
+}
+
+func (c *upperCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshaled++
+	return json.Marshal(v)
+}
+
+func (c *upperCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type failingCodec struct{}
+
+func (failingCodec) Marshal(v interface{}) ([]byte, error) {
+	return nil, errors.New("can't marshal")
+}
+
+func (failingCodec) Unmarshal(data []byte, v interface{}) error {
+	return errors.New("can't unmarshal")
+}
+
+var _ = Describe("Typed commands", func() {
+	ctx := context.Background()
+	var client *redis.Client
+
+	BeforeEach(func() {
+		client = redis.NewClient(redisOptions())
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should Get and Set", func() {
+		Expect(redis.Set(ctx, client, "user", typedUser{Name: "john", Age: 42}, 0).Err()).NotTo(HaveOccurred())
+		Expect(client.Get(ctx, "user").Val()).To(Equal(`{"name":"john","age":42}`))
+
+		user, err := redis.Get[typedUser](ctx, client, "user").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user).To(Equal(typedUser{Name: "john", Age: 42}))
+
+		ptr, err := redis.Get[*typedUser](ctx, client, "user").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ptr).To(Equal(&typedUser{Name: "john", Age: 42}))
+
+		_, err = redis.Get[typedUser](ctx, client, "missing").Result()
+		Expect(err).To(Equal(redis.Nil))
+	})
+
+	It("should use the built-in encoding of primitives", func() {
+		Expect(redis.Set(ctx, client, "n", 42, 0).Err()).NotTo(HaveOccurred())
+		Expect(client.Get(ctx, "n").Val()).To(Equal("42"))
+		Expect(redis.Get[int64](ctx, client, "n").Val()).To(Equal(int64(42)))
+		Expect(redis.Get[string](ctx, client, "n").Val()).To(Equal("42"))
+
+		Expect(redis.Set(ctx, client, "d", time.Second, 0).Err()).NotTo(HaveOccurred())
+		Expect(redis.Get[time.Duration](ctx, client, "d").Val()).To(Equal(time.Second))
+	})
+
+	It("should decode after pipeline Exec", func() {
+		pipe := client.Pipeline()
+		redis.Set(ctx, pipe, "user", typedUser{Name: "john"}, 0)
+		get := redis.Get[typedUser](ctx, pipe, "user")
+		Expect(get.Val()).To(Equal(typedUser{}))
+
+		_, err := pipe.Exec(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get.Val()).To(Equal(typedUser{Name: "john"}))
+		Expect(get.Cmd().(*redis.StringCmd).Val()).To(Equal(`{"name":"john","age":0}`))
+	})
+
+	It("should use the codec", func() {
+		codec := new(upperCodec)
+		c := redis.WithCodec(client, codec)
+		Expect(redis.Set(ctx, c, "user", typedUser{Name: "john"}, 0).Err()).NotTo(HaveOccurred())
+		Expect(codec.marshaled).To(Equal(1))
+
+		c = redis.WithCodec(client, failingCodec{})
+		err := redis.Set(ctx, c, "user", typedUser{}, 0).Err()
+		Expect(err).To(MatchError("can't marshal"))
+		err = redis.Get[typedUser](ctx, c, "user").Err()
+		Expect(err).To(MatchError("can't unmarshal"))
+	})
+
+	It("should HGet, HGetAll and HSet", func() {
+		err := redis.HSet(ctx, client, "users", map[string]typedUser{
+			"1": {Name: "john"},
+			"2": {Name: "jane"},
+		}).Err()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(redis.HGet[typedUser](ctx, client, "users", "1").Val()).To(Equal(typedUser{Name: "john"}))
+		Expect(redis.HGetAll[typedUser](ctx, client, "users").Val()).To(Equal(map[string]typedUser{
+			"1": {Name: "john"},
+			"2": {Name: "jane"},
+		}))
+	})
+
+	It("should LIndex, LRange, LPush and RPush", func() {
+		Expect(redis.RPush(ctx, client, "list", typedUser{Name: "b"}, typedUser{Name: "c"}).Err()).NotTo(HaveOccurred())
+		Expect(redis.LPush(ctx, client, "list", typedUser{Name: "a"}).Err()).NotTo(HaveOccurred())
+
+		Expect(redis.LIndex[typedUser](ctx, client, "list", 1).Val()).To(Equal(typedUser{Name: "b"}))
+		Expect(redis.LRange[typedUser](ctx, client, "list", 0, -1).Val()).To(Equal([]typedUser{
+			{Name: "a"}, {Name: "b"}, {Name: "c"},
+		}))
+	})
+
+	It("should ZAdd, ZRange and ZRangeWithScores", func() {
+		err := redis.ZAdd(ctx, client, "zset",
+			redis.TypedZ[typedUser]{Score: 2, Member: typedUser{Name: "b"}},
+			redis.TypedZ[typedUser]{Score: 1, Member: typedUser{Name: "a"}},
+		).Err()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(redis.ZRange[typedUser](ctx, client, "zset", 0, -1).Val()).To(Equal([]typedUser{
+			{Name: "a"}, {Name: "b"},
+		}))
+		Expect(redis.ZRangeWithScores[typedUser](ctx, client, "zset", 0, 0).Val()).To(Equal([]redis.TypedZ[typedUser]{
+			{Score: 1, Member: typedUser{Name: "a"}},
+		}))
+	})
+})
+
+var _ = Describe("Codec", func() {
+	ctx := context.Background()
+	var client *redis.Client
+
+	BeforeEach(func() {
+		opt := redisOptions()
+		opt.Codec = redis.GobCodec{}
+		client = redis.NewClient(opt)
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should marshal arguments and scan replies", func() {
+		user := typedUser{Name: "john", Age: 42}
+		Expect(client.Set(ctx, "user", user, 0).Err()).NotTo(HaveOccurred())
+		Expect(client.Set(ctx, "n", 42, 0).Err()).NotTo(HaveOccurred())
+
+		var got typedUser
+		Expect(client.Get(ctx, "user").Scan(&got)).NotTo(HaveOccurred())
+		Expect(got).To(Equal(user))
+
+		got = typedUser{}
+		Expect(client.Do(ctx, "get", "user").Scan(&got)).NotTo(HaveOccurred())
+		Expect(got).To(Equal(user))
+
+		var n int
+		Expect(client.Get(ctx, "n").Scan(&n)).NotTo(HaveOccurred())
+		Expect(n).To(Equal(42))
+	})
+
+	It("should be used by pipelines and typed commands", func() {
+		pipe := client.Pipeline()
+		pipe.Set(ctx, "user", typedUser{Name: "john"}, 0)
+		get := redis.Get[typedUser](ctx, pipe, "user")
+		_, err := pipe.Exec(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get.Val()).To(Equal(typedUser{Name: "john"}))
+
+		Expect(redis.Get[typedUser](ctx, client, "user").Val()).To(Equal(typedUser{Name: "john"}))
+	})
+
+	It("should fail without a codec", func() {
+		rdb := redis.NewClient(redisOptions())
+		defer rdb.Close()
+
+		err := rdb.Set(ctx, "user", typedUser{}, 0).Err()
+		Expect(err).To(MatchError("redis: can't marshal redis_test.typedUser (implement encoding.BinaryMarshaler)"))
+	})
+})
@@ -40,6 +40,7 @@ type UniversalOptions struct {
 	ReadTimeout           time.Duration
 	WriteTimeout          time.Duration
 	ContextTimeoutEnabled bool
+	Codec                 Codec
 
 	// PoolFIFO uses FIFO mode for each node connection pool GET/PUT (default LIFO).
 	PoolFIFO bool
@@ -53,12 +54,16 @@ type UniversalOptions struct {
 
 	TLSConfig *tls.Config
 
+	OnPush map[string]PushNotificationHandler
+
 	// Only cluster clients.
 
 	MaxRedirects   int
 	ReadOnly       bool
 	RouteByLatency bool
 	RouteRandomly  bool
+	ReadRouter     ReadRouter
+	NodeMetadata   func(addr string) map[string]string
 
 	// The sentinel master name.
 	// Only failover clients.
@@ -86,6 +91,8 @@ func (o *UniversalOptions) Cluster() *ClusterOptions {
 		ReadOnly:       o.ReadOnly,
 		RouteByLatency: o.RouteByLatency,
 		RouteRandomly:  o.RouteRandomly,
+		ReadRouter:     o.ReadRouter,
+		NodeMetadata:   o.NodeMetadata,
 
 		MaxRetries:      o.MaxRetries,
 		MinRetryBackoff: o.MinRetryBackoff,
@@ -95,6 +102,7 @@ func (o *UniversalOptions) Cluster() *ClusterOptions {
 		ReadTimeout:           o.ReadTimeout,
 		WriteTimeout:          o.WriteTimeout,
 		ContextTimeoutEnabled: o.ContextTimeoutEnabled,
+		Codec:                 o.Codec,
 
 		PoolFIFO: o.PoolFIFO,
 
@@ -106,6 +114,7 @@ func (o *UniversalOptions) Cluster() *ClusterOptions {
 		ConnMaxLifetime: o.ConnMaxLifetime,
 
 		TLSConfig: o.TLSConfig,
+		OnPush:    o.OnPush,
 	}
 }
 
@@ -138,6 +147,7 @@ func (o *UniversalOptions) Failover() *FailoverOptions {
 		ReadTimeout:           o.ReadTimeout,
 		WriteTimeout:          o.WriteTimeout,
 		ContextTimeoutEnabled: o.ContextTimeoutEnabled,
+		Codec:                 o.Codec,
 
 		PoolFIFO:        o.PoolFIFO,
 		PoolSize:        o.PoolSize,
@@ -148,6 +158,7 @@ func (o *UniversalOptions) Failover() *FailoverOptions {
 		ConnMaxLifetime: o.ConnMaxLifetime,
 
 		TLSConfig: o.TLSConfig,
+		OnPush:    o.OnPush,
 	}
 }
 
@@ -177,6 +188,7 @@ func (o *UniversalOptions) Simple() *Options {
 		ReadTimeout:           o.ReadTimeout,
 		WriteTimeout:          o.WriteTimeout,
 		ContextTimeoutEnabled: o.ContextTimeoutEnabled,
+		Codec:                 o.Codec,
 
 		PoolFIFO:        o.PoolFIFO,
 		PoolSize:        o.PoolSize,
@@ -187,6 +199,7 @@ func (o *UniversalOptions) Simple() *Options {
 		ConnMaxLifetime: o.ConnMaxLifetime,
 
 		TLSConfig: o.TLSConfig,
+		OnPush:    o.OnPush,
 	}
 }
 
@@ -37,4 +37,12 @@ var _ = Describe("UniversalClient", func() {
 		})
 		Expect(client.Ping(ctx).Err()).NotTo(HaveOccurred())
 	})
+
+	It("should pass the codec to all clients", func() {
+		client = nil
+		opt := &redis.UniversalOptions{Codec: redis.JSONCodec{}}
+		Expect(opt.Simple().Codec).To(Equal(opt.Codec))
+		Expect(opt.Failover().Codec).To(Equal(opt.Codec))
+		Expect(opt.Cluster().Codec).To(Equal(opt.Codec))
+	})
 })
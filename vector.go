@@ -0,0 +1,272 @@
+package redis
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+type vectorCmdable interface {
+	FTVectorKNN(ctx context.Context, index string, query *FTVectorQuery) *FTVectorSearchCmd
+	FTVectorRange(ctx context.Context, index string, query *FTVectorQuery) *FTVectorSearchCmd
+}
+
+// VectorFP32 is a vector stored as a blob of little-endian float32 values,
+// which is the format of FLOAT32 vector fields. It can be used as a command argument,
+// e.g. in HSet, and as a Scan destination.
+type VectorFP32 []float32
+
+func (v VectorFP32) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(f))
+	}
+	return b, nil
+}
+
+func (v *VectorFP32) UnmarshalBinary(b []byte) error {
+	if len(b)%4 != 0 {
+		return fmt.Errorf("redis: invalid FLOAT32 vector blob of %d bytes", len(b))
+	}
+	vec := make(VectorFP32, len(b)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	*v = vec
+	return nil
+}
+
+// VectorFP64 is a vector stored as a blob of little-endian float64 values,
+// which is the format of FLOAT64 vector fields.
+type VectorFP64 []float64
+
+func (v VectorFP64) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(f))
+	}
+	return b, nil
+}
+
+func (v *VectorFP64) UnmarshalBinary(b []byte) error {
+	if len(b)%8 != 0 {
+		return fmt.Errorf("redis: invalid FLOAT64 vector blob of %d bytes", len(b))
+	}
+	vec := make(VectorFP64, len(b)/8)
+	for i := range vec {
+		vec[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:]))
+	}
+	*v = vec
+	return nil
+}
+
+// FTVectorQuery is a vector similarity query on the vector field.
+type FTVectorQuery struct {
+	// Vector field to search.
+	Field string
+	// Query vector: []float32, []float64, VectorFP32, VectorFP64 or the raw blob.
+	Vector interface{}
+	// Number of the nearest neighbors for KNN queries. For range queries,
+	// the maximum number of returned hits. Default is 10 hits.
+	K int
+	// Maximum distance of the hits for range queries.
+	Radius float64
+	// Query that filters the documents before the vector search. Default is "*".
+	Filter string
+	// Name of the distance field. Default is "__<Field>_score".
+	ScoreAs string
+	// Overrides EF_RUNTIME of HNSW fields.
+	EFRuntime int
+	// Fields to return. Default is all fields.
+	Return []string
+	// Parameters used by Filter.
+	Params map[string]interface{}
+}
+
+func (q *FTVectorQuery) scoreField() string {
+	if q.ScoreAs != "" {
+		return q.ScoreAs
+	}
+	return "__" + q.Field + "_score"
+}
+
+func (q *FTVectorQuery) limit() int {
+	if q.K > 0 {
+		return q.K
+	}
+	return 10
+}
+
+func (q *FTVectorQuery) searchOptions() *FTSearchOptions {
+	params := make(map[string]interface{}, len(q.Params)+2)
+	for k, v := range q.Params {
+		params[k] = v
+	}
+	switch vec := q.Vector.(type) {
+	case []float32:
+		params["vector"] = VectorFP32(vec)
+	case []float64:
+		params["vector"] = VectorFP64(vec)
+	default:
+		params["vector"] = vec
+	}
+
+	options := &FTSearchOptions{
+		SortBy:  []FTSearchSortBy{{FieldName: q.scoreField(), Asc: true}},
+		Limit:   q.limit(),
+		Params:  params,
+		Dialect: 2,
+	}
+	if len(q.Return) > 0 {
+		for _, field := range q.Return {
+			options.Return = append(options.Return, FTSearchReturn{FieldName: field})
+		}
+		options.Return = append(options.Return, FTSearchReturn{FieldName: q.scoreField()})
+	}
+	return options
+}
+
+func (q *FTVectorQuery) filter() string {
+	if q.Filter != "" {
+		return q.Filter
+	}
+	return "*"
+}
+
+// FTVectorKNN returns the K nearest neighbors of the query vector sorted by the distance.
+// For more information - https://redis.io/docs/interact/search-and-query/query/vector-search/
+func (c cmdable) FTVectorKNN(ctx context.Context, index string, query *FTVectorQuery) *FTVectorSearchCmd {
+	if query == nil {
+		return nilVectorQueryCmd(ctx, index)
+	}
+	options := query.searchOptions()
+
+	knn := "KNN " + strconv.Itoa(query.limit()) + " @" + query.Field + " $vector"
+	if query.EFRuntime > 0 {
+		knn += " EF_RUNTIME $ef_runtime"
+		options.Params["ef_runtime"] = query.EFRuntime
+	}
+	q := fmt.Sprintf("(%s)=>[%s AS %s]", query.filter(), knn, query.scoreField())
+
+	return c.ftVectorSearch(ctx, index, q, query, options)
+}
+
+// FTVectorRange returns the documents within Radius of the query vector sorted by the distance.
+// For more information - https://redis.io/docs/interact/search-and-query/query/vector-search/
+func (c cmdable) FTVectorRange(ctx context.Context, index string, query *FTVectorQuery) *FTVectorSearchCmd {
+	if query == nil {
+		return nilVectorQueryCmd(ctx, index)
+	}
+	options := query.searchOptions()
+	options.Params["radius"] = query.Radius
+
+	q := fmt.Sprintf("@%s:[VECTOR_RANGE $radius $vector]=>{$YIELD_DISTANCE_AS: %s}",
+		query.Field, query.scoreField())
+	if query.Filter != "" {
+		q = fmt.Sprintf("(%s) (%s)", q, query.Filter)
+	}
+
+	return c.ftVectorSearch(ctx, index, q, query, options)
+}
+
+func nilVectorQueryCmd(ctx context.Context, index string) *FTVectorSearchCmd {
+	cmd := NewFTVectorSearchCmd(ctx, "FT.SEARCH", index)
+	cmd.SetErr(errors.New("redis: vector query is nil"))
+	return cmd
+}
+
+func (c cmdable) ftVectorSearch(
+	ctx context.Context, index, q string, query *FTVectorQuery, options *FTSearchOptions,
+) *FTVectorSearchCmd {
+	args, err := ftSearchArgs(index, q, options)
+	cmd := NewFTVectorSearchCmd(ctx, args...)
+	cmd.scoreField = query.scoreField()
+	switch {
+	case err != nil:
+		cmd.SetErr(err)
+		return cmd
+	case query.Field == "" || query.Vector == nil:
+		cmd.SetErr(errors.New("redis: vector query requires Field and Vector"))
+		return cmd
+	}
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+//------------------------------------------------------------------------------
+
+// FTVectorHit is a document found by a vector query.
+type FTVectorHit struct {
+	ID       string
+	Distance float64
+	Fields   map[string]string
+}
+
+type FTVectorSearchResult struct {
+	Total int64
+	Hits  []FTVectorHit
+}
+
+type FTVectorSearchCmd struct {
+	baseCmd
+
+	val        FTVectorSearchResult
+	scoreField string
+}
+
+var _ Cmder = (*FTVectorSearchCmd)(nil)
+
+func NewFTVectorSearchCmd(ctx context.Context, args ...interface{}) *FTVectorSearchCmd {
+	return &FTVectorSearchCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *FTVectorSearchCmd) SetVal(val FTVectorSearchResult) {
+	cmd.val = val
+}
+
+func (cmd *FTVectorSearchCmd) Val() FTVectorSearchResult {
+	return cmd.val
+}
+
+func (cmd *FTVectorSearchCmd) Result() (FTVectorSearchResult, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *FTVectorSearchCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *FTVectorSearchCmd) readReply(rd *proto.Reader) error {
+	v, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	res, err := parseFTSearch(v, &FTSearchOptions{})
+	if err != nil {
+		return err
+	}
+
+	hits := make([]FTVectorHit, 0, len(res.Docs))
+	for _, doc := range res.Docs {
+		hit := FTVectorHit{ID: doc.ID, Fields: doc.Fields}
+		if score, ok := doc.Fields[cmd.scoreField]; ok {
+			if hit.Distance, err = strconv.ParseFloat(score, 64); err != nil {
+				return err
+			}
+			delete(hit.Fields, cmd.scoreField)
+		}
+		hits = append(hits, hit)
+	}
+	cmd.val = FTVectorSearchResult{Total: res.Total, Hits: hits}
+	return nil
+}
@@ -0,0 +1,135 @@
+package redis_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("Vector encoding", func() {
+	It("encodes FLOAT32 vectors", func() {
+		b, err := redis.VectorFP32{1, -2.5}.MarshalBinary()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b).To(Equal([]byte{0, 0, 0x80, 0x3f, 0, 0, 0x20, 0xc0}))
+
+		var v redis.VectorFP32
+		Expect(v.UnmarshalBinary(b)).NotTo(HaveOccurred())
+		Expect(v).To(Equal(redis.VectorFP32{1, -2.5}))
+
+		Expect(v.UnmarshalBinary(b[:3])).To(MatchError("redis: invalid FLOAT32 vector blob of 3 bytes"))
+	})
+
+	It("encodes FLOAT64 vectors", func() {
+		b, err := redis.VectorFP64{1, -2.5}.MarshalBinary()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b).To(HaveLen(16))
+
+		var v redis.VectorFP64
+		Expect(v.UnmarshalBinary(b)).NotTo(HaveOccurred())
+		Expect(v).To(Equal(redis.VectorFP64{1, -2.5}))
+	})
+})
+
+var _ = Describe("Vector query validation", func() {
+	It("returns an error for a nil query", func() {
+		client := redis.NewClient(&redis.Options{Addr: ":1"})
+		defer client.Close()
+
+		ctx := context.TODO()
+		Expect(client.FTVectorKNN(ctx, "idx", nil).Err()).To(MatchError("redis: vector query is nil"))
+		Expect(client.FTVectorRange(ctx, "idx", nil).Err()).To(MatchError("redis: vector query is nil"))
+	})
+})
+
+var _ = Describe("Vector search", Label("search", "vector"), func() {
+	ctx := context.TODO()
+
+	for _, protocol := range []int{2, 3} {
+		protocol := protocol
+
+		Describe(fmt.Sprintf("RESP%d", protocol), func() {
+			var client *redis.Client
+
+			BeforeEach(func() {
+				client = redis.NewClient(&redis.Options{Addr: ":6379", Protocol: protocol})
+				Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+				err := client.FTCreate(ctx, "vidx", &redis.FTCreateOptions{OnHash: true, Prefix: []string{"item:"}},
+					&redis.FieldSchema{FieldName: "tag", FieldType: redis.SearchFieldTypeTag},
+					&redis.FieldSchema{
+						FieldName: "embedding",
+						FieldType: redis.SearchFieldTypeVector,
+						VectorArgs: &redis.FTVectorArgs{HNSWOptions: &redis.FTHNSWOptions{
+							Type:           "FLOAT32",
+							Dim:            2,
+							DistanceMetric: "L2",
+						}},
+					},
+				).Err()
+				Expect(err).NotTo(HaveOccurred())
+
+				client.HSet(ctx, "item:1", "tag", "a", "embedding", redis.VectorFP32{0, 0})
+				client.HSet(ctx, "item:2", "tag", "b", "embedding", redis.VectorFP32{1, 0})
+				client.HSet(ctx, "item:3", "tag", "a", "embedding", redis.VectorFP32{3, 0})
+				waitForIndexing(client, "vidx")
+			})
+
+			AfterEach(func() {
+				Expect(client.Close()).NotTo(HaveOccurred())
+			})
+
+			It("should FTVectorKNN", func() {
+				res, err := client.FTVectorKNN(ctx, "vidx", &redis.FTVectorQuery{
+					Field:  "embedding",
+					Vector: []float32{0.9, 0},
+					K:      2,
+					Return: []string{"tag"},
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Hits).To(HaveLen(2))
+				Expect(res.Hits[0].ID).To(Equal("item:2"))
+				Expect(res.Hits[0].Distance).To(BeNumerically("~", 0.01, 1e-6))
+				Expect(res.Hits[0].Fields).To(Equal(map[string]string{"tag": "b"}))
+				Expect(res.Hits[1].ID).To(Equal("item:1"))
+			})
+
+			It("should FTVectorKNN with filter", func() {
+				res, err := client.FTVectorKNN(ctx, "vidx", &redis.FTVectorQuery{
+					Field:   "embedding",
+					Vector:  redis.VectorFP32{0.9, 0},
+					K:       1,
+					Filter:  "@tag:{$tag}",
+					Params:  map[string]interface{}{"tag": "a"},
+					ScoreAs: "dist",
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Hits).To(HaveLen(1))
+				Expect(res.Hits[0].ID).To(Equal("item:1"))
+				Expect(res.Hits[0].Fields).NotTo(HaveKey("dist"))
+			})
+
+			It("should FTVectorRange", func() {
+				res, err := client.FTVectorRange(ctx, "vidx", &redis.FTVectorQuery{
+					Field:  "embedding",
+					Vector: []float32{0, 0},
+					Radius: 2,
+				}).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Hits).To(HaveLen(2))
+				Expect(res.Hits[0].ID).To(Equal("item:1"))
+				Expect(res.Hits[0].Distance).To(BeZero())
+				Expect(res.Hits[1].ID).To(Equal("item:2"))
+			})
+
+			It("should scan vectors", func() {
+				var v redis.VectorFP32
+				Expect(client.HGet(ctx, "item:2", "embedding").Scan(&v)).NotTo(HaveOccurred())
+				Expect(v).To(Equal(redis.VectorFP32{1, 0}))
+			})
+		})
+	}
+})